@@ -1,11 +1,15 @@
 package constants
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"math"
 	"os"
 	"strconv"
 	"time"
+
+	"github.com/clockworklabs/Blackholio/server-go/types"
 )
 
 // Core Game Constants
@@ -14,105 +18,266 @@ import (
 
 const (
 	// Player Constants
-	START_PLAYER_MASS  uint32 = 15 // Starting mass for new players
-	START_PLAYER_SPEED uint32 = 10 // Base player speed
+	START_PLAYER_MASS          uint32 = 15 // Starting mass for new players
+	START_PLAYER_SPEED         uint32 = 10 // Base player speed
+	START_PLAYER_MASS_OVERRIDE uint32 = 0  // Mass EnterGame and Respawn spawn circles with (0 = START_PLAYER_MASS)
+	MIN_EFFECTIVE_MASS         uint32 = 1  // Smallest mass used by the radius and speed formulas
+	MAX_CIRCLE_MASS            uint32 = 0  // Largest mass a single circle can reach by consuming (0 = uncapped)
+
+	// Movement Constants
+	MIN_MOVE_SPEED    float32 = 1.0 // Floor on movement speed so the largest circles remain playable
+	MAX_MOVE_PER_TICK float32 = 0.0 // Farthest an entity moves in one MOVE_PLAYERS_INTERVAL (0 = twice START_PLAYER_SPEED per second)
+
+	// Decay Constants
+	DECAY_GRACE_PERIOD_SEC       float32 = 0.0 // Minimum circle age before decay applies (seconds)
+	DECAY_EXEMPT_LEADER_FRACTION float32 = 0.0 // Circles lighter than this fraction of the largest mass don't decay (0 = disabled)
+	CAMPING_THRESHOLD_SEC        float32 = 0.0 // Circles that haven't moved for this long decay faster (0 = disabled)
+	CAMPING_DECAY_MULTIPLIER     float32 = 3.0 // How many times the normal decay a camping circle loses per decay tick
 
 	// Food Constants
-	FOOD_MASS_MIN     uint32 = 2   // Minimum mass for spawned food
-	FOOD_MASS_MAX     uint32 = 4   // Maximum mass for spawned food
-	TARGET_FOOD_COUNT uint32 = 600 // Target number of food entities to maintain
+	FOOD_MASS_MIN         uint32  = 2     // Minimum mass for spawned food
+	FOOD_MASS_MAX         uint32  = 4     // Maximum mass for spawned food
+	TARGET_FOOD_COUNT     uint32  = 600   // Target number of food entities to maintain
+	INITIAL_FOOD_BURST    uint32  = 600   // Food spawned at once when the first player enters an empty world
+	FOOD_PER_PLAYER       uint32  = 0     // Food to maintain per active player when that exceeds the target (0 = fixed target)
+	MIN_FOOD_SPACING      float32 = 0     // Minimum distance between the centers of spawned food (0 = no spacing)
+	FOOD_SPACING_ATTEMPTS         = 8     // Candidate positions sampled when looking for a spaced food spawn
+	FOOD_SPAWN_GRID_CELL  float32 = 50    // Cell size of the food spawn grid when MIN_FOOD_SPACING doesn't set one
+	FOOD_AVOID_CIRCLES            = false // Resample food spawns that would land inside a circle
+	FOOD_MASS_MULTIPLIER  float32 = 1.0   // Scales the mass a circle gains from eating food
+
+	// Food Magnet Constants
+	FOOD_MAGNET_MIN_MASS uint32  = 500  // Circles at or above this mass attract nearby food
+	FOOD_MAGNET_RADIUS   float32 = 50.0 // Distance from a circle's center within which food is attracted
+	FOOD_MAGNET_STRENGTH float32 = 0.0  // Pull speed (units per second) on food at the circle's center, 0 disables
 
 	// Collision and Consumption Constants
-	MINIMUM_SAFE_MASS_RATIO    float32 = 0.85 // Minimum mass ratio to safely consume another entity
-	MIN_OVERLAP_PCT_TO_CONSUME float32 = 0.1  // Minimum overlap percentage required to consume
+	MINIMUM_SAFE_MASS_RATIO    float32 = 0.85  // Minimum mass ratio to safely consume another entity
+	MIN_OVERLAP_PCT_TO_CONSUME float32 = 0.1   // Minimum overlap percentage required to consume
+	RESOLVE_CIRCLE_OVERLAPS            = false // Push apart overlapping circles of different players when neither can consume
+	SPLIT_MASS_OVERFLOW                = false // Mass consumed past MAX_CIRCLE_MASS spawns a new circle instead of being discarded
+	TEAM_MERGE_ALLOWED                 = false // Let a circle absorb a teammate's circle once it covers the teammate's center
+	STRICT_CONSUMPTION                 = false // Consume anything strictly lighter, ignoring MINIMUM_SAFE_MASS_RATIO
 
 	// Split Mechanics Constants
 	MIN_MASS_TO_SPLIT                    uint32  = START_PLAYER_MASS * 2 // 30 - Minimum mass required to split
 	MAX_CIRCLES_PER_PLAYER               uint32  = 16                    // Maximum circles a player can have
+	CIRCLE_CAP_MODEL                             = CircleCapFlat         // How the circle cap is derived from MAX_CIRCLES_PER_PLAYER
+	CIRCLE_CAP_MASS_PER_CIRCLE           uint32  = MIN_MASS_TO_SPLIT     // Total mass per allowed circle under CircleCapMassScaled
 	SPLIT_RECOMBINE_DELAY_SEC            float32 = 5.0                   // Delay before circles can recombine (seconds)
 	SPLIT_GRAV_PULL_BEFORE_RECOMBINE_SEC float32 = 2.0                   // Time before recombine when gravity starts (seconds)
 	ALLOWED_SPLIT_CIRCLE_OVERLAP_PCT     float32 = 0.9                   // Allowed overlap percentage between split circles
 	SELF_COLLISION_SPEED                 float32 = 0.05                  // Speed multiplier for circle separation (1.0 = instant)
+	MAX_SELF_COLLISION_SPEED             float32 = 0.2                   // Separation speed multiplier reached at full overlap depth
+	RECOMBINE_MAX_DISTANCE               float32 = 0                     // Largest gap between circle edges that can recombine (0 = any distance)
+	MAX_RECOMBINE_ATTEMPTS               uint32  = 20                    // Recombine retries before the closest circles are force-merged (0 = never force)
+	RECOMBINE_AT_CENTER_OF_MASS          bool    = false                 // Merged circles of the same player settle at their mass-weighted midpoint
 
 	// World Configuration Constants
-	DEFAULT_WORLD_SIZE uint64 = 1000 // Default world size for initialization
+	DEFAULT_WORLD_SIZE uint64 = 1000   // Default world size for initialization
+	MIN_WORLD_SIZE     uint64 = 100    // Smallest supported world size
+	MAX_WORLD_SIZE     uint64 = 100000 // Largest supported world size
+
+	// Player Spawn Constants
+	SPAWN_SAFE_ATTEMPTS                  = 16    // Candidate positions sampled when looking for a safe spawn
+	SPAWN_DENSITY_GRID_SIZE              = 2     // Cells per side of the coarse grid used by density-aware spawning
+	SPAWN_DENSITY_AWARE                  = false // Prefer the least populated region of the world when spawning players
+	SPAWN_PROTECTION_SEC         float32 = 0.0   // How long newly spawned player circles can't consume or be consumed (seconds)
+	MARK_DEAD_PLAYERS                    = false // Flag players as dead when their last circle is consumed
+	REQUIRE_UNIQUE_NAMES                 = false // Reject entering the game with a name another active player uses (case-insensitive)
+	SPAWN_WALL_PADDING           float32 = 0.0   // Extra distance beyond their radius that spawned food and circles keep from the walls
+	REJECT_INPUT_WITHOUT_CIRCLES         = false // Answer input from players with no circles with INVALID_STATE instead of ignoring it
+	RECONNECT_GRACE_SEC          float32 = 0.0   // Returning players skip the player cap only within this long of disconnecting, 0 always skips (seconds)
 
 	// Timer Intervals (converted to Go durations)
 	CIRCLE_DECAY_INTERVAL = 5 * time.Second        // Circle decay timer interval
 	SPAWN_FOOD_INTERVAL   = 500 * time.Millisecond // Food spawning timer interval
 	MOVE_PLAYERS_INTERVAL = 50 * time.Millisecond  // Player movement timer interval
+	PHYSICS_TICK_HZ       = 0                      // Physics substeps per second, at least one per MOVE_PLAYERS_INTERVAL (0 = one substep per interval)
+	CONSUME_DELAY         = 0 * time.Millisecond   // Delay before a scheduled consume runs, giving clients time to animate
+	MAX_INPUT_CLOCK_DRIFT = 0 * time.Millisecond   // Reject inputs whose timestamp is further than this from the server clock (0 disables)
+	FOOD_TTL              = 0 * time.Second        // Uneaten food older than this is despawned by SpawnFood (0 disables)
+	SKIP_IDLE_TICKS       = true                   // MoveAllPlayers and CircleDecay do nothing while no players are connected
+
+	// Stale Player Cleanup Constants
+	STALE_PLAYER_TTL               = 5 * time.Minute  // Players not seen for this long are logged out
+	CLEANUP_STALE_PLAYERS_INTERVAL = 30 * time.Second // Stale player cleanup timer interval
+
+	// Visual Constants
+	GROWTH_PULSE_DURATION         = 300 * time.Millisecond // How long a circle's visual radius pulses after it grows
+	GROWTH_PULSE_SCALE    float32 = 0.1                    // Extra visual radius when the pulse starts, as a fraction of the base radius
+)
+
+// CircleCapModel selects how many circles a player may split into
+type CircleCapModel string
+
+const (
+	// CircleCapFlat allows every player MaxCirclesPerPlayer circles
+	CircleCapFlat CircleCapModel = "flat"
+
+	// CircleCapMassScaled allows one circle per CircleCapMassPerCircle of the player's
+	// total mass, with MaxCirclesPerPlayer as the hard ceiling
+	CircleCapMassScaled CircleCapModel = "mass_scaled"
 )
 
 // Configuration holds all configurable game parameters
 // This allows for runtime configuration via environment variables
 type Configuration struct {
 	// Core Game Settings
-	StartPlayerMass  uint32 `json:"start_player_mass"`
-	StartPlayerSpeed uint32 `json:"start_player_speed"`
-	FoodMassMin      uint32 `json:"food_mass_min"`
-	FoodMassMax      uint32 `json:"food_mass_max"`
-	TargetFoodCount  uint32 `json:"target_food_count"`
+	StartPlayerMass         uint32  `json:"start_player_mass"`
+	StartPlayerSpeed        uint32  `json:"start_player_speed"`
+	StartPlayerMassOverride uint32  `json:"start_player_mass_override"`
+	FoodMassMin             uint32  `json:"food_mass_min"`
+	FoodMassMax             uint32  `json:"food_mass_max"`
+	TargetFoodCount         uint32  `json:"target_food_count"`
+	InitialFoodBurst        uint32  `json:"initial_food_burst"`
+	FoodPerPlayer           uint32  `json:"food_per_player"`
+	MinFoodSpacing          float32 `json:"min_food_spacing"`
+	FoodMassMultiplier      float32 `json:"food_mass_multiplier"`
+	FoodAvoidCircles        bool    `json:"food_avoid_circles"`
+
+	// Food Magnet Settings
+	FoodMagnetMinMass  uint32  `json:"food_magnet_min_mass"`
+	FoodMagnetRadius   float32 `json:"food_magnet_radius"`
+	FoodMagnetStrength float32 `json:"food_magnet_strength"`
 
 	// Physics Settings
-	MinimumSafeMassRatio   float32 `json:"minimum_safe_mass_ratio"`
-	MinOverlapPctToConsume float32 `json:"min_overlap_pct_to_consume"`
+	MinimumSafeMassRatio      float32 `json:"minimum_safe_mass_ratio"`
+	MinOverlapPctToConsume    float32 `json:"min_overlap_pct_to_consume"`
+	MinMoveSpeed              float32 `json:"min_move_speed"`
+	MaxMovePerTick            float32 `json:"max_move_per_tick"`
+	DecayGracePeriodSec       float32 `json:"decay_grace_period_sec"`
+	MaxCircleMass             uint32  `json:"max_circle_mass"`
+	SplitMassOverflow         bool    `json:"split_mass_overflow"`
+	DecayExemptLeaderFraction float32 `json:"decay_exempt_leader_fraction"`
+	CampingThresholdSec       float32 `json:"camping_threshold_sec"`
+	CampingDecayMultiplier    float32 `json:"camping_decay_multiplier"`
+	ResolveCircleOverlaps     bool    `json:"resolve_circle_overlaps"`
+	TeamMergeAllowed          bool    `json:"team_merge_allowed"`
+	StrictConsumption         bool    `json:"strict_consumption"`
 
 	// Split Mechanics Settings
-	MinMassToSplit                  uint32  `json:"min_mass_to_split"`
-	MaxCirclesPerPlayer             uint32  `json:"max_circles_per_player"`
-	SplitRecombineDelaySec          float32 `json:"split_recombine_delay_sec"`
-	SplitGravPullBeforeRecombineSec float32 `json:"split_grav_pull_before_recombine_sec"`
-	AllowedSplitCircleOverlapPct    float32 `json:"allowed_split_circle_overlap_pct"`
-	SelfCollisionSpeed              float32 `json:"self_collision_speed"`
+	MinMassToSplit                  uint32         `json:"min_mass_to_split"`
+	MaxCirclesPerPlayer             uint32         `json:"max_circles_per_player"`
+	CircleCapModel                  CircleCapModel `json:"circle_cap_model"`
+	CircleCapMassPerCircle          uint32         `json:"circle_cap_mass_per_circle"`
+	SplitRecombineDelaySec          float32        `json:"split_recombine_delay_sec"`
+	SplitGravPullBeforeRecombineSec float32        `json:"split_grav_pull_before_recombine_sec"`
+	AllowedSplitCircleOverlapPct    float32        `json:"allowed_split_circle_overlap_pct"`
+	SelfCollisionSpeed              float32        `json:"self_collision_speed"`
+	MaxSelfCollisionSpeed           float32        `json:"max_self_collision_speed"`
+	RecombineMaxDistance            float32        `json:"recombine_max_distance"`
+	MaxRecombineAttempts            uint32         `json:"max_recombine_attempts"`
+	RecombineAtCenterOfMass         bool           `json:"recombine_at_center_of_mass"`
 
 	// World Settings
-	DefaultWorldSize uint64 `json:"default_world_size"`
+	DefaultWorldSize          uint64  `json:"default_world_size"`
+	SpawnDensityAware         bool    `json:"spawn_density_aware"`
+	SpawnProtectionSec        float32 `json:"spawn_protection_sec"`
+	MarkDeadPlayers           bool    `json:"mark_dead_players"`
+	RequireUniqueNames        bool    `json:"require_unique_names"`
+	SpawnWallPadding          float32 `json:"spawn_wall_padding"`
+	RejectInputWithoutCircles bool    `json:"reject_input_without_circles"`
+	ReconnectGraceSec         float32 `json:"reconnect_grace_sec"`
 
 	// Timer Settings
 	CircleDecayInterval time.Duration `json:"circle_decay_interval"`
 	SpawnFoodInterval   time.Duration `json:"spawn_food_interval"`
 	MovePlayersInterval time.Duration `json:"move_players_interval"`
+	PhysicsTickHz       uint32        `json:"physics_tick_hz"`
+	StalePlayerTTL      time.Duration `json:"stale_player_ttl"`
+	ConsumeDelay        time.Duration `json:"consume_delay"`
+	MaxInputClockDrift  time.Duration `json:"max_input_clock_drift"`
+	FoodTTL             time.Duration `json:"food_ttl"`
+	SkipIdleTicks       bool          `json:"skip_idle_ticks"`
 
 	// Performance Settings
-	EnablePerformanceLogging bool   `json:"enable_performance_logging"`
-	MaxConcurrentPlayers     uint32 `json:"max_concurrent_players"`
-	EnableDebugMode          bool   `json:"enable_debug_mode"`
+	EnablePerformanceLogging  bool          `json:"enable_performance_logging"`
+	MaxConcurrentPlayers      uint32        `json:"max_concurrent_players"`
+	MaxCollisionChecksPerTick uint32        `json:"max_collision_checks_per_tick"`
+	EnableDebugMode           bool          `json:"enable_debug_mode"`
+	SafeArithmetic            bool          `json:"safe_arithmetic"`
+	SlowReducerThreshold      time.Duration `json:"slow_reducer_threshold"`
 }
 
 // DefaultConfiguration returns a Configuration with all default values
 func DefaultConfiguration() *Configuration {
 	return &Configuration{
 		// Core Game Settings
-		StartPlayerMass:  START_PLAYER_MASS,
-		StartPlayerSpeed: START_PLAYER_SPEED,
-		FoodMassMin:      FOOD_MASS_MIN,
-		FoodMassMax:      FOOD_MASS_MAX,
-		TargetFoodCount:  TARGET_FOOD_COUNT,
+		StartPlayerMass:         START_PLAYER_MASS,
+		StartPlayerSpeed:        START_PLAYER_SPEED,
+		StartPlayerMassOverride: START_PLAYER_MASS_OVERRIDE,
+		FoodMassMin:             FOOD_MASS_MIN,
+		FoodMassMax:             FOOD_MASS_MAX,
+		TargetFoodCount:         TARGET_FOOD_COUNT,
+		InitialFoodBurst:        INITIAL_FOOD_BURST,
+		FoodPerPlayer:           FOOD_PER_PLAYER,
+		MinFoodSpacing:          MIN_FOOD_SPACING,
+		FoodMassMultiplier:      FOOD_MASS_MULTIPLIER,
+		FoodAvoidCircles:        FOOD_AVOID_CIRCLES,
+
+		// Food Magnet Settings
+		FoodMagnetMinMass:  FOOD_MAGNET_MIN_MASS,
+		FoodMagnetRadius:   FOOD_MAGNET_RADIUS,
+		FoodMagnetStrength: FOOD_MAGNET_STRENGTH,
 
 		// Physics Settings
-		MinimumSafeMassRatio:   MINIMUM_SAFE_MASS_RATIO,
-		MinOverlapPctToConsume: MIN_OVERLAP_PCT_TO_CONSUME,
+		MinimumSafeMassRatio:      MINIMUM_SAFE_MASS_RATIO,
+		MinOverlapPctToConsume:    MIN_OVERLAP_PCT_TO_CONSUME,
+		MinMoveSpeed:              MIN_MOVE_SPEED,
+		MaxMovePerTick:            MAX_MOVE_PER_TICK,
+		DecayGracePeriodSec:       DECAY_GRACE_PERIOD_SEC,
+		MaxCircleMass:             MAX_CIRCLE_MASS,
+		SplitMassOverflow:         SPLIT_MASS_OVERFLOW,
+		DecayExemptLeaderFraction: DECAY_EXEMPT_LEADER_FRACTION,
+		CampingThresholdSec:       CAMPING_THRESHOLD_SEC,
+		CampingDecayMultiplier:    CAMPING_DECAY_MULTIPLIER,
+		ResolveCircleOverlaps:     RESOLVE_CIRCLE_OVERLAPS,
+		TeamMergeAllowed:          TEAM_MERGE_ALLOWED,
+		StrictConsumption:         STRICT_CONSUMPTION,
 
 		// Split Mechanics Settings
 		MinMassToSplit:                  MIN_MASS_TO_SPLIT,
 		MaxCirclesPerPlayer:             MAX_CIRCLES_PER_PLAYER,
+		CircleCapModel:                  CIRCLE_CAP_MODEL,
+		CircleCapMassPerCircle:          CIRCLE_CAP_MASS_PER_CIRCLE,
 		SplitRecombineDelaySec:          SPLIT_RECOMBINE_DELAY_SEC,
 		SplitGravPullBeforeRecombineSec: SPLIT_GRAV_PULL_BEFORE_RECOMBINE_SEC,
 		AllowedSplitCircleOverlapPct:    ALLOWED_SPLIT_CIRCLE_OVERLAP_PCT,
 		SelfCollisionSpeed:              SELF_COLLISION_SPEED,
+		MaxSelfCollisionSpeed:           MAX_SELF_COLLISION_SPEED,
+		RecombineMaxDistance:            RECOMBINE_MAX_DISTANCE,
+		MaxRecombineAttempts:            MAX_RECOMBINE_ATTEMPTS,
+		RecombineAtCenterOfMass:         RECOMBINE_AT_CENTER_OF_MASS,
 
 		// World Settings
-		DefaultWorldSize: DEFAULT_WORLD_SIZE,
+		DefaultWorldSize:          DEFAULT_WORLD_SIZE,
+		SpawnDensityAware:         SPAWN_DENSITY_AWARE,
+		SpawnProtectionSec:        SPAWN_PROTECTION_SEC,
+		MarkDeadPlayers:           MARK_DEAD_PLAYERS,
+		RequireUniqueNames:        REQUIRE_UNIQUE_NAMES,
+		SpawnWallPadding:          SPAWN_WALL_PADDING,
+		RejectInputWithoutCircles: REJECT_INPUT_WITHOUT_CIRCLES,
+		ReconnectGraceSec:         RECONNECT_GRACE_SEC,
 
 		// Timer Settings
 		CircleDecayInterval: CIRCLE_DECAY_INTERVAL,
 		SpawnFoodInterval:   SPAWN_FOOD_INTERVAL,
 		MovePlayersInterval: MOVE_PLAYERS_INTERVAL,
+		PhysicsTickHz:       PHYSICS_TICK_HZ,
+		StalePlayerTTL:      STALE_PLAYER_TTL,
+		ConsumeDelay:        CONSUME_DELAY,
+		MaxInputClockDrift:  MAX_INPUT_CLOCK_DRIFT,
+		FoodTTL:             FOOD_TTL,
+		SkipIdleTicks:       SKIP_IDLE_TICKS,
 
 		// Performance Settings
-		EnablePerformanceLogging: false,
-		MaxConcurrentPlayers:     1000,
-		EnableDebugMode:          false,
+		EnablePerformanceLogging:  false,
+		MaxConcurrentPlayers:      1000,
+		MaxCollisionChecksPerTick: 250000,
+		EnableDebugMode:           false,
+		SafeArithmetic:            false,
+		SlowReducerThreshold:      0,
 	}
 }
 
@@ -184,6 +349,9 @@ func (c *Configuration) LoadFromEnvironment() error {
 	if c.StartPlayerSpeed, err = getEnvUint32("BLACKHOLIO_START_PLAYER_SPEED", c.StartPlayerSpeed); err != nil {
 		return err
 	}
+	if c.StartPlayerMassOverride, err = getEnvUint32("BLACKHOLIO_START_PLAYER_MASS_OVERRIDE", c.StartPlayerMassOverride); err != nil {
+		return err
+	}
 	if c.FoodMassMin, err = getEnvUint32("BLACKHOLIO_FOOD_MASS_MIN", c.FoodMassMin); err != nil {
 		return err
 	}
@@ -193,6 +361,32 @@ func (c *Configuration) LoadFromEnvironment() error {
 	if c.TargetFoodCount, err = getEnvUint32("BLACKHOLIO_TARGET_FOOD_COUNT", c.TargetFoodCount); err != nil {
 		return err
 	}
+	if c.InitialFoodBurst, err = getEnvUint32("BLACKHOLIO_INITIAL_FOOD_BURST", c.InitialFoodBurst); err != nil {
+		return err
+	}
+	if c.FoodPerPlayer, err = getEnvUint32("BLACKHOLIO_FOOD_PER_PLAYER", c.FoodPerPlayer); err != nil {
+		return err
+	}
+	if c.MinFoodSpacing, err = getEnvFloat32("BLACKHOLIO_MIN_FOOD_SPACING", c.MinFoodSpacing); err != nil {
+		return err
+	}
+	if c.FoodMassMultiplier, err = getEnvFloat32("BLACKHOLIO_FOOD_MASS_MULTIPLIER", c.FoodMassMultiplier); err != nil {
+		return err
+	}
+	if c.FoodAvoidCircles, err = getEnvBool("BLACKHOLIO_FOOD_AVOID_CIRCLES", c.FoodAvoidCircles); err != nil {
+		return err
+	}
+
+	// Load food magnet settings
+	if c.FoodMagnetMinMass, err = getEnvUint32("BLACKHOLIO_FOOD_MAGNET_MIN_MASS", c.FoodMagnetMinMass); err != nil {
+		return err
+	}
+	if c.FoodMagnetRadius, err = getEnvFloat32("BLACKHOLIO_FOOD_MAGNET_RADIUS", c.FoodMagnetRadius); err != nil {
+		return err
+	}
+	if c.FoodMagnetStrength, err = getEnvFloat32("BLACKHOLIO_FOOD_MAGNET_STRENGTH", c.FoodMagnetStrength); err != nil {
+		return err
+	}
 
 	// Load physics settings
 	if c.MinimumSafeMassRatio, err = getEnvFloat32("BLACKHOLIO_MINIMUM_SAFE_MASS_RATIO", c.MinimumSafeMassRatio); err != nil {
@@ -201,11 +395,50 @@ func (c *Configuration) LoadFromEnvironment() error {
 	if c.MinOverlapPctToConsume, err = getEnvFloat32("BLACKHOLIO_MIN_OVERLAP_PCT_TO_CONSUME", c.MinOverlapPctToConsume); err != nil {
 		return err
 	}
+	if c.MinMoveSpeed, err = getEnvFloat32("BLACKHOLIO_MIN_MOVE_SPEED", c.MinMoveSpeed); err != nil {
+		return err
+	}
+	if c.MaxMovePerTick, err = getEnvFloat32("BLACKHOLIO_MAX_MOVE_PER_TICK", c.MaxMovePerTick); err != nil {
+		return err
+	}
+	if c.DecayGracePeriodSec, err = getEnvFloat32("BLACKHOLIO_DECAY_GRACE_PERIOD_SEC", c.DecayGracePeriodSec); err != nil {
+		return err
+	}
+	if c.MaxCircleMass, err = getEnvUint32("BLACKHOLIO_MAX_CIRCLE_MASS", c.MaxCircleMass); err != nil {
+		return err
+	}
+	if c.SplitMassOverflow, err = getEnvBool("BLACKHOLIO_SPLIT_MASS_OVERFLOW", c.SplitMassOverflow); err != nil {
+		return err
+	}
+	if c.DecayExemptLeaderFraction, err = getEnvFloat32("BLACKHOLIO_DECAY_EXEMPT_LEADER_FRACTION", c.DecayExemptLeaderFraction); err != nil {
+		return err
+	}
+	if c.CampingThresholdSec, err = getEnvFloat32("BLACKHOLIO_CAMPING_THRESHOLD_SEC", c.CampingThresholdSec); err != nil {
+		return err
+	}
+	if c.CampingDecayMultiplier, err = getEnvFloat32("BLACKHOLIO_CAMPING_DECAY_MULTIPLIER", c.CampingDecayMultiplier); err != nil {
+		return err
+	}
+	if c.ResolveCircleOverlaps, err = getEnvBool("BLACKHOLIO_RESOLVE_CIRCLE_OVERLAPS", c.ResolveCircleOverlaps); err != nil {
+		return err
+	}
+	if c.TeamMergeAllowed, err = getEnvBool("BLACKHOLIO_TEAM_MERGE_ALLOWED", c.TeamMergeAllowed); err != nil {
+		return err
+	}
+	if c.StrictConsumption, err = getEnvBool("BLACKHOLIO_STRICT_CONSUMPTION", c.StrictConsumption); err != nil {
+		return err
+	}
 
 	// Load split mechanics settings
 	if c.MaxCirclesPerPlayer, err = getEnvUint32("BLACKHOLIO_MAX_CIRCLES_PER_PLAYER", c.MaxCirclesPerPlayer); err != nil {
 		return err
 	}
+	if val := os.Getenv("BLACKHOLIO_CIRCLE_CAP_MODEL"); val != "" {
+		c.CircleCapModel = CircleCapModel(val)
+	}
+	if c.CircleCapMassPerCircle, err = getEnvUint32("BLACKHOLIO_CIRCLE_CAP_MASS_PER_CIRCLE", c.CircleCapMassPerCircle); err != nil {
+		return err
+	}
 	if c.SplitRecombineDelaySec, err = getEnvFloat32("BLACKHOLIO_SPLIT_RECOMBINE_DELAY_SEC", c.SplitRecombineDelaySec); err != nil {
 		return err
 	}
@@ -218,11 +451,44 @@ func (c *Configuration) LoadFromEnvironment() error {
 	if c.SelfCollisionSpeed, err = getEnvFloat32("BLACKHOLIO_SELF_COLLISION_SPEED", c.SelfCollisionSpeed); err != nil {
 		return err
 	}
+	if c.MaxSelfCollisionSpeed, err = getEnvFloat32("BLACKHOLIO_MAX_SELF_COLLISION_SPEED", c.MaxSelfCollisionSpeed); err != nil {
+		return err
+	}
+	if c.RecombineMaxDistance, err = getEnvFloat32("BLACKHOLIO_RECOMBINE_MAX_DISTANCE", c.RecombineMaxDistance); err != nil {
+		return err
+	}
+	if c.MaxRecombineAttempts, err = getEnvUint32("BLACKHOLIO_MAX_RECOMBINE_ATTEMPTS", c.MaxRecombineAttempts); err != nil {
+		return err
+	}
+	if c.RecombineAtCenterOfMass, err = getEnvBool("BLACKHOLIO_RECOMBINE_AT_CENTER_OF_MASS", c.RecombineAtCenterOfMass); err != nil {
+		return err
+	}
 
 	// Load world settings
 	if c.DefaultWorldSize, err = getEnvUint64("BLACKHOLIO_DEFAULT_WORLD_SIZE", c.DefaultWorldSize); err != nil {
 		return err
 	}
+	if c.SpawnDensityAware, err = getEnvBool("BLACKHOLIO_SPAWN_DENSITY_AWARE", c.SpawnDensityAware); err != nil {
+		return err
+	}
+	if c.SpawnProtectionSec, err = getEnvFloat32("BLACKHOLIO_SPAWN_PROTECTION_SEC", c.SpawnProtectionSec); err != nil {
+		return err
+	}
+	if c.MarkDeadPlayers, err = getEnvBool("BLACKHOLIO_MARK_DEAD_PLAYERS", c.MarkDeadPlayers); err != nil {
+		return err
+	}
+	if c.RequireUniqueNames, err = getEnvBool("BLACKHOLIO_REQUIRE_UNIQUE_NAMES", c.RequireUniqueNames); err != nil {
+		return err
+	}
+	if c.SpawnWallPadding, err = getEnvFloat32("BLACKHOLIO_SPAWN_WALL_PADDING", c.SpawnWallPadding); err != nil {
+		return err
+	}
+	if c.RejectInputWithoutCircles, err = getEnvBool("BLACKHOLIO_REJECT_INPUT_WITHOUT_CIRCLES", c.RejectInputWithoutCircles); err != nil {
+		return err
+	}
+	if c.ReconnectGraceSec, err = getEnvFloat32("BLACKHOLIO_RECONNECT_GRACE_SEC", c.ReconnectGraceSec); err != nil {
+		return err
+	}
 
 	// Load timer settings
 	if c.CircleDecayInterval, err = getEnvDuration("BLACKHOLIO_CIRCLE_DECAY_INTERVAL", c.CircleDecayInterval); err != nil {
@@ -234,6 +500,24 @@ func (c *Configuration) LoadFromEnvironment() error {
 	if c.MovePlayersInterval, err = getEnvDuration("BLACKHOLIO_MOVE_PLAYERS_INTERVAL", c.MovePlayersInterval); err != nil {
 		return err
 	}
+	if c.PhysicsTickHz, err = getEnvUint32("BLACKHOLIO_PHYSICS_TICK_HZ", c.PhysicsTickHz); err != nil {
+		return err
+	}
+	if c.StalePlayerTTL, err = getEnvDuration("BLACKHOLIO_STALE_PLAYER_TTL", c.StalePlayerTTL); err != nil {
+		return err
+	}
+	if c.ConsumeDelay, err = getEnvDuration("BLACKHOLIO_CONSUME_DELAY", c.ConsumeDelay); err != nil {
+		return err
+	}
+	if c.MaxInputClockDrift, err = getEnvDuration("BLACKHOLIO_MAX_INPUT_CLOCK_DRIFT", c.MaxInputClockDrift); err != nil {
+		return err
+	}
+	if c.FoodTTL, err = getEnvDuration("BLACKHOLIO_FOOD_TTL", c.FoodTTL); err != nil {
+		return err
+	}
+	if c.SkipIdleTicks, err = getEnvBool("BLACKHOLIO_SKIP_IDLE_TICKS", c.SkipIdleTicks); err != nil {
+		return err
+	}
 
 	// Load performance settings
 	if c.EnablePerformanceLogging, err = getEnvBool("BLACKHOLIO_ENABLE_PERFORMANCE_LOGGING", c.EnablePerformanceLogging); err != nil {
@@ -242,9 +526,18 @@ func (c *Configuration) LoadFromEnvironment() error {
 	if c.MaxConcurrentPlayers, err = getEnvUint32("BLACKHOLIO_MAX_CONCURRENT_PLAYERS", c.MaxConcurrentPlayers); err != nil {
 		return err
 	}
+	if c.MaxCollisionChecksPerTick, err = getEnvUint32("BLACKHOLIO_MAX_COLLISION_CHECKS_PER_TICK", c.MaxCollisionChecksPerTick); err != nil {
+		return err
+	}
 	if c.EnableDebugMode, err = getEnvBool("BLACKHOLIO_ENABLE_DEBUG_MODE", c.EnableDebugMode); err != nil {
 		return err
 	}
+	if c.SafeArithmetic, err = getEnvBool("BLACKHOLIO_SAFE_ARITHMETIC", c.SafeArithmetic); err != nil {
+		return err
+	}
+	if c.SlowReducerThreshold, err = getEnvDuration("BLACKHOLIO_SLOW_REDUCER_THRESHOLD", c.SlowReducerThreshold); err != nil {
+		return err
+	}
 
 	// Recalculate derived values
 	c.MinMassToSplit = c.StartPlayerMass * 2
@@ -261,6 +554,10 @@ func (c *Configuration) Validate() error {
 	if c.StartPlayerSpeed == 0 {
 		return fmt.Errorf("start_player_speed must be greater than 0")
 	}
+	if c.StartPlayerMassOverride != 0 && 2*(MassToRadius(c.StartPlayerMassOverride)+c.SpawnWallPadding) >= float32(c.DefaultWorldSize) {
+		return fmt.Errorf("start_player_mass_override must be 0 or small enough for a circle to fit in default_world_size (%d), got %d",
+			c.DefaultWorldSize, c.StartPlayerMassOverride)
+	}
 	if c.FoodMassMin == 0 {
 		return fmt.Errorf("food_mass_min must be greater than 0")
 	}
@@ -270,6 +567,20 @@ func (c *Configuration) Validate() error {
 	if c.TargetFoodCount == 0 {
 		return fmt.Errorf("target_food_count must be greater than 0")
 	}
+	if c.MinFoodSpacing < 0 {
+		return fmt.Errorf("min_food_spacing must be non-negative, got %f", c.MinFoodSpacing)
+	}
+	if c.FoodMassMultiplier < 0 {
+		return fmt.Errorf("food_mass_multiplier must be non-negative, got %f", c.FoodMassMultiplier)
+	}
+
+	// Validate food magnet settings
+	if c.FoodMagnetRadius < 0 {
+		return fmt.Errorf("food_magnet_radius must be non-negative, got %f", c.FoodMagnetRadius)
+	}
+	if c.FoodMagnetStrength < 0 {
+		return fmt.Errorf("food_magnet_strength must be non-negative, got %f", c.FoodMagnetStrength)
+	}
 
 	// Validate physics settings
 	if c.MinimumSafeMassRatio <= 0 || c.MinimumSafeMassRatio > 1 {
@@ -278,6 +589,28 @@ func (c *Configuration) Validate() error {
 	if c.MinOverlapPctToConsume <= 0 || c.MinOverlapPctToConsume > 1 {
 		return fmt.Errorf("min_overlap_pct_to_consume must be between 0 and 1, got %f", c.MinOverlapPctToConsume)
 	}
+	if c.MinMoveSpeed < 0 || c.MinMoveSpeed > float32(c.StartPlayerSpeed) {
+		return fmt.Errorf("min_move_speed must be between 0 and start_player_speed (%d), got %f", c.StartPlayerSpeed, c.MinMoveSpeed)
+	}
+	if c.MaxMovePerTick < 0 {
+		return fmt.Errorf("max_move_per_tick must be non-negative, got %f", c.MaxMovePerTick)
+	}
+	if c.DecayGracePeriodSec < 0 {
+		return fmt.Errorf("decay_grace_period_sec must be non-negative, got %f", c.DecayGracePeriodSec)
+	}
+	if c.MaxCircleMass != 0 && c.MaxCircleMass < c.StartPlayerMass {
+		return fmt.Errorf("max_circle_mass must be 0 (uncapped) or at least start_player_mass (%d), got %d", c.StartPlayerMass, c.MaxCircleMass)
+	}
+	if c.DecayExemptLeaderFraction < 0 || c.DecayExemptLeaderFraction >= 1 {
+		return fmt.Errorf("decay_exempt_leader_fraction must be in [0, 1), got %f", c.DecayExemptLeaderFraction)
+	}
+	if c.CampingThresholdSec < 0 {
+		return fmt.Errorf("camping_threshold_sec must be non-negative, got %f", c.CampingThresholdSec)
+	}
+	// Normal decay takes 1% per tick, so the multiplier can't go past 100
+	if c.CampingDecayMultiplier < 1 || c.CampingDecayMultiplier > 100 {
+		return fmt.Errorf("camping_decay_multiplier must be between 1 and 100, got %f", c.CampingDecayMultiplier)
+	}
 
 	// Validate split mechanics settings
 	if c.MaxCirclesPerPlayer == 0 {
@@ -286,6 +619,12 @@ func (c *Configuration) Validate() error {
 	if c.MaxCirclesPerPlayer > 64 {
 		return fmt.Errorf("max_circles_per_player should not exceed 64 for performance reasons, got %d", c.MaxCirclesPerPlayer)
 	}
+	if c.CircleCapModel != CircleCapFlat && c.CircleCapModel != CircleCapMassScaled {
+		return fmt.Errorf("circle_cap_model must be %q or %q, got %q", CircleCapFlat, CircleCapMassScaled, c.CircleCapModel)
+	}
+	if c.CircleCapMassPerCircle == 0 {
+		return fmt.Errorf("circle_cap_mass_per_circle must be greater than 0")
+	}
 	if c.SplitRecombineDelaySec <= 0 {
 		return fmt.Errorf("split_recombine_delay_sec must be greater than 0")
 	}
@@ -296,19 +635,33 @@ func (c *Configuration) Validate() error {
 		return fmt.Errorf("split_grav_pull_before_recombine_sec (%f) must be <= split_recombine_delay_sec (%f)",
 			c.SplitGravPullBeforeRecombineSec, c.SplitRecombineDelaySec)
 	}
+	if c.RecombineMaxDistance < 0 {
+		return fmt.Errorf("recombine_max_distance must be >= 0, got %f", c.RecombineMaxDistance)
+	}
 	if c.AllowedSplitCircleOverlapPct <= 0 || c.AllowedSplitCircleOverlapPct > 1 {
 		return fmt.Errorf("allowed_split_circle_overlap_pct must be between 0 and 1, got %f", c.AllowedSplitCircleOverlapPct)
 	}
 	if c.SelfCollisionSpeed < 0 || c.SelfCollisionSpeed > 1 {
 		return fmt.Errorf("self_collision_speed must be between 0 and 1, got %f", c.SelfCollisionSpeed)
 	}
+	if c.MaxSelfCollisionSpeed < c.SelfCollisionSpeed || c.MaxSelfCollisionSpeed > 1 {
+		return fmt.Errorf("max_self_collision_speed must be between self_collision_speed (%f) and 1, got %f",
+			c.SelfCollisionSpeed, c.MaxSelfCollisionSpeed)
+	}
 
 	// Validate world settings
-	if c.DefaultWorldSize < 100 {
-		return fmt.Errorf("default_world_size must be at least 100, got %d", c.DefaultWorldSize)
+	if err := ValidateWorldSize(c.DefaultWorldSize); err != nil {
+		return fmt.Errorf("invalid default_world_size: %w", err)
+	}
+	if c.SpawnProtectionSec < 0 {
+		return fmt.Errorf("spawn_protection_sec must be non-negative, got %f", c.SpawnProtectionSec)
 	}
-	if c.DefaultWorldSize > 100000 {
-		return fmt.Errorf("default_world_size should not exceed 100000 for performance reasons, got %d", c.DefaultWorldSize)
+	if c.ReconnectGraceSec < 0 {
+		return fmt.Errorf("reconnect_grace_sec must be non-negative, got %f", c.ReconnectGraceSec)
+	}
+	if c.SpawnWallPadding < 0 || 2*c.SpawnWallPadding >= float32(c.DefaultWorldSize) {
+		return fmt.Errorf("spawn_wall_padding must be between 0 and half of default_world_size (%d), got %f",
+			c.DefaultWorldSize, c.SpawnWallPadding)
 	}
 
 	// Validate timer settings
@@ -324,6 +677,24 @@ func (c *Configuration) Validate() error {
 	if c.MovePlayersInterval > time.Second {
 		return fmt.Errorf("move_players_interval should not exceed 1 second for gameplay reasons")
 	}
+	if c.PhysicsTickHz > 1000 {
+		return fmt.Errorf("physics_tick_hz must not exceed 1000, got %d", c.PhysicsTickHz)
+	}
+	if step := c.PhysicsStep(); step > c.MovePlayersInterval {
+		return fmt.Errorf("physics_tick_hz must give at least one substep per move_players_interval (%v), got a %v step", c.MovePlayersInterval, step)
+	}
+	if c.StalePlayerTTL < CLEANUP_STALE_PLAYERS_INTERVAL {
+		return fmt.Errorf("stale_player_ttl should be at least the cleanup interval (%v)", CLEANUP_STALE_PLAYERS_INTERVAL)
+	}
+	if c.ConsumeDelay < 0 || c.ConsumeDelay > time.Second {
+		return fmt.Errorf("consume_delay must be between 0 and 1 second, got %v", c.ConsumeDelay)
+	}
+	if c.MaxInputClockDrift < 0 {
+		return fmt.Errorf("max_input_clock_drift cannot be negative, got %v", c.MaxInputClockDrift)
+	}
+	if c.FoodTTL < 0 {
+		return fmt.Errorf("food_ttl cannot be negative, got %v", c.FoodTTL)
+	}
 
 	// Validate performance settings
 	if c.MaxConcurrentPlayers == 0 {
@@ -332,6 +703,12 @@ func (c *Configuration) Validate() error {
 	if c.MaxConcurrentPlayers > 100000 {
 		return fmt.Errorf("max_concurrent_players should not exceed 100000 for performance reasons")
 	}
+	if c.MaxCollisionChecksPerTick == 0 {
+		return fmt.Errorf("max_collision_checks_per_tick must be greater than 0")
+	}
+	if c.SlowReducerThreshold < 0 {
+		return fmt.Errorf("slow_reducer_threshold cannot be negative, got %v", c.SlowReducerThreshold)
+	}
 
 	// Validate derived values
 	if c.MinMassToSplit != c.StartPlayerMass*2 {
@@ -341,11 +718,61 @@ func (c *Configuration) Validate() error {
 	return nil
 }
 
+// ValidateWorldSize checks that a world size is within the supported bounds
+func ValidateWorldSize(worldSize uint64) error {
+	if worldSize < MIN_WORLD_SIZE {
+		return fmt.Errorf("world_size must be at least %d, got %d", MIN_WORLD_SIZE, worldSize)
+	}
+	if worldSize > MAX_WORLD_SIZE {
+		return fmt.Errorf("world_size should not exceed %d for performance reasons, got %d", MAX_WORLD_SIZE, worldSize)
+	}
+	return nil
+}
+
 // GetMassToSplit returns the minimum mass required to split for this configuration
 func (c *Configuration) GetMassToSplit() uint32 {
 	return c.StartPlayerMass * 2
 }
 
+// PhysicsStep returns the duration of one physics substep at PhysicsTickHz,
+// or MovePlayersInterval when no tick rate is configured
+func (c *Configuration) PhysicsStep() time.Duration {
+	if c.PhysicsTickHz == 0 {
+		return c.MovePlayersInterval
+	}
+	return time.Second / time.Duration(c.PhysicsTickHz)
+}
+
+// MaxMoveDistance returns the farthest an entity may move in deltaTime seconds: MaxMovePerTick
+// scaled from one MovePlayersInterval to deltaTime or, when that is 0, the distance covered
+// at twice StartPlayerSpeed, the fastest any circle moves, so that normal movement is never clamped
+func (c *Configuration) MaxMoveDistance(deltaTime float32) float32 {
+	if c.MaxMovePerTick == 0 {
+		return 2 * float32(c.StartPlayerSpeed) * deltaTime
+	}
+	return c.MaxMovePerTick * deltaTime / float32(c.MovePlayersInterval.Seconds())
+}
+
+// Merge returns a copy of the configuration with the fields present in the partial JSON
+// object data overwritten. Unknown fields are rejected, derived values are recalculated
+// and the result is validated.
+func (c *Configuration) Merge(data []byte) (*Configuration, error) {
+	merged := *c
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&merged); err != nil {
+		return nil, fmt.Errorf("invalid configuration json: %w", err)
+	}
+
+	// Recalculate derived values
+	merged.MinMassToSplit = merged.StartPlayerMass * 2
+
+	if err := merged.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return &merged, nil
+}
+
 // Global configuration instance
 var globalConfig *Configuration
 
@@ -355,6 +782,7 @@ func SetGlobalConfiguration(config *Configuration) error {
 		return fmt.Errorf("invalid configuration: %w", err)
 	}
 	globalConfig = config
+	types.SetSafeArithmetic(config.SafeArithmetic)
 	return nil
 }
 
@@ -379,21 +807,32 @@ func LoadGlobalConfigurationFromEnvironment() error {
 // Mathematical Utility Functions
 // These functions use the configuration values to calculate game mechanics
 
+// effectiveMass clamps mass to MIN_EFFECTIVE_MASS.
+// Entity.Validate forbids zero mass, but transient states (e.g. mid-consume) can hit it.
+func effectiveMass(mass uint32) uint32 {
+	if mass < MIN_EFFECTIVE_MASS {
+		return MIN_EFFECTIVE_MASS
+	}
+	return mass
+}
+
 // MassToRadius calculates the radius of a circle based on its mass
 // Formula: sqrt(mass) - matches both Rust and C# implementations
+// Mass is clamped to MIN_EFFECTIVE_MASS so a zero-mass entity still has a radius
 func MassToRadius(mass uint32) float32 {
 	// Use math.Sqrt but we need to import math
-	massFloat := float32(mass)
+	massFloat := float32(effectiveMass(mass))
 	return float32(math.Sqrt(float64(massFloat)))
 }
 
 // MassToMaxMoveSpeed calculates the maximum movement speed based on mass
 // Formula: 2 * START_PLAYER_SPEED / (1 + sqrt(mass / START_PLAYER_MASS))
+// Mass is clamped to MIN_EFFECTIVE_MASS so a zero-mass entity moves no faster than mass 1
 func MassToMaxMoveSpeed(mass uint32) float32 {
 	config := GetGlobalConfiguration()
 	startMass := float32(config.StartPlayerMass)
 	startSpeed := float32(config.StartPlayerSpeed)
-	massFloat := float32(mass)
+	massFloat := float32(effectiveMass(mass))
 
 	// Calculate sqrt(mass / START_PLAYER_MASS)
 	ratio := massFloat / startMass
@@ -408,11 +847,12 @@ func IsValidMassForSplit(mass uint32) bool {
 	return mass >= config.GetMassToSplit()
 }
 
-// GetOverlapThreshold calculates the overlap threshold for consumption
+// GetOverlapThreshold calculates the overlap threshold for consumption.
+// The square is taken in float64 so it stays accurate for very large radii.
 func GetOverlapThreshold(radiusA, radiusB float32) float32 {
 	config := GetGlobalConfiguration()
-	radiusSum := (radiusA + radiusB) * (1.0 - config.MinOverlapPctToConsume)
-	return radiusSum * radiusSum
+	radiusSum := (float64(radiusA) + float64(radiusB)) * (1.0 - float64(config.MinOverlapPctToConsume))
+	return float32(radiusSum * radiusSum)
 }
 
 // Documentation and Helper Functions
@@ -425,33 +865,77 @@ Blackholio Game Server Configuration via Environment Variables:
 Core Game Settings:
   BLACKHOLIO_START_PLAYER_MASS         Starting mass for new players (default: 15)
   BLACKHOLIO_START_PLAYER_SPEED        Base player speed (default: 10)
+  BLACKHOLIO_START_PLAYER_MASS_OVERRIDE Mass players spawn with, 0 uses START_PLAYER_MASS (default: 0)
   BLACKHOLIO_FOOD_MASS_MIN             Minimum food mass (default: 2)
   BLACKHOLIO_FOOD_MASS_MAX             Maximum food mass (default: 4)
   BLACKHOLIO_TARGET_FOOD_COUNT         Target food count (default: 600)
+  BLACKHOLIO_INITIAL_FOOD_BURST        Food spawned when the first player joins, 0 disables (default: 600)
+  BLACKHOLIO_FOOD_PER_PLAYER           Food per active player, raises the target when larger (default: 0)
+  BLACKHOLIO_MIN_FOOD_SPACING          Minimum distance between spawned food, 0 disables (default: 0.0)
+  BLACKHOLIO_FOOD_MASS_MULTIPLIER      Scale of the mass gained from food (default: 1.0)
+  BLACKHOLIO_FOOD_AVOID_CIRCLES        Don't spawn food inside existing circles (default: false)
+
+Food Magnet:
+  BLACKHOLIO_FOOD_MAGNET_MIN_MASS      Mass at which circles start attracting food (default: 500)
+  BLACKHOLIO_FOOD_MAGNET_RADIUS        Attraction range from the circle's center (default: 50.0)
+  BLACKHOLIO_FOOD_MAGNET_STRENGTH      Pull speed on nearby food, 0 disables (default: 0.0)
 
 Physics Settings:
   BLACKHOLIO_MINIMUM_SAFE_MASS_RATIO   Safe mass ratio for consumption (default: 0.85)
   BLACKHOLIO_MIN_OVERLAP_PCT_TO_CONSUME Overlap percentage for consumption (default: 0.1)
+  BLACKHOLIO_MIN_MOVE_SPEED            Minimum movement speed for large circles (default: 1.0)
+  BLACKHOLIO_MAX_MOVE_PER_TICK         Farthest a circle moves per move interval, 0 derives it from max speed (default: 0.0)
+  BLACKHOLIO_DECAY_GRACE_PERIOD_SEC    Circle age before decay starts (default: 0.0)
+  BLACKHOLIO_MAX_CIRCLE_MASS           Mass cap for a single circle, 0 disables (default: 0)
+  BLACKHOLIO_SPLIT_MASS_OVERFLOW       Spawn a new circle from mass eaten past the cap (default: false)
+  BLACKHOLIO_DECAY_EXEMPT_LEADER_FRACTION No decay below this fraction of the largest mass, 0 disables (default: 0.0)
+  BLACKHOLIO_CAMPING_THRESHOLD_SEC     Seconds without moving before a circle decays faster, 0 disables (default: 0.0)
+  BLACKHOLIO_CAMPING_DECAY_MULTIPLIER  Decay multiplier for circles past the camping threshold (default: 3.0)
+  BLACKHOLIO_RESOLVE_CIRCLE_OVERLAPS   Push apart circles that can't consume each other (default: false)
+  BLACKHOLIO_TEAM_MERGE_ALLOWED        Let teammates absorb each other's circles (default: false)
+  BLACKHOLIO_STRICT_CONSUMPTION        Consume anything strictly lighter, ignoring the safe mass ratio (default: false)
 
 Split Mechanics:
   BLACKHOLIO_MAX_CIRCLES_PER_PLAYER             Max circles per player (default: 16)
+  BLACKHOLIO_CIRCLE_CAP_MODEL                   Circle cap model, flat or mass_scaled (default: flat)
+  BLACKHOLIO_CIRCLE_CAP_MASS_PER_CIRCLE         Total mass per allowed circle when mass_scaled (default: 30)
   BLACKHOLIO_SPLIT_RECOMBINE_DELAY_SEC          Split recombine delay (default: 5.0)
   BLACKHOLIO_SPLIT_GRAV_PULL_BEFORE_RECOMBINE_SEC Gravity pull time (default: 2.0)
   BLACKHOLIO_ALLOWED_SPLIT_CIRCLE_OVERLAP_PCT   Split circle overlap (default: 0.9)
   BLACKHOLIO_SELF_COLLISION_SPEED               Circle separation speed (default: 0.05)
+  BLACKHOLIO_MAX_SELF_COLLISION_SPEED           Separation speed at full overlap (default: 0.2)
+  BLACKHOLIO_RECOMBINE_MAX_DISTANCE             Max edge gap for recombining, 0 for any (default: 0.0)
+  BLACKHOLIO_MAX_RECOMBINE_ATTEMPTS             Retries before a forced merge, 0 never forces (default: 20)
+  BLACKHOLIO_RECOMBINE_AT_CENTER_OF_MASS        Merge own circles at their weighted midpoint (default: false)
 
 World Settings:
   BLACKHOLIO_DEFAULT_WORLD_SIZE         World size (default: 1000)
+  BLACKHOLIO_SPAWN_DENSITY_AWARE        Spawn players in the emptiest region (default: false)
+  BLACKHOLIO_SPAWN_PROTECTION_SEC       Invulnerability after spawning, 0 disables (default: 0.0)
+  BLACKHOLIO_MARK_DEAD_PLAYERS          Flag players whose last circle was eaten as dead (default: false)
+  BLACKHOLIO_REQUIRE_UNIQUE_NAMES       Reject names already used by an active player (default: false)
+  BLACKHOLIO_SPAWN_WALL_PADDING         Extra distance spawns keep from the walls (default: 0.0)
+  BLACKHOLIO_REJECT_INPUT_WITHOUT_CIRCLES Reject input from players with no circles (default: false)
+  BLACKHOLIO_RECONNECT_GRACE_SEC       Seconds after disconnecting that returning players skip the cap, 0 always (default: 0.0)
 
 Timer Settings (use Go duration format, e.g., "5s", "500ms"):
   BLACKHOLIO_CIRCLE_DECAY_INTERVAL      Circle decay interval (default: 5s)
   BLACKHOLIO_SPAWN_FOOD_INTERVAL        Food spawn interval (default: 500ms)
   BLACKHOLIO_MOVE_PLAYERS_INTERVAL      Player move interval (default: 50ms)
+  BLACKHOLIO_PHYSICS_TICK_HZ            Physics substeps per second, 0 ties them to the move interval (default: 0)
+  BLACKHOLIO_STALE_PLAYER_TTL           Log out players not seen for this long (default: 5m)
+  BLACKHOLIO_CONSUME_DELAY              Delay before consumption for client animation (default: 0s)
+  BLACKHOLIO_MAX_INPUT_CLOCK_DRIFT      Reject inputs timestamped this far from the server clock, 0 disables (default: 0s)
+  BLACKHOLIO_FOOD_TTL                   Despawn uneaten food older than this, 0 disables (default: 0s)
+  BLACKHOLIO_SKIP_IDLE_TICKS            Skip movement and decay ticks while no players are connected (default: true)
 
 Performance Settings:
   BLACKHOLIO_ENABLE_PERFORMANCE_LOGGING Enable performance logging (default: false)
   BLACKHOLIO_MAX_CONCURRENT_PLAYERS     Max concurrent players (default: 1000)
+  BLACKHOLIO_MAX_COLLISION_CHECKS_PER_TICK Collision pair checks per tick (default: 250000)
   BLACKHOLIO_ENABLE_DEBUG_MODE          Enable debug mode (default: false)
+  BLACKHOLIO_SAFE_ARITHMETIC            Zero and log non-finite vector arithmetic results (default: false)
+  BLACKHOLIO_SLOW_REDUCER_THRESHOLD     Always log reducers slower than this, 0 disables (default: 0s)
 
 Example:
   export BLACKHOLIO_START_PLAYER_MASS=20
@@ -469,43 +953,89 @@ Blackholio Game Constants Summary:
 Core Game Constants:
   START_PLAYER_MASS = %d
   START_PLAYER_SPEED = %d
+  START_PLAYER_MASS_OVERRIDE = %d
   FOOD_MASS_MIN = %d
   FOOD_MASS_MAX = %d
   TARGET_FOOD_COUNT = %d
+  INITIAL_FOOD_BURST = %d
+  FOOD_PER_PLAYER = %d
+  MIN_FOOD_SPACING = %.2f
+  FOOD_MASS_MULTIPLIER = %.2f
+  FOOD_AVOID_CIRCLES = %v
+
+Food Magnet Constants:
+  FOOD_MAGNET_MIN_MASS = %d
+  FOOD_MAGNET_RADIUS = %.2f
+  FOOD_MAGNET_STRENGTH = %.2f
 
 Physics Constants:
   MINIMUM_SAFE_MASS_RATIO = %.2f
   MIN_OVERLAP_PCT_TO_CONSUME = %.2f
+  MIN_MOVE_SPEED = %.2f
+  MAX_MOVE_PER_TICK = %.2f
+  DECAY_GRACE_PERIOD_SEC = %.2f
+  MAX_CIRCLE_MASS = %d
+  SPLIT_MASS_OVERFLOW = %v
+  DECAY_EXEMPT_LEADER_FRACTION = %.2f
+  CAMPING_THRESHOLD_SEC = %.2f
+  CAMPING_DECAY_MULTIPLIER = %.2f
+  RESOLVE_CIRCLE_OVERLAPS = %v
+  TEAM_MERGE_ALLOWED = %v
+  STRICT_CONSUMPTION = %v
 
 Split Mechanics Constants:
   MIN_MASS_TO_SPLIT = %d (calculated: START_PLAYER_MASS * 2)
   MAX_CIRCLES_PER_PLAYER = %d
+  CIRCLE_CAP_MODEL = %s
+  CIRCLE_CAP_MASS_PER_CIRCLE = %d
   SPLIT_RECOMBINE_DELAY_SEC = %.2f
   SPLIT_GRAV_PULL_BEFORE_RECOMBINE_SEC = %.2f
   ALLOWED_SPLIT_CIRCLE_OVERLAP_PCT = %.2f
   SELF_COLLISION_SPEED = %.2f
+  MAX_SELF_COLLISION_SPEED = %.2f
+  RECOMBINE_MAX_DISTANCE = %.2f
+  MAX_RECOMBINE_ATTEMPTS = %d
+  RECOMBINE_AT_CENTER_OF_MASS = %v
 
 World Constants:
   DEFAULT_WORLD_SIZE = %d
+  SPAWN_DENSITY_AWARE = %v
+  SPAWN_PROTECTION_SEC = %.2f
+  MARK_DEAD_PLAYERS = %v
+  REQUIRE_UNIQUE_NAMES = %v
+  SPAWN_WALL_PADDING = %.2f
+  REJECT_INPUT_WITHOUT_CIRCLES = %v
+  RECONNECT_GRACE_SEC = %.2f
 
 Timer Constants:
   CIRCLE_DECAY_INTERVAL = %v
   SPAWN_FOOD_INTERVAL = %v
   MOVE_PLAYERS_INTERVAL = %v
+  PHYSICS_TICK_HZ = %d
+  STALE_PLAYER_TTL = %v
+  CONSUME_DELAY = %v
+  MAX_INPUT_CLOCK_DRIFT = %v
+  FOOD_TTL = %v
+  SKIP_IDLE_TICKS = %v
 
 Performance Settings:
   EnablePerformanceLogging = %v
   MaxConcurrentPlayers = %d
+  MaxCollisionChecksPerTick = %d
   EnableDebugMode = %v
+  SafeArithmetic = %v
+  SlowReducerThreshold = %v
 `,
-		config.StartPlayerMass, config.StartPlayerSpeed,
-		config.FoodMassMin, config.FoodMassMax, config.TargetFoodCount,
-		config.MinimumSafeMassRatio, config.MinOverlapPctToConsume,
-		config.MinMassToSplit, config.MaxCirclesPerPlayer,
+		config.StartPlayerMass, config.StartPlayerSpeed, config.StartPlayerMassOverride,
+		config.FoodMassMin, config.FoodMassMax, config.TargetFoodCount, config.InitialFoodBurst, config.FoodPerPlayer, config.MinFoodSpacing, config.FoodMassMultiplier, config.FoodAvoidCircles,
+		config.FoodMagnetMinMass, config.FoodMagnetRadius, config.FoodMagnetStrength,
+		config.MinimumSafeMassRatio, config.MinOverlapPctToConsume, config.MinMoveSpeed, config.MaxMovePerTick, config.DecayGracePeriodSec, config.MaxCircleMass, config.SplitMassOverflow, config.DecayExemptLeaderFraction, config.CampingThresholdSec, config.CampingDecayMultiplier, config.ResolveCircleOverlaps, config.TeamMergeAllowed, config.StrictConsumption,
+		config.MinMassToSplit, config.MaxCirclesPerPlayer, config.CircleCapModel, config.CircleCapMassPerCircle,
 		config.SplitRecombineDelaySec, config.SplitGravPullBeforeRecombineSec,
-		config.AllowedSplitCircleOverlapPct, config.SelfCollisionSpeed,
-		config.DefaultWorldSize,
-		config.CircleDecayInterval, config.SpawnFoodInterval, config.MovePlayersInterval,
-		config.EnablePerformanceLogging, config.MaxConcurrentPlayers, config.EnableDebugMode,
+		config.AllowedSplitCircleOverlapPct, config.SelfCollisionSpeed, config.MaxSelfCollisionSpeed,
+		config.RecombineMaxDistance, config.MaxRecombineAttempts, config.RecombineAtCenterOfMass,
+		config.DefaultWorldSize, config.SpawnDensityAware, config.SpawnProtectionSec, config.MarkDeadPlayers, config.RequireUniqueNames, config.SpawnWallPadding, config.RejectInputWithoutCircles, config.ReconnectGraceSec,
+		config.CircleDecayInterval, config.SpawnFoodInterval, config.MovePlayersInterval, config.PhysicsTickHz, config.StalePlayerTTL, config.ConsumeDelay, config.MaxInputClockDrift, config.FoodTTL, config.SkipIdleTicks,
+		config.EnablePerformanceLogging, config.MaxConcurrentPlayers, config.MaxCollisionChecksPerTick, config.EnableDebugMode, config.SafeArithmetic, config.SlowReducerThreshold,
 	)
 }
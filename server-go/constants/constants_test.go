@@ -5,6 +5,8 @@ import (
 	"os"
 	"testing"
 	"time"
+
+	"github.com/clockworklabs/Blackholio/server-go/types"
 )
 
 func TestConstants(t *testing.T) {
@@ -137,6 +139,22 @@ func TestConfigurationValidation(t *testing.T) {
 		}
 	})
 
+	t.Run("InvalidMinFoodSpacing", func(t *testing.T) {
+		config := DefaultConfiguration()
+		config.MinFoodSpacing = -1
+		if err := config.Validate(); err == nil {
+			t.Error("Should error with negative food spacing")
+		}
+	})
+
+	t.Run("InvalidFoodMassMultiplier", func(t *testing.T) {
+		config := DefaultConfiguration()
+		config.FoodMassMultiplier = -0.5
+		if err := config.Validate(); err == nil {
+			t.Error("Should error with negative food mass multiplier")
+		}
+	})
+
 	t.Run("InvalidMassRatio", func(t *testing.T) {
 		config := DefaultConfiguration()
 		config.MinimumSafeMassRatio = 1.5
@@ -197,6 +215,218 @@ func TestConfigurationValidation(t *testing.T) {
 			t.Error("Should error when grav pull time > recombine delay")
 		}
 	})
+
+	t.Run("InvalidMaxSelfCollisionSpeed", func(t *testing.T) {
+		config := DefaultConfiguration()
+		config.MaxSelfCollisionSpeed = config.SelfCollisionSpeed / 2
+		if err := config.Validate(); err == nil {
+			t.Error("Should error when max self collision speed is below self collision speed")
+		}
+
+		config.MaxSelfCollisionSpeed = 1.5
+		if err := config.Validate(); err == nil {
+			t.Error("Should error when max self collision speed exceeds 1")
+		}
+	})
+
+	t.Run("InvalidRecombineMaxDistance", func(t *testing.T) {
+		config := DefaultConfiguration()
+		config.RecombineMaxDistance = -1
+		if err := config.Validate(); err == nil {
+			t.Error("Should error when recombine max distance is negative")
+		}
+	})
+
+	t.Run("InvalidStalePlayerTTL", func(t *testing.T) {
+		config := DefaultConfiguration()
+		config.StalePlayerTTL = time.Second
+		if err := config.Validate(); err == nil {
+			t.Error("Should error when stale player TTL is shorter than the cleanup interval")
+		}
+	})
+
+	t.Run("InvalidPhysicsTickHz", func(t *testing.T) {
+		config := DefaultConfiguration()
+		config.PhysicsTickHz = 2000
+		if err := config.Validate(); err == nil {
+			t.Error("Should error when physics tick rate is above 1000")
+		}
+
+		config.PhysicsTickHz = 10 // 100ms step, coarser than the 50ms move interval
+		if err := config.Validate(); err == nil {
+			t.Error("Should error when a physics step is longer than the move interval")
+		}
+
+		config.PhysicsTickHz = 120
+		if err := config.Validate(); err != nil {
+			t.Errorf("Finer physics than the move interval should be valid: %v", err)
+		}
+	})
+
+	t.Run("InvalidMaxInputClockDrift", func(t *testing.T) {
+		config := DefaultConfiguration()
+		config.MaxInputClockDrift = -time.Second
+		if err := config.Validate(); err == nil {
+			t.Error("Should error when max input clock drift is negative")
+		}
+	})
+
+	t.Run("InvalidFoodTTL", func(t *testing.T) {
+		config := DefaultConfiguration()
+		config.FoodTTL = -time.Second
+		if err := config.Validate(); err == nil {
+			t.Error("Should error when food TTL is negative")
+		}
+	})
+
+	t.Run("InvalidDecayExemptLeaderFraction", func(t *testing.T) {
+		config := DefaultConfiguration()
+		config.DecayExemptLeaderFraction = -0.1
+		if err := config.Validate(); err == nil {
+			t.Error("Should error when decay exempt leader fraction is negative")
+		}
+
+		config.DecayExemptLeaderFraction = 1
+		if err := config.Validate(); err == nil {
+			t.Error("Should error when decay exempt leader fraction is 1 or more")
+		}
+	})
+
+	t.Run("InvalidMinMoveSpeed", func(t *testing.T) {
+		config := DefaultConfiguration()
+		config.MinMoveSpeed = -1
+		if err := config.Validate(); err == nil {
+			t.Error("Should error when min move speed is negative")
+		}
+
+		config.MinMoveSpeed = float32(config.StartPlayerSpeed) + 1
+		if err := config.Validate(); err == nil {
+			t.Error("Should error when min move speed exceeds start player speed")
+		}
+	})
+
+	t.Run("InvalidDecayGracePeriod", func(t *testing.T) {
+		config := DefaultConfiguration()
+		config.DecayGracePeriodSec = -1
+		if err := config.Validate(); err == nil {
+			t.Error("Should error when decay grace period is negative")
+		}
+	})
+
+	t.Run("InvalidFoodMagnet", func(t *testing.T) {
+		config := DefaultConfiguration()
+		config.FoodMagnetRadius = -1
+		if err := config.Validate(); err == nil {
+			t.Error("Should error when food magnet radius is negative")
+		}
+
+		config = DefaultConfiguration()
+		config.FoodMagnetStrength = -1
+		if err := config.Validate(); err == nil {
+			t.Error("Should error when food magnet strength is negative")
+		}
+	})
+
+	t.Run("InvalidSpawnProtection", func(t *testing.T) {
+		config := DefaultConfiguration()
+		config.SpawnProtectionSec = -1
+		if err := config.Validate(); err == nil {
+			t.Error("Should error when spawn protection is negative")
+		}
+	})
+
+	t.Run("InvalidCircleCap", func(t *testing.T) {
+		config := DefaultConfiguration()
+		config.CircleCapModel = "exponential"
+		if err := config.Validate(); err == nil {
+			t.Error("Should error on an unknown circle cap model")
+		}
+
+		config = DefaultConfiguration()
+		config.CircleCapMassPerCircle = 0
+		if err := config.Validate(); err == nil {
+			t.Error("Should error when circle cap mass per circle is 0")
+		}
+	})
+
+	t.Run("InvalidCamping", func(t *testing.T) {
+		config := DefaultConfiguration()
+		config.CampingThresholdSec = -1
+		if err := config.Validate(); err == nil {
+			t.Error("Should error when camping threshold is negative")
+		}
+
+		config = DefaultConfiguration()
+		config.CampingDecayMultiplier = 0.5
+		if err := config.Validate(); err == nil {
+			t.Error("Should error when camping decay multiplier is below 1")
+		}
+	})
+
+	t.Run("InvalidStartPlayerMassOverride", func(t *testing.T) {
+		config := DefaultConfiguration()
+		config.StartPlayerMassOverride = 250000 // Radius 500 fills the default world
+		if err := config.Validate(); err == nil {
+			t.Error("Should error when the start mass override can't fit in the world")
+		}
+	})
+
+	t.Run("InvalidMaxMovePerTick", func(t *testing.T) {
+		config := DefaultConfiguration()
+		config.MaxMovePerTick = -1
+		if err := config.Validate(); err == nil {
+			t.Error("Should error when max move per tick is negative")
+		}
+	})
+
+	t.Run("InvalidReconnectGrace", func(t *testing.T) {
+		config := DefaultConfiguration()
+		config.ReconnectGraceSec = -1
+		if err := config.Validate(); err == nil {
+			t.Error("Should error when reconnect grace is negative")
+		}
+	})
+
+	t.Run("InvalidSpawnWallPadding", func(t *testing.T) {
+		config := DefaultConfiguration()
+		config.SpawnWallPadding = -1
+		if err := config.Validate(); err == nil {
+			t.Error("Should error when spawn wall padding is negative")
+		}
+		config.SpawnWallPadding = float32(config.DefaultWorldSize) / 2
+		if err := config.Validate(); err == nil {
+			t.Error("Should error when spawn wall padding leaves no room to spawn")
+		}
+	})
+
+	t.Run("InvalidMaxCircleMass", func(t *testing.T) {
+		config := DefaultConfiguration()
+		config.MaxCircleMass = config.StartPlayerMass - 1
+		if err := config.Validate(); err == nil {
+			t.Error("Should error when max circle mass is below start player mass")
+		}
+
+		config.MaxCircleMass = 0
+		if err := config.Validate(); err != nil {
+			t.Errorf("Zero max circle mass should mean uncapped: %v", err)
+		}
+	})
+
+	t.Run("InvalidMaxCollisionChecksPerTick", func(t *testing.T) {
+		config := DefaultConfiguration()
+		config.MaxCollisionChecksPerTick = 0
+		if err := config.Validate(); err == nil {
+			t.Error("Should error when max collision checks per tick is 0")
+		}
+	})
+
+	t.Run("InvalidSlowReducerThreshold", func(t *testing.T) {
+		config := DefaultConfiguration()
+		config.SlowReducerThreshold = -time.Millisecond
+		if err := config.Validate(); err == nil {
+			t.Error("Should error when slow reducer threshold is negative")
+		}
+	})
 }
 
 func TestEnvironmentVariableLoading(t *testing.T) {
@@ -338,6 +568,22 @@ func TestGlobalConfiguration(t *testing.T) {
 		}
 	})
 
+	t.Run("SafeArithmetic", func(t *testing.T) {
+		defer SetGlobalConfiguration(DefaultConfiguration())
+		config := DefaultConfiguration()
+		config.SafeArithmetic = true
+		if err := SetGlobalConfiguration(config); err != nil {
+			t.Fatalf("SetGlobalConfiguration failed: %v", err)
+		}
+		if !types.SafeArithmetic() {
+			t.Error("Setting the configuration should turn on safe arithmetic")
+		}
+		SetGlobalConfiguration(DefaultConfiguration())
+		if types.SafeArithmetic() {
+			t.Error("Safe arithmetic should be off by default")
+		}
+	})
+
 	t.Run("SetInvalidGlobalConfiguration", func(t *testing.T) {
 		invalidConfig := DefaultConfiguration()
 		invalidConfig.StartPlayerMass = 0
@@ -349,6 +595,36 @@ func TestGlobalConfiguration(t *testing.T) {
 	})
 }
 
+func TestConfigurationMerge(t *testing.T) {
+	t.Run("Partial update", func(t *testing.T) {
+		merged, err := DefaultConfiguration().Merge([]byte(`{"target_food_count": 5}`))
+		if err != nil {
+			t.Fatalf("Merge failed: %v", err)
+		}
+		if merged.TargetFoodCount != 5 || merged.FoodMassMax != FOOD_MASS_MAX {
+			t.Errorf("Merge should only change the given fields, got %+v", merged)
+		}
+	})
+
+	t.Run("Derived values recalculated", func(t *testing.T) {
+		merged, err := DefaultConfiguration().Merge([]byte(`{"start_player_mass": 20}`))
+		if err != nil {
+			t.Fatalf("Merge of start_player_mass alone failed: %v", err)
+		}
+		if merged.StartPlayerMass != 20 || merged.MinMassToSplit != 40 {
+			t.Errorf("Expected start mass 20 and min mass to split 40, got %d and %d", merged.StartPlayerMass, merged.MinMassToSplit)
+		}
+	})
+
+	t.Run("Invalid values rejected", func(t *testing.T) {
+		for _, data := range []string{`{"start_player_mass": 0}`, `{"unknown_field": 1}`, `not json`} {
+			if _, err := DefaultConfiguration().Merge([]byte(data)); err == nil {
+				t.Errorf("Merge(%s) should fail", data)
+			}
+		}
+	})
+}
+
 func TestMathematicalFunctions(t *testing.T) {
 	t.Run("MassToRadius", func(t *testing.T) {
 		tests := []struct {
@@ -394,6 +670,28 @@ func TestMathematicalFunctions(t *testing.T) {
 		}
 	})
 
+	t.Run("ZeroMass", func(t *testing.T) {
+		SetGlobalConfiguration(DefaultConfiguration())
+
+		for _, mass := range []uint32{0, 1} {
+			radius := MassToRadius(mass)
+			speed := MassToMaxMoveSpeed(mass)
+			if radius != 1.0 {
+				t.Errorf("MassToRadius(%d) = %f, want 1.0", mass, radius)
+			}
+			if math.IsNaN(float64(speed)) || math.IsInf(float64(speed), 0) || speed <= 0 {
+				t.Errorf("MassToMaxMoveSpeed(%d) = %f, want a finite positive speed", mass, speed)
+			}
+			if speed >= 2*float32(START_PLAYER_SPEED) {
+				t.Errorf("MassToMaxMoveSpeed(%d) = %f, should be below 2 * START_PLAYER_SPEED", mass, speed)
+			}
+		}
+
+		if MassToMaxMoveSpeed(0) != MassToMaxMoveSpeed(1) {
+			t.Error("Mass 0 should be treated as mass 1")
+		}
+	})
+
 	t.Run("IsValidMassForSplit", func(t *testing.T) {
 		config := DefaultConfiguration()
 		SetGlobalConfiguration(config)
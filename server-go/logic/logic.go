@@ -1,9 +1,11 @@
 package logic
 
 import (
+	"encoding/binary"
 	"fmt"
 	"math"
 	"math/rand"
+	"sort"
 	"time"
 
 	"github.com/clockworklabs/Blackholio/server-go/constants"
@@ -17,35 +19,155 @@ import (
 // IsOverlapping checks if two entities are overlapping for collision detection
 // This matches the Rust and C# implementations exactly
 func IsOverlapping(a, b *tables.Entity) bool {
-	dx := a.Position.X - b.Position.X
-	dy := a.Position.Y - b.Position.Y
-	distanceSq := dx*dx + dy*dy
-
-	radiusA := constants.MassToRadius(a.Mass)
-	radiusB := constants.MassToRadius(b.Mass)
-
-	// In C#: radius_sum = (radius_a + radius_b) * (1.0 - MIN_OVERLAP_PCT_TO_CONSUME)
-	// In Rust: uses max_radius = f32::max(radius_a, radius_b)
-	// Let's use the C# approach for consistency with constants
-	config := constants.GetGlobalConfiguration()
-	radiusSum := (radiusA + radiusB) * (1.0 - config.MinOverlapPctToConsume)
-
-	return distanceSq <= radiusSum*radiusSum
+	return centerDistanceSquared(a, b) <= OverlapDistanceSquared(a, b, OverlapModeThreshold)
 }
 
 // IsOverlappingRust implements the Rust version of overlap detection
 // This uses the max radius approach instead of the threshold approach
 func IsOverlappingRust(a, b *tables.Entity) bool {
-	dx := a.Position.X - b.Position.X
-	dy := a.Position.Y - b.Position.Y
-	distanceSq := dx*dx + dy*dy
+	return centerDistanceSquared(a, b) <= OverlapDistanceSquared(a, b, OverlapModeMaxRadius)
+}
 
-	radiusA := constants.MassToRadius(a.Mass)
-	radiusB := constants.MassToRadius(b.Mass)
+// OverlapDistanceSquared returns the squared center distance at or below which a and b
+// count as overlapping under mode. In C#, radius_sum = (radius_a + radius_b) * (1.0 -
+// MIN_OVERLAP_PCT_TO_CONSUME); Rust uses max_radius = f32::max(radius_a, radius_b).
+// The radii and the square are computed in float64 and rounded once, so neither very
+// large nor very small masses lose precision along the way.
+func OverlapDistanceSquared(a, b *tables.Entity, mode OverlapMode) float32 {
+	radiusA := math.Sqrt(float64(max(a.Mass, constants.MIN_EFFECTIVE_MASS)))
+	radiusB := math.Sqrt(float64(max(b.Mass, constants.MIN_EFFECTIVE_MASS)))
+
+	var threshold float64
+	if mode == OverlapModeMaxRadius {
+		threshold = math.Max(radiusA, radiusB)
+	} else {
+		config := constants.GetGlobalConfiguration()
+		threshold = (radiusA + radiusB) * (1.0 - float64(config.MinOverlapPctToConsume))
+	}
+	return float32(threshold * threshold)
+}
 
-	// Rust approach: use max radius
-	maxRadius := float32(math.Max(float64(radiusA), float64(radiusB)))
-	return distanceSq <= maxRadius*maxRadius
+// centerDistanceSquared returns the squared distance between two entity centers
+func centerDistanceSquared(a, b *tables.Entity) float32 {
+	dx := float64(a.Position.X) - float64(b.Position.X)
+	dy := float64(a.Position.Y) - float64(b.Position.Y)
+	return float32(dx*dx + dy*dy)
+}
+
+// OverlapDepth returns how far inside the mode's overlap threshold the centers of a and b
+// are, i.e. how much further apart they could move and still overlap. It is 0 when the
+// entities don't overlap and grows to the full threshold distance when their centers meet.
+func OverlapDepth(a, b *tables.Entity, mode OverlapMode) float32 {
+	threshold := math.Sqrt(float64(OverlapDistanceSquared(a, b, mode)))
+	distance := math.Sqrt(float64(centerDistanceSquared(a, b)))
+	return float32(math.Max(threshold-distance, 0))
+}
+
+// OverlapMode selects which overlap rule is used for collision detection
+type OverlapMode int
+
+const (
+	// OverlapModeThreshold requires the circles to overlap by MinOverlapPctToConsume (C# behavior)
+	OverlapModeThreshold OverlapMode = iota
+
+	// OverlapModeMaxRadius requires the centers to be within the larger radius (Rust behavior)
+	OverlapModeMaxRadius
+)
+
+// DefaultOverlapMode is the overlap rule used by the game reducers
+const DefaultOverlapMode = OverlapModeThreshold
+
+// String returns the string representation of the overlap mode
+func (m OverlapMode) String() string {
+	switch m {
+	case OverlapModeThreshold:
+		return "Threshold"
+	case OverlapModeMaxRadius:
+		return "MaxRadius"
+	default:
+		return "Unknown"
+	}
+}
+
+// IsOverlappingWithMode checks if two entities are overlapping using the given overlap rule
+func IsOverlappingWithMode(a, b *tables.Entity, mode OverlapMode) bool {
+	if mode == OverlapModeMaxRadius {
+		return IsOverlappingRust(a, b)
+	}
+	return IsOverlapping(a, b)
+}
+
+// CanConsume is the authoritative check for whether consumer may eat consumed:
+// the consumer must have a sufficient mass advantage and the two must overlap
+func CanConsume(consumer, consumed *tables.Entity, mode OverlapMode) bool {
+	if !CanConsumeEntity(consumer.Mass, consumed.Mass) {
+		return false
+	}
+	return IsOverlappingWithMode(consumer, consumed, mode)
+}
+
+// CanTeamMerge is the counterpart of CanConsume for circles of teammates: no mass
+// advantage is needed, only that consumer is at least as heavy as consumed and has
+// covered its center, so teammates consolidate mass by deliberately stacking up
+func CanTeamMerge(consumer, consumed *tables.Entity) bool {
+	if consumer.Mass < consumed.Mass {
+		return false
+	}
+	return IsOverlappingWithMode(consumer, consumed, OverlapModeMaxRadius)
+}
+
+// ConsumePair is one consumption: ConsumerEntityID eats ConsumedEntityID
+type ConsumePair struct {
+	ConsumerEntityID uint32
+	ConsumedEntityID uint32
+}
+
+// ResolveClusterConsumption settles every consume in a cluster of overlapping entities in
+// one deterministic pass rather than pair by pair across ticks. ownerOf reports the player
+// owning an entity; unowned entities are food and never eat. Entities are visited from
+// heaviest to lightest (ties by id) and each owned entity not already eaten consumes every
+// uneaten entity of another owner it can CanConsume under mode. An eaten entity eats
+// nothing, so in a chain where A can eat B and B can eat C, only A eats B. Masses are
+// those at the start of the pass. Pairs come in that visiting order, with each consumer's
+// pairs sorted by consumed id.
+func ResolveClusterConsumption(entities []*tables.Entity, ownerOf func(uint32) (uint32, bool), mode OverlapMode) []ConsumePair {
+	sorted := make([]*tables.Entity, len(entities))
+	copy(sorted, entities)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Mass != sorted[j].Mass {
+			return sorted[i].Mass > sorted[j].Mass
+		}
+		return sorted[i].EntityID < sorted[j].EntityID
+	})
+
+	eaten := make(map[uint32]bool, len(sorted))
+	var pairs []ConsumePair
+	for i, consumer := range sorted {
+		consumerOwner, owned := ownerOf(consumer.EntityID)
+		if !owned || eaten[consumer.EntityID] {
+			continue
+		}
+
+		var consumed []uint32
+		for _, other := range sorted[i+1:] {
+			if eaten[other.EntityID] {
+				continue
+			}
+			if otherOwner, otherOwned := ownerOf(other.EntityID); otherOwned && otherOwner == consumerOwner {
+				continue
+			}
+			if CanConsume(consumer, other, mode) {
+				consumed = append(consumed, other.EntityID)
+			}
+		}
+
+		sort.Slice(consumed, func(a, b int) bool { return consumed[a] < consumed[b] })
+		for _, id := range consumed {
+			eaten[id] = true
+			pairs = append(pairs, ConsumePair{ConsumerEntityID: consumer.EntityID, ConsumedEntityID: id})
+		}
+	}
+	return pairs
 }
 
 // CalculateCenterOfMass calculates the center of mass for a slice of entities
@@ -79,48 +201,374 @@ func CalculateCenterOfMass(entities []*tables.Entity) types.DbVector2 {
 func SpawnCircleAt(playerID uint32, mass uint32, position types.DbVector2, timestamp tables.Timestamp) (*tables.Entity, *tables.Circle, error) {
 	// Create the entity
 	entity := tables.NewEntity(0, position, mass) // EntityID will be auto-assigned
+	entity.Kind = tables.KindCircle
+	entity.CreatedAt = timestamp
 
 	// Create the circle
 	direction := types.NewDbVector2(0, 1) // Default direction: up
 	circle := tables.NewCircle(entity.EntityID, playerID, direction, 0.0, timestamp)
+	circle.SpawnedAt = timestamp
+	circle.LastMovedAt = timestamp
+	circle.Color = PlayerColor(playerID)
 
 	return entity, circle, nil
 }
 
+// playerColorPalette holds the default circle colors as 0xRRGGBBAA
+var playerColorPalette = [...]uint32{
+	0xe6194bff, 0x3cb44bff, 0xffe119ff, 0x4363d8ff,
+	0xf58231ff, 0x911eb4ff, 0x42d4f4ff, 0xf032e6ff,
+}
+
+// PlayerColor returns the deterministic default circle color for a player
+func PlayerColor(playerID uint32) uint32 {
+	return playerColorPalette[playerID%uint32(len(playerColorPalette))]
+}
+
+// CircleColor returns the color a player's circles should use: the requested color
+// forced to full alpha, or PlayerColor when none was requested (zero)
+func CircleColor(playerID, requested uint32) uint32 {
+	if requested == 0 {
+		return PlayerColor(playerID)
+	}
+	return requested | 0xff
+}
+
 // SpawnPlayerInitialCircle spawns a player's initial circle at a random safe position
 // This matches the Rust and C# implementations exactly
 func SpawnPlayerInitialCircle(playerID uint32, worldSize uint64, rng *rand.Rand, timestamp tables.Timestamp) (*tables.Entity, *tables.Circle, error) {
-	playerStartRadius := constants.MassToRadius(constants.START_PLAYER_MASS)
+	return SpawnPlayerCircleWithMass(playerID, constants.START_PLAYER_MASS, worldSize, rng, timestamp)
+}
+
+// SpawnPlayerCircleWithMass is SpawnPlayerInitialCircle for a circle of the given mass,
+// keeping the circle's own radius clear of the walls
+func SpawnPlayerCircleWithMass(playerID uint32, mass uint32, worldSize uint64, rng *rand.Rand, timestamp tables.Timestamp) (*tables.Entity, *tables.Circle, error) {
+	if err := ValidateSpawnMass(mass, worldSize); err != nil {
+		return nil, nil, err
+	}
+	margin := SpawnMargin(constants.MassToRadius(mass))
 	worldSizeFloat := float32(worldSize)
 
 	// Generate random position with safety margin
-	x := RangeFloat32(rng, playerStartRadius, worldSizeFloat-playerStartRadius)
-	y := RangeFloat32(rng, playerStartRadius, worldSizeFloat-playerStartRadius)
+	x := RangeFloat32(rng, margin, worldSizeFloat-margin)
+	y := RangeFloat32(rng, margin, worldSizeFloat-margin)
 
 	position := types.NewDbVector2(x, y)
-	return SpawnCircleAt(playerID, constants.START_PLAYER_MASS, position, timestamp)
+	entity, circle, err := SpawnCircleAt(playerID, mass, position, timestamp)
+	if err == nil {
+		ProtectSpawnedCircle(circle, timestamp)
+	}
+	return entity, circle, err
+}
+
+// SpawnPlayerSafeCircle spawns a player's initial circle at a position chosen by FindSafeSpawn
+func SpawnPlayerSafeCircle(playerID uint32, entities []*tables.Entity, worldSize uint64, rng *rand.Rand, timestamp tables.Timestamp) (*tables.Entity, *tables.Circle, error) {
+	return SpawnPlayerSafeCircleWithMass(playerID, constants.START_PLAYER_MASS, entities, worldSize, rng, timestamp)
+}
+
+// SpawnPlayerSafeCircleWithMass is SpawnPlayerSafeCircle for a circle of the given mass
+func SpawnPlayerSafeCircleWithMass(playerID uint32, mass uint32, entities []*tables.Entity, worldSize uint64, rng *rand.Rand, timestamp tables.Timestamp) (*tables.Entity, *tables.Circle, error) {
+	if err := ValidateSpawnMass(mass, worldSize); err != nil {
+		return nil, nil, err
+	}
+	position := FindSafeSpawn(entities, mass, worldSize, rng)
+	entity, circle, err := SpawnCircleAt(playerID, mass, position, timestamp)
+	if err == nil {
+		ProtectSpawnedCircle(circle, timestamp)
+	}
+	return entity, circle, err
+}
+
+// PlayerStartMass returns the mass player circles spawn with: the configured
+// StartPlayerMassOverride, or START_PLAYER_MASS when that is 0
+func PlayerStartMass() uint32 {
+	if override := constants.GetGlobalConfiguration().StartPlayerMassOverride; override != 0 {
+		return override
+	}
+	return constants.START_PLAYER_MASS
+}
+
+// ValidateSpawnMass checks that a player circle of the given mass can spawn: it needs
+// mass, and its radius plus the SpawnWallPadding margin on both sides must fit the world
+func ValidateSpawnMass(mass uint32, worldSize uint64) error {
+	if mass == 0 {
+		return fmt.Errorf("spawn mass must be greater than 0")
+	}
+	if margin := SpawnMargin(constants.MassToRadius(mass)); 2*margin >= float32(worldSize) {
+		return fmt.Errorf("spawn mass %d is too large for world size %d", mass, worldSize)
+	}
+	return nil
+}
+
+// SpawnMargin returns how far the center of a spawn with the given radius must stay from
+// each wall: the radius itself plus the configured SpawnWallPadding
+func SpawnMargin(radius float32) float32 {
+	return radius + constants.GetGlobalConfiguration().SpawnWallPadding
+}
+
+// ProtectSpawnedCircle makes a freshly spawned player circle invulnerable for the configured
+// SpawnProtectionSec. Protected circles can neither consume nor be consumed.
+func ProtectSpawnedCircle(circle *tables.Circle, timestamp tables.Timestamp) {
+	seconds := constants.GetGlobalConfiguration().SpawnProtectionSec
+	protection := time.Duration(float64(seconds) * float64(time.Second))
+	circle.ProtectedUntil = timestamp.Add(tables.NewTimeDurationFromDuration(protection))
+}
+
+// IsCircleProtected reports whether a circle is still within its spawn protection at now
+func IsCircleProtected(circle *tables.Circle, now tables.Timestamp) bool {
+	return now.Microseconds < circle.ProtectedUntil.Microseconds
+}
+
+// FindSafeSpawn picks a spawn position for a circle of the given mass that does not overlap
+// any larger entity. When SpawnDensityAware is enabled the candidates are drawn from the
+// least populated cell of a coarse grid so new players spread out across the world.
+// If no safe position is found within SPAWN_SAFE_ATTEMPTS the last candidate is returned.
+func FindSafeSpawn(entities []*tables.Entity, mass uint32, worldSize uint64, rng *rand.Rand) types.DbVector2 {
+	config := constants.GetGlobalConfiguration()
+	radius := constants.MassToRadius(mass)
+	margin := SpawnMargin(radius)
+	worldSizeFloat := float32(worldSize)
+
+	minX, maxX := margin, worldSizeFloat-margin
+	minY, maxY := margin, worldSizeFloat-margin
+	if config.SpawnDensityAware {
+		cellSize := worldSizeFloat / constants.SPAWN_DENSITY_GRID_SIZE
+		cellX, cellY := leastPopulatedCell(entities, cellSize, rng)
+		minX = Clamp(float32(cellX)*cellSize, margin, worldSizeFloat-margin)
+		maxX = Clamp(float32(cellX+1)*cellSize, margin, worldSizeFloat-margin)
+		minY = Clamp(float32(cellY)*cellSize, margin, worldSizeFloat-margin)
+		maxY = Clamp(float32(cellY+1)*cellSize, margin, worldSizeFloat-margin)
+	}
+
+	var position types.DbVector2
+	for attempt := 0; attempt < constants.SPAWN_SAFE_ATTEMPTS; attempt++ {
+		position = types.NewDbVector2(RangeFloat32(rng, minX, maxX), RangeFloat32(rng, minY, maxY))
+		if isSafeSpawn(position, radius, mass, entities) {
+			break
+		}
+	}
+	return position
+}
+
+// isSafeSpawn reports whether a circle placed at position would overlap an entity larger than it
+func isSafeSpawn(position types.DbVector2, radius float32, mass uint32, entities []*tables.Entity) bool {
+	for _, entity := range entities {
+		if entity.Mass <= mass {
+			continue
+		}
+		minDistance := radius + constants.MassToRadius(entity.Mass)
+		if position.Sub(entity.Position).SqrMagnitude() < minDistance*minDistance {
+			return false
+		}
+	}
+	return true
+}
+
+// leastPopulatedCell counts entities per cell of the coarse spawn grid and returns the
+// coordinates of the emptiest cell, breaking ties randomly
+func leastPopulatedCell(entities []*tables.Entity, cellSize float32, rng *rand.Rand) (int, int) {
+	const gridSize = constants.SPAWN_DENSITY_GRID_SIZE
+	var counts [gridSize * gridSize]int
+	for _, entity := range entities {
+		cellX := int(Clamp(entity.Position.X/cellSize, 0, gridSize-1))
+		cellY := int(Clamp(entity.Position.Y/cellSize, 0, gridSize-1))
+		counts[cellY*gridSize+cellX]++
+	}
+
+	best := make([]int, 0, len(counts))
+	for cell, count := range counts {
+		switch {
+		case len(best) == 0 || count < counts[best[0]]:
+			best = append(best[:0], cell)
+		case count == counts[best[0]]:
+			best = append(best, cell)
+		}
+	}
+
+	cell := best[rng.Intn(len(best))]
+	return cell % gridSize, cell / gridSize
 }
 
 // SpawnFoodEntity creates a new food entity at a random position
-func SpawnFoodEntity(worldSize uint64, rng *rand.Rand) (*tables.Entity, *tables.Food, error) {
+// With a nearby grid the position is resampled up to FOOD_SPACING_ATTEMPTS times until no
+// food in the grid is within MinFoodSpacing and the food overlaps none of the grid's
+// circles; the last candidate is used if none is, and is added to the grid. A nil grid
+// skips both checks.
+func SpawnFoodEntity(worldSize uint64, rng *rand.Rand, timestamp tables.Timestamp, nearby *SpatialGrid) (*tables.Entity, *tables.Food, error) {
 	config := constants.GetGlobalConfiguration()
 
 	// Random mass between min and max
 	foodMass := RangeUint32(rng, config.FoodMassMin, config.FoodMassMax)
 	foodRadius := constants.MassToRadius(foodMass)
+	margin := SpawnMargin(foodRadius)
 	worldSizeFloat := float32(worldSize)
 
 	// Generate random position with safety margin
-	x := RangeFloat32(rng, foodRadius, worldSizeFloat-foodRadius)
-	y := RangeFloat32(rng, foodRadius, worldSizeFloat-foodRadius)
+	var position types.DbVector2
+	for attempt := 0; attempt < constants.FOOD_SPACING_ATTEMPTS; attempt++ {
+		x := RangeFloat32(rng, margin, worldSizeFloat-margin)
+		y := RangeFloat32(rng, margin, worldSizeFloat-margin)
+		position = types.NewDbVector2(x, y)
+		if nearby == nil || (!nearby.AnyWithin(position, config.MinFoodSpacing) && !nearby.OverlapsCircle(position, foodRadius)) {
+			break
+		}
+	}
+	if nearby != nil {
+		nearby.Add(position)
+	}
 
-	position := types.NewDbVector2(x, y)
 	entity := tables.NewEntity(0, position, foodMass) // EntityID will be auto-assigned
+	entity.Kind = tables.KindFood
+	entity.CreatedAt = timestamp
 	food := tables.NewFood(entity.EntityID)
 
 	return entity, food, nil
 }
 
+// NewFoodSpacingGrid returns a SpatialGrid for SpawnFoodEntity holding the given food
+// positions to keep MinFoodSpacing against and, with FoodAvoidCircles, the given circles
+// to stay out of. It is nil when both checks are disabled.
+func NewFoodSpacingGrid(food, circles []*tables.Entity) *SpatialGrid {
+	config := constants.GetGlobalConfiguration()
+	if config.MinFoodSpacing <= 0 && !config.FoodAvoidCircles {
+		return nil
+	}
+
+	cellSize := constants.FOOD_SPAWN_GRID_CELL
+	if config.MinFoodSpacing > 0 {
+		cellSize = config.MinFoodSpacing
+	}
+	grid := NewSpatialGrid(cellSize)
+	for _, entity := range food {
+		grid.Add(entity.Position)
+	}
+	if config.FoodAvoidCircles {
+		for _, entity := range circles {
+			grid.AddCircle(entity.Position, constants.MassToRadius(entity.Mass))
+		}
+	}
+	return grid
+}
+
+// StaleFoodDatabase is the set of operations DespawnStaleFood needs
+type StaleFoodDatabase interface {
+	GetAllEntities() ([]*tables.Entity, error)
+	DestroyEntity(entityID uint32) error
+}
+
+// DespawnStaleFood destroys food that has gone uneaten for longer than ttl and returns
+// how many were removed. Food without a CreatedAt has no known age and is kept.
+func DespawnStaleFood(db StaleFoodDatabase, ttl time.Duration, now tables.Timestamp) (int, error) {
+	entities, err := db.GetAllEntities()
+	if err != nil {
+		return 0, err
+	}
+
+	despawned := 0
+	for _, entity := range entities {
+		if entity.Kind != tables.KindFood || entity.CreatedAt.Microseconds == 0 {
+			continue
+		}
+		if now.Sub(entity.CreatedAt).ToDuration() <= ttl {
+			continue
+		}
+		if err := DestroyEntity(db.DestroyEntity, entity.EntityID); err != nil {
+			return despawned, err
+		}
+		despawned++
+	}
+	return despawned, nil
+}
+
+// EffectiveFoodTarget returns how much food the world should hold for the given number of
+// active players: TargetFoodCount, raised to FoodPerPlayer per player in crowded arenas
+func EffectiveFoodTarget(playerCount uint64, config *constants.Configuration) uint64 {
+	target := uint64(config.TargetFoodCount)
+	if scaled := playerCount * uint64(config.FoodPerPlayer); scaled > target {
+		return scaled
+	}
+	return target
+}
+
+// SeedDatabase is the set of insert operations SeedWorld needs.
+// Inserts are expected to assign auto-increment ids back onto the passed rows,
+// as the reducers DatabaseContext does.
+type SeedDatabase interface {
+	InsertPlayer(player *tables.Player) error
+	InsertEntity(entity *tables.Entity) error
+	InsertCircle(circle *tables.Circle) error
+	InsertFood(food *tables.Food) error
+}
+
+// SeedOptions describes the world SeedWorld populates
+type SeedOptions struct {
+	Players          int
+	CirclesPerPlayer int
+	Food             int
+	WorldSize        uint64
+	Timestamp        tables.Timestamp
+}
+
+// SeedResult holds the ids of everything SeedWorld created, in creation order
+type SeedResult struct {
+	PlayerIDs       []uint32
+	CircleEntityIDs []uint32
+	FoodEntityIDs   []uint32
+}
+
+// SeedWorld spawns players, their circles and food in one call, for setting up tests
+// and benchmarks. Players get deterministic identities derived from their index so
+// seeding the same options with the same rng always produces the same world.
+func SeedWorld(db SeedDatabase, opts SeedOptions, rng *rand.Rand) (*SeedResult, error) {
+	result := &SeedResult{}
+	var seededCircles []*tables.Entity
+
+	for i := 0; i < opts.Players; i++ {
+		var identity [16]byte
+		binary.BigEndian.PutUint32(identity[12:], uint32(i+1))
+		player := tables.NewPlayer(tables.NewIdentity(identity), 0, fmt.Sprintf("Seed%d", i+1))
+		player.LastSeen = opts.Timestamp
+		if err := db.InsertPlayer(player); err != nil {
+			return result, fmt.Errorf("failed to insert seed player %d: %w", i+1, err)
+		}
+		result.PlayerIDs = append(result.PlayerIDs, player.PlayerID)
+
+		for c := 0; c < opts.CirclesPerPlayer; c++ {
+			entity, circle, err := SpawnPlayerInitialCircle(player.PlayerID, opts.WorldSize, rng, opts.Timestamp)
+			if err != nil {
+				return result, err
+			}
+			if err := db.InsertEntity(entity); err != nil {
+				return result, fmt.Errorf("failed to insert seed circle entity: %w", err)
+			}
+			circle.EntityID = entity.EntityID
+			if err := db.InsertCircle(circle); err != nil {
+				return result, fmt.Errorf("failed to insert seed circle: %w", err)
+			}
+			result.CircleEntityIDs = append(result.CircleEntityIDs, entity.EntityID)
+			seededCircles = append(seededCircles, entity)
+		}
+	}
+
+	foodGrid := NewFoodSpacingGrid(nil, seededCircles)
+	for i := 0; i < opts.Food; i++ {
+		entity, food, err := SpawnFoodEntity(opts.WorldSize, rng, opts.Timestamp, foodGrid)
+		if err != nil {
+			return result, err
+		}
+		if err := db.InsertEntity(entity); err != nil {
+			return result, fmt.Errorf("failed to insert seed food entity: %w", err)
+		}
+		food.EntityID = entity.EntityID
+		if err := db.InsertFood(food); err != nil {
+			return result, fmt.Errorf("failed to insert seed food: %w", err)
+		}
+		result.FoodEntityIDs = append(result.FoodEntityIDs, entity.EntityID)
+	}
+
+	return result, nil
+}
+
 // DestroyEntityIDs returns the entity IDs that should be deleted when destroying an entity
 // This matches the C# and Rust implementations
 func DestroyEntityIDs(entityID uint32) []EntityDeletion {
@@ -148,8 +596,10 @@ func DestroyEntity(destroyFunc DestroyEntityFunc, entityID uint32) error {
 }
 
 // ScheduleConsumeEntity creates a timer for entity consumption
+// The configured ConsumeDelay is added to the timestamp so clients can animate the bite
 func ScheduleConsumeEntity(consumerID, consumedID uint32, timestamp tables.Timestamp) *tables.ConsumeEntityTimer {
-	scheduleAt := tables.NewScheduleAtTime(timestamp)
+	delay := constants.GetGlobalConfiguration().ConsumeDelay
+	scheduleAt := tables.NewScheduleAtTime(timestamp.Add(tables.NewTimeDurationFromDuration(delay)))
 	return &tables.ConsumeEntityTimer{
 		ScheduledID:      0, // Will be auto-assigned
 		ScheduledAt:      scheduleAt,
@@ -187,6 +637,15 @@ func NewSeededRNG(seed int64) *rand.Rand {
 	return rand.New(rand.NewSource(seed))
 }
 
+// DeriveSeed mixes a world seed with a per-call counter into an RNG seed using the
+// SplitMix64 finalizer, so consecutive counters give unrelated random streams
+func DeriveSeed(seed, counter uint64) int64 {
+	z := seed + (counter+1)*0x9E3779B97F4A7C15
+	z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+	return int64(z ^ (z >> 31))
+}
+
 // Collision Detection Optimizations
 // These functions provide optimized collision detection for performance
 
@@ -195,6 +654,152 @@ type QuadrantBounds struct {
 	MinX, MinY, MaxX, MaxY float32
 }
 
+// SpatialGrid buckets positions into square cells so proximity queries only scan
+// the cells around the query point instead of every position. Circles are bucketed by
+// their center and kept apart from plain positions, as are entities.
+type SpatialGrid struct {
+	CellSize  float32
+	cells     map[[2]int32][]types.DbVector2
+	circles   map[[2]int32][]gridCircle
+	maxRadius float32
+
+	entities             map[[2]int32][]*tables.Entity
+	entityMin, entityMax [2]int32
+}
+
+// gridCircle is a circle recorded in a SpatialGrid
+type gridCircle struct {
+	center types.DbVector2
+	radius float32
+}
+
+// NewSpatialGrid creates an empty grid with the given cell size
+func NewSpatialGrid(cellSize float32) *SpatialGrid {
+	return &SpatialGrid{
+		CellSize: cellSize,
+		cells:    make(map[[2]int32][]types.DbVector2),
+		circles:  make(map[[2]int32][]gridCircle),
+		entities: make(map[[2]int32][]*tables.Entity),
+	}
+}
+
+// cell returns the coordinates of the cell containing position
+func (g *SpatialGrid) cell(position types.DbVector2) [2]int32 {
+	return [2]int32{int32(math.Floor(float64(position.X / g.CellSize))), int32(math.Floor(float64(position.Y / g.CellSize)))}
+}
+
+// Add records a position in the grid
+func (g *SpatialGrid) Add(position types.DbVector2) {
+	key := g.cell(position)
+	g.cells[key] = append(g.cells[key], position)
+}
+
+// AnyWithin reports whether any position in the grid is closer than distance to position
+func (g *SpatialGrid) AnyWithin(position types.DbVector2, distance float32) bool {
+	reach := int32(math.Ceil(float64(distance / g.CellSize)))
+	center := g.cell(position)
+	for dx := -reach; dx <= reach; dx++ {
+		for dy := -reach; dy <= reach; dy++ {
+			for _, other := range g.cells[[2]int32{center[0] + dx, center[1] + dy}] {
+				if position.DistanceSquared(other) < distance*distance {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// AddCircle records a circle in the grid
+func (g *SpatialGrid) AddCircle(center types.DbVector2, radius float32) {
+	key := g.cell(center)
+	g.circles[key] = append(g.circles[key], gridCircle{center: center, radius: radius})
+	g.maxRadius = max(g.maxRadius, radius)
+}
+
+// OverlapsCircle reports whether a circle of the given radius at position would overlap
+// any circle in the grid
+func (g *SpatialGrid) OverlapsCircle(position types.DbVector2, radius float32) bool {
+	if len(g.circles) == 0 {
+		return false
+	}
+	reach := int32(math.Ceil(float64((radius + g.maxRadius) / g.CellSize)))
+	center := g.cell(position)
+	for dx := -reach; dx <= reach; dx++ {
+		for dy := -reach; dy <= reach; dy++ {
+			for _, other := range g.circles[[2]int32{center[0] + dx, center[1] + dy}] {
+				if distance := radius + other.radius; position.DistanceSquared(other.center) < distance*distance {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// AddEntity records an entity in the grid by its center
+func (g *SpatialGrid) AddEntity(entity *tables.Entity) {
+	key := g.cell(entity.Position)
+	if len(g.entities) == 0 {
+		g.entityMin, g.entityMax = key, key
+	}
+	g.entities[key] = append(g.entities[key], entity)
+	g.entityMin = [2]int32{min(g.entityMin[0], key[0]), min(g.entityMin[1], key[1])}
+	g.entityMax = [2]int32{max(g.entityMax[0], key[0]), max(g.entityMax[1], key[1])}
+}
+
+// NearestEntity returns the grid entity whose center is closest to position among those
+// accepted by filter, with its distance. Ties go to the lowest entity id. The search scans
+// rings of cells outward from position and stops once no unscanned cell can hold anything
+// closer. found is false when no entity passes the filter.
+func (g *SpatialGrid) NearestEntity(position types.DbVector2, filter func(*tables.Entity) bool) (nearest *tables.Entity, distance float32, found bool) {
+	if len(g.entities) == 0 {
+		return nil, 0, false
+	}
+	center := g.cell(position)
+	maxRing := max(
+		abs32(center[0]-g.entityMin[0]), abs32(g.entityMax[0]-center[0]),
+		abs32(center[1]-g.entityMin[1]), abs32(g.entityMax[1]-center[1]),
+	)
+
+	var bestSq float32
+	for ring := int32(0); ring <= maxRing; ring++ {
+		// Every cell in this ring or beyond is at least ring-1 cells away from position
+		if reach := float32(ring-1) * g.CellSize; nearest != nil && ring > 0 && bestSq < reach*reach {
+			break
+		}
+		for dx := -ring; dx <= ring; dx++ {
+			for dy := -ring; dy <= ring; dy++ {
+				if max(abs32(dx), abs32(dy)) != ring {
+					continue
+				}
+				for _, entity := range g.entities[[2]int32{center[0] + dx, center[1] + dy}] {
+					distSq := position.DistanceSquared(entity.Position)
+					if nearest != nil && (distSq > bestSq || (distSq == bestSq && entity.EntityID > nearest.EntityID)) {
+						continue
+					}
+					if filter != nil && !filter(entity) {
+						continue
+					}
+					nearest, bestSq = entity, distSq
+				}
+			}
+		}
+	}
+	if nearest == nil {
+		return nil, 0, false
+	}
+	return nearest, float32(math.Sqrt(float64(bestSq))), true
+}
+
+// abs32 returns the absolute value of v
+func abs32(v int32) int32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
 // EntityBounds calculates the bounding box for an entity
 func EntityBounds(entity *tables.Entity) QuadrantBounds {
 	radius := constants.MassToRadius(entity.Mass)
@@ -241,6 +846,40 @@ func ClampPositionToWorld(position types.DbVector2, radius float32, worldSize ui
 	)
 }
 
+// MigrateEntitiesToWorld rescales entity positions proportionally from a world of oldWorldSize
+// to one of newWorldSize, preserving the relative layout, then clamps them inside the new bounds.
+// Entities are updated in place; the ones whose position changed are returned so the caller
+// can write them back to the database.
+func MigrateEntitiesToWorld(entities []*tables.Entity, oldWorldSize, newWorldSize uint64) []*tables.Entity {
+	if oldWorldSize == 0 {
+		return nil
+	}
+
+	ratio := float32(newWorldSize) / float32(oldWorldSize)
+	var moved []*tables.Entity
+	for _, entity := range entities {
+		radius := constants.MassToRadius(entity.Mass)
+		rescaled := ClampPositionToWorld(entity.Position.Mul(ratio), radius, newWorldSize)
+		if rescaled.Equal(entity.Position) {
+			continue
+		}
+		entity.Position = rescaled
+		moved = append(moved, entity)
+	}
+	return moved
+}
+
+// DistanceToWorldEdge returns the distance from a position to the nearest world wall.
+// Positions outside the world return a negative distance measuring how far out they are.
+func DistanceToWorldEdge(position types.DbVector2, worldSize uint64) float32 {
+	worldSizeFloat := float32(worldSize)
+	distance := position.X
+	distance = float32(math.Min(float64(distance), float64(worldSizeFloat-position.X)))
+	distance = float32(math.Min(float64(distance), float64(position.Y)))
+	distance = float32(math.Min(float64(distance), float64(worldSizeFloat-position.Y)))
+	return distance
+}
+
 // Clamp constrains a value between min and max
 func Clamp(value, min, max float32) float32 {
 	if value < min {
@@ -253,15 +892,120 @@ func Clamp(value, min, max float32) float32 {
 }
 
 // UpdateCirclePosition updates a circle's position based on its movement
+// Speed never drops below the configured MinMoveSpeed so the largest circles remain playable,
+// and no call moves the entity farther than the configuration's MaxMoveDistance allows
 func UpdateCirclePosition(entity *tables.Entity, direction types.DbVector2, deltaTime float32, worldSize uint64) types.DbVector2 {
+	config := constants.GetGlobalConfiguration()
 	speed := constants.MassToMaxMoveSpeed(entity.Mass)
-	velocity := direction.Mul(speed * deltaTime)
+	if minSpeed := config.MinMoveSpeed; speed < minSpeed {
+		speed = minSpeed
+	}
+	velocity := direction.Mul(speed * deltaTime).ClampMagnitude(config.MaxMoveDistance(deltaTime))
 	newPosition := entity.Position.Add(velocity)
 
 	radius := constants.MassToRadius(entity.Mass)
 	return ClampPositionToWorld(newPosition, radius, worldSize)
 }
 
+// FixedTimestep accumulates elapsed time and hands it out in whole physics substeps,
+// carrying any remainder over to the next call
+type FixedTimestep struct {
+	Step        time.Duration
+	accumulated time.Duration
+}
+
+// NewFixedTimestep creates an accumulator that hands out substeps of the given length
+func NewFixedTimestep(step time.Duration) *FixedTimestep {
+	return &FixedTimestep{Step: step}
+}
+
+// Advance adds elapsed time and returns how many whole substeps are now due
+func (f *FixedTimestep) Advance(elapsed time.Duration) int {
+	f.accumulated += elapsed
+	steps := int(f.accumulated / f.Step)
+	f.accumulated -= time.Duration(steps) * f.Step
+	return steps
+}
+
+// ForceAccumulator sums per-entity forces over one movement tick. Reset empties it
+// without releasing its storage, so a single accumulator can be reused every tick
+// instead of allocating a fresh map.
+type ForceAccumulator struct {
+	forces map[uint32]types.DbVector2
+}
+
+// NewForceAccumulator creates an accumulator with room for capacity entities
+func NewForceAccumulator(capacity int) *ForceAccumulator {
+	return &ForceAccumulator{forces: make(map[uint32]types.DbVector2, capacity)}
+}
+
+// Add adds force to the total accumulated for id
+func (f *ForceAccumulator) Add(id uint32, force types.DbVector2) {
+	f.forces[id] = f.forces[id].Add(force)
+}
+
+// Get returns the total force accumulated for id and whether any was added this tick
+func (f *ForceAccumulator) Get(id uint32) (types.DbVector2, bool) {
+	force, ok := f.forces[id]
+	return force, ok
+}
+
+// Reset clears every accumulated force, keeping the storage for the next tick
+func (f *ForceAccumulator) Reset() {
+	clear(f.forces)
+}
+
+// PredictPositionAfter simulates an entity moving in direction for the given number of
+// movement ticks and returns where it ends up, without mutating the entity. Each tick
+// lasts MovePlayersInterval and, like the real movement, is clamped to the world bounds.
+func PredictPositionAfter(entity *tables.Entity, direction types.DbVector2, ticks int, worldSize uint64) types.DbVector2 {
+	deltaTime := float32(constants.GetGlobalConfiguration().MovePlayersInterval.Seconds())
+	simulated := *entity
+	for tick := 0; tick < ticks; tick++ {
+		simulated.Position = UpdateCirclePosition(&simulated, direction, deltaTime, worldSize)
+	}
+	return simulated.Position
+}
+
+// ClampToTurnCone limits how far desired may turn away from current, returning desired
+// rotated back to within maxAngle radians of current when it lies outside that cone.
+// The result keeps desired's magnitude. A zero current or desired returns desired, and
+// a desired pointing exactly opposite current is turned counterclockwise.
+func ClampToTurnCone(current, desired types.DbVector2, maxAngle float32) types.DbVector2 {
+	if current.IsZero() || desired.IsZero() {
+		return desired
+	}
+	maxAngle = Clamp(maxAngle, 0, math.Pi)
+	if current.AngleTo(desired) <= maxAngle {
+		return desired
+	}
+
+	if current.Cross(desired) < 0 {
+		maxAngle = -maxAngle
+	}
+	return current.Normalized().Rotate(maxAngle).Mul(desired.Magnitude())
+}
+
+// FoodMagnetPull returns how far food drifts toward a magnet circle over deltaTime.
+// Circles below FoodMagnetMinMass, food beyond FoodMagnetRadius and a zero
+// FoodMagnetStrength give no pull. The pull weakens linearly with distance and
+// never carries the food past the circle's center.
+func FoodMagnetPull(circle, food *tables.Entity, deltaTime float32) types.DbVector2 {
+	config := constants.GetGlobalConfiguration()
+	if config.FoodMagnetStrength <= 0 || circle.Mass < config.FoodMagnetMinMass {
+		return types.Zero()
+	}
+
+	direction, distance := circle.Position.Sub(food.Position).NormalizedWithMagnitude()
+	if distance == 0 || distance > config.FoodMagnetRadius {
+		return types.Zero()
+	}
+
+	falloff := 1.0 - distance/config.FoodMagnetRadius
+	step := float32(math.Min(float64(config.FoodMagnetStrength*falloff*deltaTime), float64(distance)))
+	return direction.Mul(step)
+}
+
 // Split Circle Physics
 // These functions handle the complex physics for split circles
 
@@ -286,10 +1030,10 @@ func CalculateGravityPull(entityA, entityB *tables.Entity, timeSinceSplit float3
 	radiusSum := constants.MassToRadius(entityA.Mass) + constants.MassToRadius(entityB.Mass)
 	if distanceSqr > radiusSum*radiusSum {
 		gravityMultiplier := 1.0 - timeBeforeRecombining/config.SplitGravPullBeforeRecombineSec
-		distance := float32(math.Sqrt(float64(distanceSqr)))
+		direction, distance := diff.NormalizedWithMagnitude()
 		// Use the original formula: diff.Normalized * (radius_sum - distance)
 		// When distance > radius_sum, this becomes attractive force
-		vec := diff.Normalized().Mul(radiusSum - distance).Mul(gravityMultiplier).Mul(0.05).Div(float32(circleCount))
+		vec := direction.Mul(radiusSum - distance).Mul(gravityMultiplier).Mul(0.05).Div(float32(circleCount))
 		return vec.Div(2.0)
 	}
 
@@ -313,14 +1057,65 @@ func CalculateSeparationForce(entityA, entityB *tables.Entity) types.DbVector2 {
 	radiusSumMultiplied := radiusSum * config.AllowedSplitCircleOverlapPct
 
 	if distanceSqr < radiusSumMultiplied*radiusSumMultiplied {
-		distance := float32(math.Sqrt(float64(distanceSqr)))
-		vec := diff.Normalized().Mul(radiusSum - distance).Mul(config.SelfCollisionSpeed)
+		direction, distance := diff.NormalizedWithMagnitude()
+		speed := SeparationSpeedForOverlap(distance, radiusSumMultiplied)
+		vec := direction.Mul(radiusSum - distance).Mul(speed)
 		return vec.Div(2.0)
 	}
 
 	return types.Zero()
 }
 
+// ResolveOverlapPositions pushes two overlapping entities apart along the minimum
+// translation vector when neither is heavy enough to consume the other. The push is
+// shared in inverse proportion to mass, so equal masses each move half the overlap.
+// Returns true if the positions were changed.
+func ResolveOverlapPositions(a, b *tables.Entity) bool {
+	if CanConsumeEntity(a.Mass, b.Mass) || CanConsumeEntity(b.Mass, a.Mass) {
+		return false
+	}
+
+	mtv := MinimumTranslationVector(a, b)
+	if mtv.SqrMagnitude() == 0 {
+		return false
+	}
+
+	totalMass := float32(a.Mass) + float32(b.Mass)
+	a.Position = a.Position.Add(mtv.Mul(float32(b.Mass) / totalMass))
+	b.Position = b.Position.Sub(mtv.Mul(float32(a.Mass) / totalMass))
+	return true
+}
+
+// MinimumTranslationVector returns the shortest vector that moves a so its circle no
+// longer overlaps b's, or zero when they don't overlap. Concentric entities have no
+// separating axis, so they are pushed apart along +X.
+func MinimumTranslationVector(a, b *tables.Entity) types.DbVector2 {
+	radiusSum := constants.MassToRadius(a.Mass) + constants.MassToRadius(b.Mass)
+	normal, distance := a.Position.Sub(b.Position).NormalizedWithMagnitude()
+	if distance >= radiusSum {
+		return types.Zero()
+	}
+	if distance == 0 {
+		normal = types.NewDbVector2(1.0, 0.0)
+	}
+	return normal.Mul(radiusSum - distance)
+}
+
+// SeparationSpeedForOverlap returns the separation speed multiplier for two split circles
+// whose centers are distance apart, where allowedDistance is the closest they may get
+// before separation kicks in. The speed ramps linearly from SelfCollisionSpeed at the
+// allowed distance up to MaxSelfCollisionSpeed when the circles are fully concentric.
+func SeparationSpeedForOverlap(distance, allowedDistance float32) float32 {
+	config := constants.GetGlobalConfiguration()
+
+	if allowedDistance <= 0 {
+		return config.SelfCollisionSpeed
+	}
+
+	depth := Clamp((allowedDistance-distance)/allowedDistance, 0.0, 1.0)
+	return config.SelfCollisionSpeed + (config.MaxSelfCollisionSpeed-config.SelfCollisionSpeed)*depth
+}
+
 // Validation and Safety Functions
 // These functions provide validation and safety checks
 
@@ -346,6 +1141,9 @@ func ValidateEntityPosition(entity *tables.Entity, worldSize uint64) error {
 	return nil
 }
 
+// DirectionUnitEpsilon is how far a circle direction's magnitude may stray from 1
+const DirectionUnitEpsilon float32 = 1e-3
+
 // ValidateCircleData checks if circle data is consistent
 func ValidateCircleData(circle *tables.Circle, entity *tables.Entity) error {
 	if circle.EntityID != entity.EntityID {
@@ -356,6 +1154,12 @@ func ValidateCircleData(circle *tables.Circle, entity *tables.Entity) error {
 		return fmt.Errorf("circle %d has invalid direction: %v", circle.EntityID, circle.Direction)
 	}
 
+	// A zero direction is how a stopped circle is stored; anything else must be normalized
+	if !circle.Direction.IsZero() && !circle.Direction.IsUnit(DirectionUnitEpsilon) {
+		return fmt.Errorf("circle %d has non-normalized direction: %v (magnitude %f)",
+			circle.EntityID, circle.Direction, circle.Direction.Magnitude())
+	}
+
 	if circle.Speed < 0 || circle.Speed > 1 {
 		return fmt.Errorf("circle %d has invalid speed: %f (must be 0-1)", circle.EntityID, circle.Speed)
 	}
@@ -363,6 +1167,109 @@ func ValidateCircleData(circle *tables.Circle, entity *tables.Entity) error {
 	return nil
 }
 
+// GameStateDatabase is the set of queries ValidateGameState needs
+type GameStateDatabase interface {
+	GetAllEntities() ([]*tables.Entity, error)
+	GetAllCircles() ([]*tables.Circle, error)
+	GetAllFood() ([]*tables.Food, error)
+	GetAllPlayers() ([]*tables.Player, error)
+}
+
+// ValidateGameState checks the invariants that tie the game tables together and returns
+// every violation found, or nil for a consistent state: primary keys are unique, every
+// circle and food row has a backing entity, circles belong to an existing player, and
+// entities have positive mass and lie within the world.
+func ValidateGameState(db GameStateDatabase, worldSize uint64) []error {
+	var errs []error
+
+	entities, err := db.GetAllEntities()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to get entities: %w", err))
+	}
+	circles, err := db.GetAllCircles()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to get circles: %w", err))
+	}
+	food, err := db.GetAllFood()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to get food: %w", err))
+	}
+	players, err := db.GetAllPlayers()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to get players: %w", err))
+	}
+
+	entityIDs := make(map[uint32]bool, len(entities))
+	for _, entity := range entities {
+		if entityIDs[entity.EntityID] {
+			errs = append(errs, fmt.Errorf("duplicate entity id %d", entity.EntityID))
+		}
+		entityIDs[entity.EntityID] = true
+
+		if entity.Mass == 0 {
+			errs = append(errs, fmt.Errorf("entity %d has zero mass", entity.EntityID))
+		}
+		if err := ValidateEntityPosition(entity, worldSize); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	playerIDs := make(map[uint32]bool, len(players))
+	identities := make(map[tables.Identity]bool, len(players))
+	for _, player := range players {
+		if playerIDs[player.PlayerID] {
+			errs = append(errs, fmt.Errorf("duplicate player id %d", player.PlayerID))
+		}
+		if identities[player.Identity] {
+			errs = append(errs, fmt.Errorf("duplicate player identity %s", player.Identity.String()))
+		}
+		playerIDs[player.PlayerID] = true
+		identities[player.Identity] = true
+	}
+
+	circleIDs := make(map[uint32]bool, len(circles))
+	for _, circle := range circles {
+		if circleIDs[circle.EntityID] {
+			errs = append(errs, fmt.Errorf("duplicate circle entity id %d", circle.EntityID))
+		}
+		circleIDs[circle.EntityID] = true
+
+		if !entityIDs[circle.EntityID] {
+			errs = append(errs, fmt.Errorf("circle %d has no backing entity", circle.EntityID))
+		}
+		if !playerIDs[circle.PlayerID] {
+			errs = append(errs, fmt.Errorf("circle %d belongs to missing player %d", circle.EntityID, circle.PlayerID))
+		}
+	}
+
+	foodIDs := make(map[uint32]bool, len(food))
+	for _, row := range food {
+		if foodIDs[row.EntityID] {
+			errs = append(errs, fmt.Errorf("duplicate food entity id %d", row.EntityID))
+		}
+		foodIDs[row.EntityID] = true
+
+		if !entityIDs[row.EntityID] {
+			errs = append(errs, fmt.Errorf("food %d has no backing entity", row.EntityID))
+		}
+	}
+
+	return errs
+}
+
+// ExceedsClockDrift reports whether an input timestamp lies further than tolerance from
+// the server clock, in either direction. A zero tolerance disables the check.
+func ExceedsClockDrift(timestamp, serverNow tables.Timestamp, tolerance time.Duration) bool {
+	if tolerance <= 0 {
+		return false
+	}
+	drift := timestamp.Sub(serverNow).ToDuration()
+	if behind := serverNow.Sub(timestamp).ToDuration(); behind > drift {
+		drift = behind
+	}
+	return drift > tolerance
+}
+
 // Performance Monitoring Hooks
 // These functions provide performance monitoring capabilities
 
@@ -405,11 +1312,18 @@ type PerformanceMetrics struct {
 // Game Logic Helper Functions
 // These functions provide common game logic operations
 
-// CanPlayerSplit checks if a player's circle can split
+// CanPlayerSplit checks if a player's circle can split, counting the circle's own
+// mass as the player's total mass
 func CanPlayerSplit(entity *tables.Entity, currentCircleCount uint32) bool {
+	return CanPlayerSplitWithMass(entity, currentCircleCount, entity.Mass)
+}
+
+// CanPlayerSplitWithMass checks if a player's circle can split, given the player's
+// total mass across all their circles for the circle cap
+func CanPlayerSplitWithMass(entity *tables.Entity, currentCircleCount uint32, totalMass uint32) bool {
 	config := constants.GetGlobalConfiguration()
 
-	if currentCircleCount >= config.MaxCirclesPerPlayer {
+	if currentCircleCount >= MaxCirclesForMass(totalMass, config) {
 		return false
 	}
 
@@ -417,27 +1331,181 @@ func CanPlayerSplit(entity *tables.Entity, currentCircleCount uint32) bool {
 	return entity.Mass >= config.MinMassToSplit*2
 }
 
-// CalculateHalfMass calculates the mass for each half when splitting
+// MaxCirclesForMass returns how many circles a player with totalMass may have.
+// Under CircleCapMassScaled that is one per CircleCapMassPerCircle of mass, at least
+// one and at most MaxCirclesPerPlayer; otherwise it is always MaxCirclesPerPlayer.
+func MaxCirclesForMass(totalMass uint32, config *constants.Configuration) uint32 {
+	if config.CircleCapModel != constants.CircleCapMassScaled || config.CircleCapMassPerCircle == 0 {
+		return config.MaxCirclesPerPlayer
+	}
+	return max(1, min(totalMass/config.CircleCapMassPerCircle, config.MaxCirclesPerPlayer))
+}
+
+// RemainingSplitBudget returns how many more circles a player with currentCount
+// circles and totalMass may create by splitting before reaching MaxCirclesForMass
+func RemainingSplitBudget(currentCount uint32, totalMass uint32, config *constants.Configuration) uint32 {
+	maxCircles := MaxCirclesForMass(totalMass, config)
+	if currentCount >= maxCircles {
+		return 0
+	}
+	return maxCircles - currentCount
+}
+
+// TopByCircleCount returns up to topN player ids ordered by circle count, largest first.
+// Ties are broken by player id so the ranking is stable between calls.
+func TopByCircleCount(counts map[uint32]uint32, topN int) []uint32 {
+	playerIDs := make([]uint32, 0, len(counts))
+	for playerID := range counts {
+		playerIDs = append(playerIDs, playerID)
+	}
+	sort.Slice(playerIDs, func(i, j int) bool {
+		a, b := playerIDs[i], playerIDs[j]
+		if counts[a] != counts[b] {
+			return counts[a] > counts[b]
+		}
+		return a < b
+	})
+
+	if topN < 0 {
+		topN = 0
+	}
+	if len(playerIDs) > topN {
+		playerIDs = playerIDs[:topN]
+	}
+	return playerIDs
+}
+
+// RoundMass converts a fractional mass to a whole one, rounding half to even so that
+// rounding up and down balance out over many conversions. Negative and NaN inputs give
+// 0 and values past the uint32 range saturate at math.MaxUint32.
+func RoundMass(f float32) uint32 {
+	rounded := math.RoundToEven(float64(f))
+	if math.IsNaN(rounded) || rounded <= 0 {
+		return 0
+	}
+	if rounded >= math.MaxUint32 {
+		return math.MaxUint32
+	}
+	return uint32(rounded)
+}
+
+// CalculateHalfMass calculates the mass for the new circle when splitting. The split
+// stays in integers: the original circle keeps originalMass minus this half, so an odd
+// remainder stays with it and no mass is lost.
 func CalculateHalfMass(originalMass uint32) uint32 {
 	return originalMass / 2
 }
 
-// CanConsumeEntity checks if one entity can consume another based on mass ratio
+// CanConsumeEntity checks if one entity can consume another based on mass ratio.
+// With StrictConsumption enabled any strictly lighter entity can be consumed.
 func CanConsumeEntity(consumerMass, consumedMass uint32) bool {
 	config := constants.GetGlobalConfiguration()
+	if config.StrictConsumption {
+		return consumedMass < consumerMass
+	}
 	massRatio := float32(consumedMass) / float32(consumerMass)
 	return massRatio < config.MinimumSafeMassRatio
 }
 
+// FoodMassGain returns the mass a circle gains from eating food of the given mass,
+// scaled by FoodMassMultiplier and rounded with RoundMass
+func FoodMassGain(foodMass uint32) uint32 {
+	return RoundMass(float32(foodMass) * constants.GetGlobalConfiguration().FoodMassMultiplier)
+}
+
+// AddMassSaturating returns a + b, saturating at math.MaxUint32 instead of wrapping.
+// When MaxCircleMass is configured the result is also capped there, though a circle
+// already above the cap never loses mass by consuming.
+func AddMassSaturating(a, b uint32) uint32 {
+	sum := a + b
+	if sum < a {
+		sum = math.MaxUint32
+	}
+
+	maxMass := constants.GetGlobalConfiguration().MaxCircleMass
+	if maxMass != 0 && sum > maxMass {
+		if a > maxMass {
+			return a
+		}
+		return maxMass
+	}
+	return sum
+}
+
+// MassOverflow returns how much of a + b AddMassSaturating would discard because of
+// MaxCircleMass. It is 0 when no cap is configured or the sum fits under it.
+func MassOverflow(a, b uint32) uint32 {
+	maxMass := constants.GetGlobalConfiguration().MaxCircleMass
+	if maxMass == 0 {
+		return 0
+	}
+	kept := uint64(AddMassSaturating(a, b))
+	sum := uint64(a) + uint64(b)
+	if sum <= kept {
+		return 0
+	}
+	if overflow := sum - kept; overflow < math.MaxUint32 {
+		return uint32(overflow)
+	}
+	return math.MaxUint32
+}
+
 // ShouldCircleDecay checks if a circle should lose mass due to decay
 func ShouldCircleDecay(entity *tables.Entity) bool {
 	return entity.Mass > constants.START_PLAYER_MASS
 }
 
-// CalculateDecayedMass calculates the new mass after decay
+// ShouldCircleDecayAt checks if a circle should decay at the given time, additionally
+// requiring the circle to be older than the configured DecayGracePeriodSec
+func ShouldCircleDecayAt(entity *tables.Entity, circle *tables.Circle, now tables.Timestamp) bool {
+	if !ShouldCircleDecay(entity) {
+		return false
+	}
+	config := constants.GetGlobalConfiguration()
+	age := now.Sub(circle.SpawnedAt).ToDuration().Seconds()
+	return age >= float64(config.DecayGracePeriodSec)
+}
+
+// IsDecayExempt reports whether a circle is light enough relative to the largest mass in
+// the world to skip decay. Always false when DecayExemptLeaderFraction is 0.
+func IsDecayExempt(mass, leaderMass uint32, config *constants.Configuration) bool {
+	if config.DecayExemptLeaderFraction <= 0 {
+		return false
+	}
+	return float64(mass) < float64(config.DecayExemptLeaderFraction)*float64(leaderMass)
+}
+
+// CalculateDecayedMass calculates the new mass after decay, rounded with RoundMass
 func CalculateDecayedMass(originalMass uint32) uint32 {
 	// 1% decay per tick (matches Rust and C# implementations)
-	return uint32(float32(originalMass) * 0.99)
+	return decayMass(originalMass, 0.01)
+}
+
+// CalculateCampingDecayedMass is CalculateDecayedMass for a camping circle, which loses
+// CampingDecayMultiplier times the normal 1% per tick
+func CalculateCampingDecayedMass(originalMass uint32) uint32 {
+	return decayMass(originalMass, 0.01*constants.GetGlobalConfiguration().CampingDecayMultiplier)
+}
+
+// decayMass removes rate of a mass, rounded with RoundMass. Below 50 mass 1% is under
+// half a unit and would round away, so decay always takes at least one unit, as the
+// truncating Rust and C# servers do.
+func decayMass(originalMass uint32, rate float32) uint32 {
+	decayed := RoundMass(float32(originalMass) * (1 - rate))
+	if rate > 0 && originalMass > 0 {
+		decayed = min(decayed, originalMass-1)
+	}
+	return decayed
+}
+
+// IsCamping reports whether a circle that last moved at lastMoved has stayed still for
+// at least CampingThresholdSec by now. Always false when CampingThresholdSec is 0.
+func IsCamping(lastMoved, now tables.Timestamp) bool {
+	threshold := constants.GetGlobalConfiguration().CampingThresholdSec
+	if threshold <= 0 {
+		return false
+	}
+	return now.Sub(lastMoved).ToDuration().Seconds() >= float64(threshold)
 }
 
 // ShouldRecombineCircles checks if circles should recombine based on time
@@ -447,22 +1515,189 @@ func ShouldRecombineCircles(lastSplitTime tables.Timestamp, currentTime tables.T
 	return timeSinceSplit.ToDuration().Seconds() >= float64(config.SplitRecombineDelaySec)
 }
 
+// WithinRecombineDistance checks if the gap between the edges of two circles is at most
+// maxDistance. Overlapping circles are always in range, and a maxDistance of 0 puts
+// every pair in range.
+func WithinRecombineDistance(a, b *tables.Entity, maxDistance float32) bool {
+	if maxDistance == 0 {
+		return true
+	}
+	gap := a.Position.Distance(b.Position) - constants.MassToRadius(a.Mass) - constants.MassToRadius(b.Mass)
+	return gap <= maxDistance
+}
+
+// ClosestEntityPair returns the indices of the two entities whose centers are closest,
+// earliest pair first on ties. Both are -1 when there are fewer than two entities.
+func ClosestEntityPair(entities []*tables.Entity) (int, int) {
+	first, second := -1, -1
+	var best float32
+	for i := 0; i < len(entities); i++ {
+		for j := i + 1; j < len(entities); j++ {
+			distance := entities[i].Position.DistanceSquared(entities[j].Position)
+			if first < 0 || distance < best {
+				first, second, best = i, j, distance
+			}
+		}
+	}
+	return first, second
+}
+
+// Rendering Helpers
+// These functions describe how entities are drawn and never affect the simulation
+
+// VisualRadius returns the radius clients should draw entity at now: its MassToRadius
+// plus a pulse that starts at GROWTH_PULSE_SCALE when it last grew at lastGrewAt and
+// fades out linearly over GROWTH_PULSE_DURATION. A zero lastGrewAt means no pulse.
+// It is purely cosmetic and never feeds back into collisions.
+func VisualRadius(entity *tables.Entity, lastGrewAt, now tables.Timestamp) float32 {
+	radius := constants.MassToRadius(entity.Mass)
+	if lastGrewAt.Microseconds == 0 || now.Microseconds < lastGrewAt.Microseconds {
+		return radius
+	}
+
+	elapsed := now.Sub(lastGrewAt).ToDuration()
+	if elapsed >= constants.GROWTH_PULSE_DURATION {
+		return radius
+	}
+	remaining := 1 - float32(elapsed)/float32(constants.GROWTH_PULSE_DURATION)
+	return radius * (1 + constants.GROWTH_PULSE_SCALE*remaining)
+}
+
+// Module Status
+// These types describe the health snapshot reported to operations tooling
+
+// StatusDatabase is the set of queries ModuleStatus needs
+type StatusDatabase interface {
+	GetPlayerCount() (uint64, error)
+	GetEntityCount() (uint64, error)
+	GetFoodCount() (uint64, error)
+}
+
+// RuntimeState is the module state kept outside the database that ModuleStatus reports
+type RuntimeState struct {
+	StartedAt tables.Timestamp // When Init last ran, zero if it hasn't
+	Ticks     uint64           // MoveAllPlayers ticks run since Init
+	Paused    bool             // Whether scheduled ticks are being skipped because the game is idle
+}
+
+// Status is a health snapshot of the module
+type Status struct {
+	Initialized bool    `json:"initialized"`
+	UptimeSec   float64 `json:"uptime_sec"`
+	Players     uint64  `json:"players"`
+	Entities    uint64  `json:"entities"`
+	Food        uint64  `json:"food"`
+	Tick        uint64  `json:"tick"`
+	Paused      bool    `json:"paused"`
+}
+
+// ModuleStatus builds a Status from the database counts and the runtime state at now.
+// Uptime is measured from state.StartedAt and is 0 before Init has run.
+func ModuleStatus(db StatusDatabase, state RuntimeState, now tables.Timestamp) (*Status, error) {
+	players, err := db.GetPlayerCount()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count players: %w", err)
+	}
+	entities, err := db.GetEntityCount()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count entities: %w", err)
+	}
+	food, err := db.GetFoodCount()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count food: %w", err)
+	}
+
+	status := &Status{
+		Initialized: state.StartedAt.Microseconds != 0,
+		Players:     players,
+		Entities:    entities,
+		Food:        food,
+		Tick:        state.Ticks,
+		Paused:      state.Paused,
+	}
+	if status.Initialized && now.Microseconds > state.StartedAt.Microseconds {
+		status.UptimeSec = now.Sub(state.StartedAt).ToDuration().Seconds()
+	}
+	return status, nil
+}
+
+// State Diffs
+// These functions compare game snapshots for delta updates and regression tests
+
+// StateDiff lists how the entities changed between two snapshots. Created and Updated
+// hold the entities as they are in the later snapshot; Deleted holds the ids of entities
+// that are gone. Each list is ordered by entity id.
+type StateDiff struct {
+	Created []*tables.Entity `json:"created"`
+	Updated []*tables.Entity `json:"updated"`
+	Deleted []uint32         `json:"deleted"`
+}
+
+// IsEmpty reports whether the diff records no changes
+func (d *StateDiff) IsEmpty() bool {
+	return len(d.Created) == 0 && len(d.Updated) == 0 && len(d.Deleted) == 0
+}
+
+// DiffSnapshots compares the entities of two snapshots. An entity counts as updated when
+// any of its fields differ. A nil snapshot is treated as an empty world.
+func DiffSnapshots(before, after *tables.GameSnapshot) *StateDiff {
+	previous := make(map[uint32]*tables.Entity)
+	if before != nil {
+		for _, entity := range before.Entities {
+			previous[entity.EntityID] = entity
+		}
+	}
+
+	diff := &StateDiff{Created: []*tables.Entity{}, Updated: []*tables.Entity{}, Deleted: []uint32{}}
+	seen := make(map[uint32]bool, len(previous))
+	if after != nil {
+		for _, entity := range after.Entities {
+			seen[entity.EntityID] = true
+			old, existed := previous[entity.EntityID]
+			switch {
+			case !existed:
+				diff.Created = append(diff.Created, entity)
+			case *old != *entity:
+				diff.Updated = append(diff.Updated, entity)
+			}
+		}
+	}
+	for id := range previous {
+		if !seen[id] {
+			diff.Deleted = append(diff.Deleted, id)
+		}
+	}
+
+	sort.Slice(diff.Created, func(i, j int) bool { return diff.Created[i].EntityID < diff.Created[j].EntityID })
+	sort.Slice(diff.Updated, func(i, j int) bool { return diff.Updated[i].EntityID < diff.Updated[j].EntityID })
+	sort.Slice(diff.Deleted, func(i, j int) bool { return diff.Deleted[i] < diff.Deleted[j] })
+	return diff
+}
+
 // Debug and Development Helpers
 // These functions assist with debugging and development
 
-// EntityDebugInfo returns debug information for an entity
-func EntityDebugInfo(entity *tables.Entity) map[string]interface{} {
+// EntityDebugInfo returns debug information for an entity.
+// The "kind" field comes from entity.Kind; circle is the entity's circle row (nil if it
+// has none) and supplies the owning player.
+func EntityDebugInfo(entity *tables.Entity, circle *tables.Circle) map[string]interface{} {
 	radius := constants.MassToRadius(entity.Mass)
 	speed := constants.MassToMaxMoveSpeed(entity.Mass)
 
-	return map[string]interface{}{
+	info := map[string]interface{}{
 		"entity_id": entity.EntityID,
+		"kind":      entity.Kind.String(),
 		"position":  entity.Position.String(),
 		"mass":      entity.Mass,
 		"radius":    radius,
 		"max_speed": speed,
 		"bounds":    EntityBounds(entity),
 	}
+
+	if circle != nil {
+		info["player_id"] = circle.PlayerID
+	}
+	return info
 }
 
 // CircleDebugInfo returns debug information for a circle
@@ -473,6 +1708,9 @@ func CircleDebugInfo(circle *tables.Circle) map[string]interface{} {
 		"direction":       circle.Direction.String(),
 		"speed":           circle.Speed,
 		"last_split_time": circle.LastSplitTime.String(),
+		"spawned_at":      circle.SpawnedAt.String(),
+		"protected_until": circle.ProtectedUntil.String(),
+		"last_moved_at":   circle.LastMovedAt.String(),
 	}
 }
 
@@ -483,11 +1721,17 @@ func GameStateDebugInfo(entities []*tables.Entity, circles []*tables.Circle, foo
 		totalMass += entity.Mass
 	}
 
+	// An empty world has no average; report 0 rather than NaN
+	avgMass := float32(0)
+	if len(entities) > 0 {
+		avgMass = float32(totalMass) / float32(len(entities))
+	}
+
 	return map[string]interface{}{
 		"entity_count": len(entities),
 		"circle_count": len(circles),
 		"food_count":   len(food),
 		"total_mass":   totalMass,
-		"avg_mass":     float32(totalMass) / float32(len(entities)),
+		"avg_mass":     avgMass,
 	}
 }
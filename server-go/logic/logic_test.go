@@ -2,6 +2,8 @@ package logic
 
 import (
 	"math"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,6 +18,20 @@ func createTestEntity(id uint32, x, y float32, mass uint32) *tables.Entity {
 	return tables.NewEntity(id, position, mass)
 }
 
+// withConfig installs the default configuration with modify applied as the global
+// configuration for the rest of the test, restoring the previous one afterwards
+func withConfig(t *testing.T, modify func(*constants.Configuration)) *constants.Configuration {
+	t.Helper()
+	previous := constants.GetGlobalConfiguration()
+	config := constants.DefaultConfiguration()
+	modify(config)
+	if err := constants.SetGlobalConfiguration(config); err != nil {
+		t.Fatalf("SetGlobalConfiguration failed: %v", err)
+	}
+	t.Cleanup(func() { constants.SetGlobalConfiguration(previous) })
+	return config
+}
+
 func TestIsOverlapping(t *testing.T) {
 	t.Run("Overlapping entities", func(t *testing.T) {
 		// Two circles at the same position should overlap
@@ -85,6 +101,241 @@ func TestIsOverlappingRust(t *testing.T) {
 	})
 }
 
+func TestOverlapDistanceSquared(t *testing.T) {
+	pct := float64(constants.GetGlobalConfiguration().MinOverlapPctToConsume)
+	reference := func(massA, massB uint32, mode OverlapMode) float64 {
+		radiusA := math.Sqrt(math.Max(float64(massA), 1))
+		radiusB := math.Sqrt(math.Max(float64(massB), 1))
+		if mode == OverlapModeMaxRadius {
+			r := math.Max(radiusA, radiusB)
+			return r * r
+		}
+		sum := (radiusA + radiusB) * (1 - pct)
+		return sum * sum
+	}
+
+	masses := [][2]uint32{
+		{0, 0},
+		{1, 1},
+		{1, 2},
+		{15, 4},
+		{1_000_000, 3},
+		{math.MaxUint32 - 1, 1},
+		{math.MaxUint32, math.MaxUint32},
+	}
+	for _, mode := range []OverlapMode{OverlapModeThreshold, OverlapModeMaxRadius} {
+		for _, m := range masses {
+			got := float64(OverlapDistanceSquared(createTestEntity(1, 0, 0, m[0]), createTestEntity(2, 0, 0, m[1]), mode))
+			want := reference(m[0], m[1], mode)
+			if math.Abs(got-want) > want*1e-7 {
+				t.Errorf("%s OverlapDistanceSquared(%d, %d) = %g, want %g", mode, m[0], m[1], got, want)
+			}
+		}
+	}
+
+	t.Run("Shared by overlap checks", func(t *testing.T) {
+		a := createTestEntity(1, 0, 0, 100)
+		b := createTestEntity(2, 0, 0, 25)
+		threshold := OverlapDistanceSquared(a, b, OverlapModeThreshold)
+
+		b.Position = types.NewDbVector2(float32(math.Sqrt(float64(threshold)))*0.999, 0)
+		if !IsOverlapping(a, b) {
+			t.Error("Entities just inside the threshold should overlap")
+		}
+		b.Position = types.NewDbVector2(float32(math.Sqrt(float64(threshold)))*1.001, 0)
+		if IsOverlapping(a, b) {
+			t.Error("Entities just outside the threshold should not overlap")
+		}
+	})
+}
+
+func TestOverlapDepth(t *testing.T) {
+	for _, mode := range []OverlapMode{OverlapModeThreshold, OverlapModeMaxRadius} {
+		t.Run(mode.String(), func(t *testing.T) {
+			a := createTestEntity(1, 0, 0, 100)
+			b := createTestEntity(2, 0, 0, 25)
+			threshold := float32(math.Sqrt(float64(OverlapDistanceSquared(a, b, mode))))
+
+			// Deeply nested: concentric entities overlap by the whole threshold
+			if depth := OverlapDepth(a, b, mode); math.Abs(float64(depth-threshold)) > 1e-4 {
+				t.Errorf("Concentric depth = %f, want %f", depth, threshold)
+			}
+
+			b.Position = types.NewDbVector2(threshold/2, 0)
+			if depth := OverlapDepth(a, b, mode); math.Abs(float64(depth-threshold/2)) > 1e-4 {
+				t.Errorf("Half-nested depth = %f, want %f", depth, threshold/2)
+			}
+
+			// Just touching: right at the threshold the depth is about 0
+			b.Position = types.NewDbVector2(threshold, 0)
+			if depth := OverlapDepth(a, b, mode); depth > 1e-4 {
+				t.Errorf("Touching depth = %f, want ~0", depth)
+			}
+
+			b.Position = types.NewDbVector2(threshold*2, 0)
+			if depth := OverlapDepth(a, b, mode); depth != 0 {
+				t.Errorf("Separated depth = %f, want 0", depth)
+			}
+			if IsOverlappingWithMode(a, b, mode) {
+				t.Error("Separated entities should not overlap")
+			}
+		})
+	}
+}
+
+func TestCanConsume(t *testing.T) {
+	t.Run("Mass ok and overlapping", func(t *testing.T) {
+		consumer := createTestEntity(1, 0, 0, 100)
+		consumed := createTestEntity(2, 1, 0, 10)
+
+		if !CanConsume(consumer, consumed, OverlapModeThreshold) {
+			t.Error("Larger overlapping entity should be able to consume")
+		}
+	})
+
+	t.Run("Mass ok but not overlapping", func(t *testing.T) {
+		consumer := createTestEntity(1, 0, 0, 100)
+		consumed := createTestEntity(2, 500, 500, 10)
+
+		if CanConsume(consumer, consumed, OverlapModeThreshold) {
+			t.Error("Should not consume an entity that isn't overlapping")
+		}
+		if CanConsume(consumer, consumed, OverlapModeMaxRadius) {
+			t.Error("Should not consume an entity that isn't overlapping (max radius mode)")
+		}
+	})
+
+	t.Run("Overlapping but too close in mass", func(t *testing.T) {
+		consumer := createTestEntity(1, 0, 0, 100)
+		consumed := createTestEntity(2, 1, 0, 95)
+
+		if !IsOverlapping(consumer, consumed) {
+			t.Fatal("Test entities should overlap")
+		}
+		if CanConsume(consumer, consumed, OverlapModeThreshold) {
+			t.Error("Should not consume an entity of nearly equal mass")
+		}
+	})
+
+	t.Run("Modes use their own overlap rule", func(t *testing.T) {
+		// Radii 10 and 4: the threshold rule allows up to (10 + 4) * 0.9 = 12.6,
+		// the max radius rule only up to 10
+		consumer := createTestEntity(1, 0, 0, 100)
+		consumed := createTestEntity(2, 12, 0, 16)
+
+		if !CanConsume(consumer, consumed, OverlapModeThreshold) {
+			t.Error("Threshold mode should allow consumption at distance 12")
+		}
+		if CanConsume(consumer, consumed, OverlapModeMaxRadius) {
+			t.Error("Max radius mode should not allow consumption at distance 12")
+		}
+	})
+}
+
+func TestMinimumTranslationVector(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     *tables.Entity
+		expected types.DbVector2
+	}{
+		// Radius 10 each, centers 12 apart along X: a moves 8 away from b
+		{"Partial overlap", createTestEntity(1, 100, 100, 100), createTestEntity(2, 112, 100, 100), types.NewDbVector2(-8, 0)},
+		// Radius 20 around radius 5, centers 5 apart along Y: a must clear 25 - 5
+		{"Full containment", createTestEntity(1, 100, 105, 25), createTestEntity(2, 100, 100, 400), types.NewDbVector2(0, 20)},
+		{"Identical positions", createTestEntity(1, 50, 50, 100), createTestEntity(2, 50, 50, 100), types.NewDbVector2(20, 0)},
+		{"Touching", createTestEntity(1, 100, 100, 100), createTestEntity(2, 120, 100, 100), types.Zero()},
+		{"Apart", createTestEntity(1, 100, 100, 100), createTestEntity(2, 300, 300, 100), types.Zero()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mtv := MinimumTranslationVector(tt.a, tt.b)
+			if math.Abs(float64(mtv.X-tt.expected.X)) > 0.001 || math.Abs(float64(mtv.Y-tt.expected.Y)) > 0.001 {
+				t.Errorf("MinimumTranslationVector = %s, want %s", mtv.String(), tt.expected.String())
+			}
+			if mtv.SqrMagnitude() > 0 {
+				moved := *tt.a
+				moved.Position = moved.Position.Add(mtv)
+				if distance := moved.Position.Distance(tt.b.Position); distance < constants.MassToRadius(tt.a.Mass)+constants.MassToRadius(tt.b.Mass)-0.001 {
+					t.Errorf("a still overlaps b after applying the MTV, distance %f", distance)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveOverlapPositions(t *testing.T) {
+	t.Run("Equal masses pushed apart by the MTV", func(t *testing.T) {
+		// Radius 10 each, centers 12 apart: overlap depth 8 shared equally
+		a := createTestEntity(1, 100, 100, 100)
+		b := createTestEntity(2, 112, 100, 100)
+
+		if !ResolveOverlapPositions(a, b) {
+			t.Fatal("Overlapping equal masses should be separated")
+		}
+		if math.Abs(float64(a.Position.X-96)) > 0.001 || math.Abs(float64(b.Position.X-116)) > 0.001 {
+			t.Errorf("Expected X positions 96 and 116, got %f and %f", a.Position.X, b.Position.X)
+		}
+		if a.Position.Y != 100 || b.Position.Y != 100 {
+			t.Errorf("Y positions should not change: got %f and %f", a.Position.Y, b.Position.Y)
+		}
+		if distance := a.Position.Distance(b.Position); math.Abs(float64(distance-20)) > 0.001 {
+			t.Errorf("Circles should end up exactly touching, got distance %f", distance)
+		}
+	})
+
+	t.Run("Unequal masses move in inverse proportion", func(t *testing.T) {
+		// Masses 100 and 90 are too close for either to consume the other
+		a := createTestEntity(1, 100, 100, 100)
+		b := createTestEntity(2, 100, 110, 90)
+		depth := constants.MassToRadius(100) + constants.MassToRadius(90) - 10
+
+		if !ResolveOverlapPositions(a, b) {
+			t.Fatal("Overlapping evenly matched entities should be separated")
+		}
+		movedA := 100 - a.Position.Y
+		movedB := b.Position.Y - 110
+		if math.Abs(float64(movedA+movedB-depth)) > 0.001 {
+			t.Errorf("Total push should equal the overlap depth %f, got %f", depth, movedA+movedB)
+		}
+		if movedA >= movedB {
+			t.Errorf("Heavier entity should move less: moved %f vs %f", movedA, movedB)
+		}
+	})
+
+	t.Run("Consumable pair left alone", func(t *testing.T) {
+		a := createTestEntity(1, 100, 100, 100)
+		b := createTestEntity(2, 105, 100, 20)
+
+		if ResolveOverlapPositions(a, b) {
+			t.Error("Pairs where one can consume the other should not be separated")
+		}
+		if a.Position.X != 100 || b.Position.X != 105 {
+			t.Error("Positions should be unchanged")
+		}
+	})
+
+	t.Run("Non-overlapping pair left alone", func(t *testing.T) {
+		a := createTestEntity(1, 100, 100, 100)
+		b := createTestEntity(2, 130, 100, 100)
+
+		if ResolveOverlapPositions(a, b) {
+			t.Error("Non-overlapping entities should not be moved")
+		}
+	})
+
+	t.Run("Concentric entities separated", func(t *testing.T) {
+		a := createTestEntity(1, 100, 100, 100)
+		b := createTestEntity(2, 100, 100, 100)
+
+		if !ResolveOverlapPositions(a, b) {
+			t.Fatal("Concentric entities should be separated")
+		}
+		if distance := a.Position.Distance(b.Position); math.Abs(float64(distance-20)) > 0.001 {
+			t.Errorf("Concentric entities should end up touching, got distance %f", distance)
+		}
+	})
+}
+
 func TestCalculateCenterOfMass(t *testing.T) {
 	t.Run("Empty entities", func(t *testing.T) {
 		result := CalculateCenterOfMass([]*tables.Entity{})
@@ -167,6 +418,9 @@ func TestSpawnCircleAt(t *testing.T) {
 		if entity.Mass != mass {
 			t.Errorf("Entity mass wrong: got %d, expected %d", entity.Mass, mass)
 		}
+		if entity.Kind != tables.KindCircle {
+			t.Errorf("Entity kind wrong: got %s, expected circle", entity.Kind)
+		}
 
 		// Check circle properties
 		if circle.PlayerID != playerID {
@@ -239,12 +493,150 @@ func TestSpawnPlayerInitialCircle(t *testing.T) {
 	})
 }
 
+func TestSpawnPlayerCircleWithMass(t *testing.T) {
+	worldSize := uint64(100)
+
+	t.Run("Keeps the overridden radius clear of the walls", func(t *testing.T) {
+		// Radius 40 leaves centers only in [40, 60]; START_PLAYER_MASS would allow almost anywhere
+		mass := uint32(1600)
+		radius := constants.MassToRadius(mass)
+		rng := NewSeededRNG(3)
+		for i := 0; i < 50; i++ {
+			entity, _, err := SpawnPlayerCircleWithMass(1, mass, worldSize, rng, tables.Timestamp{})
+			if err != nil {
+				t.Fatalf("SpawnPlayerCircleWithMass failed: %v", err)
+			}
+			if entity.Mass != mass {
+				t.Fatalf("Spawned mass = %d, want %d", entity.Mass, mass)
+			}
+			if err := ValidateEntityPosition(entity, worldSize); err != nil {
+				t.Fatalf("Spawn not clear of the walls for radius %f: %v", radius, err)
+			}
+		}
+	})
+
+	t.Run("Safe spawn avoids larger entities using its own radius", func(t *testing.T) {
+		mass := uint32(400)
+		giant := createTestEntity(1, 500, 500, 10000)
+		rng := NewSeededRNG(5)
+		for i := 0; i < 20; i++ {
+			entity, _, err := SpawnPlayerSafeCircleWithMass(1, mass, []*tables.Entity{giant}, 1000, rng, tables.Timestamp{})
+			if err != nil {
+				t.Fatalf("SpawnPlayerSafeCircleWithMass failed: %v", err)
+			}
+			if !isSafeSpawn(entity.Position, constants.MassToRadius(mass), mass, []*tables.Entity{giant}) {
+				t.Errorf("Spawn at %v overlaps the larger entity at radius %f", entity.Position, constants.MassToRadius(mass))
+			}
+		}
+	})
+
+	t.Run("Rejects invalid masses", func(t *testing.T) {
+		if _, _, err := SpawnPlayerCircleWithMass(1, 0, worldSize, NewSeededRNG(1), tables.Timestamp{}); err == nil {
+			t.Error("Zero mass should be rejected")
+		}
+		if _, _, err := SpawnPlayerCircleWithMass(1, 2500, worldSize, NewSeededRNG(1), tables.Timestamp{}); err == nil {
+			t.Error("A circle as wide as the world should be rejected")
+		}
+	})
+
+	t.Run("PlayerStartMass", func(t *testing.T) {
+		if got := PlayerStartMass(); got != constants.START_PLAYER_MASS {
+			t.Errorf("PlayerStartMass() = %d, want START_PLAYER_MASS without an override", got)
+		}
+		withConfig(t, func(config *constants.Configuration) {
+			config.StartPlayerMassOverride = 100
+		})
+		if got := PlayerStartMass(); got != 100 {
+			t.Errorf("PlayerStartMass() = %d, want the override 100", got)
+		}
+	})
+}
+
+func TestFindSafeSpawn(t *testing.T) {
+	worldSize := uint64(1000)
+	radius := constants.MassToRadius(constants.START_PLAYER_MASS)
+
+	t.Run("Avoids large entities", func(t *testing.T) {
+		// A huge entity covering most of the world leaves only the corners safe
+		giant := createTestEntity(1, 500, 500, 200000)
+		entities := []*tables.Entity{giant}
+		rng := NewSeededRNG(7)
+
+		safe := 0
+		for i := 0; i < 50; i++ {
+			position := FindSafeSpawn(entities, constants.START_PLAYER_MASS, worldSize, rng)
+			if isSafeSpawn(position, radius, constants.START_PLAYER_MASS, entities) {
+				safe++
+			}
+		}
+		if safe < 45 {
+			t.Errorf("Expected nearly all spawns to avoid the giant, got %d/50 safe", safe)
+		}
+	})
+
+	t.Run("Ignores smaller entities", func(t *testing.T) {
+		entities := []*tables.Entity{createTestEntity(1, 500, 500, constants.START_PLAYER_MASS)}
+		if !isSafeSpawn(types.NewDbVector2(500, 500), radius, constants.START_PLAYER_MASS, entities) {
+			t.Error("Entities no larger than the spawning circle should not block a spawn")
+		}
+	})
+
+	t.Run("Density-aware spawns skew toward the empty side", func(t *testing.T) {
+		withConfig(t, func(config *constants.Configuration) {
+			config.SpawnDensityAware = true
+		})
+
+		// Crowd the left half of the world with food-sized entities
+		rng := NewSeededRNG(11)
+		var entities []*tables.Entity
+		for i := 0; i < 400; i++ {
+			x := RangeFloat32(rng, 0, float32(worldSize)/2)
+			y := RangeFloat32(rng, 0, float32(worldSize))
+			entities = append(entities, createTestEntity(uint32(i+1), x, y, 2))
+		}
+
+		right := 0
+		for i := 0; i < 200; i++ {
+			position := FindSafeSpawn(entities, constants.START_PLAYER_MASS, worldSize, rng)
+			if position.X > float32(worldSize)/2 {
+				right++
+			}
+			if position.X < radius || position.X > float32(worldSize)-radius ||
+				position.Y < radius || position.Y > float32(worldSize)-radius {
+				t.Fatalf("Spawn out of bounds: %v", position)
+			}
+		}
+		if right < 180 {
+			t.Errorf("Expected spawns to favour the empty right half, got %d/200", right)
+		}
+	})
+
+	t.Run("Uniform spawns without the flag", func(t *testing.T) {
+		rng := NewSeededRNG(11)
+		var entities []*tables.Entity
+		for i := 0; i < 400; i++ {
+			entities = append(entities, createTestEntity(uint32(i+1), RangeFloat32(rng, 0, 500), RangeFloat32(rng, 0, 1000), 2))
+		}
+
+		right := 0
+		for i := 0; i < 200; i++ {
+			if FindSafeSpawn(entities, constants.START_PLAYER_MASS, worldSize, rng).X > float32(worldSize)/2 {
+				right++
+			}
+		}
+		if right < 70 || right > 130 {
+			t.Errorf("Expected roughly even spawns without density awareness, got %d/200 on the right", right)
+		}
+	})
+}
+
 func TestSpawnFoodEntity(t *testing.T) {
 	t.Run("Valid food spawn", func(t *testing.T) {
 		worldSize := uint64(1000)
 		rng := NewSeededRNG(42)
 
-		entity, food, err := SpawnFoodEntity(worldSize, rng)
+		timestamp := tables.NewTimestamp(1000000)
+		entity, food, err := SpawnFoodEntity(worldSize, rng, timestamp, nil)
 
 		if err != nil {
 			t.Fatalf("SpawnFoodEntity failed: %v", err)
@@ -269,6 +661,150 @@ func TestSpawnFoodEntity(t *testing.T) {
 		if food.EntityID != entity.EntityID {
 			t.Errorf("Food entity ID should match: got %d, expected %d", food.EntityID, entity.EntityID)
 		}
+		if entity.Kind != tables.KindFood {
+			t.Errorf("Entity kind wrong: got %s, expected food", entity.Kind)
+		}
+		if entity.CreatedAt != timestamp {
+			t.Errorf("CreatedAt wrong: got %v, expected %v", entity.CreatedAt, timestamp)
+		}
+	})
+	t.Run("Respects food spacing", func(t *testing.T) {
+		config := withConfig(t, func(config *constants.Configuration) {
+			config.MinFoodSpacing = 20
+		})
+
+		rng := NewSeededRNG(7)
+		grid := NewFoodSpacingGrid(nil, nil)
+		var spawned []*tables.Entity
+		for i := 0; i < 100; i++ {
+			entity, _, err := SpawnFoodEntity(1000, rng, tables.Timestamp{}, grid)
+			if err != nil {
+				t.Fatalf("SpawnFoodEntity failed: %v", err)
+			}
+			spawned = append(spawned, entity)
+		}
+
+		for i := range spawned {
+			for j := i + 1; j < len(spawned); j++ {
+				if distance := spawned[i].Position.Distance(spawned[j].Position); distance < config.MinFoodSpacing {
+					t.Fatalf("Food %d and %d are only %f apart", i, j, distance)
+				}
+			}
+		}
+	})
+
+	t.Run("Avoids circles", func(t *testing.T) {
+		withConfig(t, func(config *constants.Configuration) {
+			config.FoodAvoidCircles = true
+		})
+
+		blackhole := createTestEntity(1, 500, 500, 40000)
+		grid := NewFoodSpacingGrid(nil, []*tables.Entity{blackhole})
+		if grid == nil {
+			t.Fatal("FoodAvoidCircles should build a grid even without food spacing")
+		}
+
+		rng := NewSeededRNG(7)
+		reach := constants.MassToRadius(blackhole.Mass)
+		for i := 0; i < 200; i++ {
+			entity, _, err := SpawnFoodEntity(1000, rng, tables.Timestamp{}, grid)
+			if err != nil {
+				t.Fatalf("SpawnFoodEntity failed: %v", err)
+			}
+			if distance := entity.Position.Distance(blackhole.Position); distance < reach+constants.MassToRadius(entity.Mass) {
+				t.Fatalf("Food %d spawned inside the circle, %f from its center", i, distance)
+			}
+		}
+	})
+
+	t.Run("Crowded world falls back", func(t *testing.T) {
+		withConfig(t, func(config *constants.Configuration) {
+			config.MinFoodSpacing = 500
+		})
+
+		rng := NewSeededRNG(7)
+		grid := NewFoodSpacingGrid(nil, nil)
+		for i := 0; i < 10; i++ {
+			entity, _, err := SpawnFoodEntity(100, rng, tables.Timestamp{}, grid)
+			if err != nil {
+				t.Fatalf("SpawnFoodEntity failed in a crowded world: %v", err)
+			}
+			radius := constants.MassToRadius(entity.Mass)
+			if entity.Position.X < radius || entity.Position.X > 100-radius ||
+				entity.Position.Y < radius || entity.Position.Y > 100-radius {
+				t.Errorf("Fallback position out of bounds: %s", entity.Position.String())
+			}
+		}
+	})
+}
+
+func TestSpatialGrid(t *testing.T) {
+	grid := NewSpatialGrid(10)
+	grid.Add(types.NewDbVector2(5, 5))
+	grid.Add(types.NewDbVector2(-35, 80))
+
+	tests := []struct {
+		name     string
+		position types.DbVector2
+		distance float32
+		expected bool
+	}{
+		{"same cell", types.NewDbVector2(8, 5), 5, true},
+		{"neighbouring cell", types.NewDbVector2(14, 5), 10, true},
+		{"too far", types.NewDbVector2(40, 40), 10, false},
+		{"distance spans several cells", types.NewDbVector2(40, 5), 36, true},
+		{"negative coordinates", types.NewDbVector2(-30, 80), 6, true},
+		{"exactly at distance", types.NewDbVector2(15, 5), 10, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := grid.AnyWithin(tt.position, tt.distance); got != tt.expected {
+				t.Errorf("AnyWithin(%s, %f) = %v, want %v", tt.position.String(), tt.distance, got, tt.expected)
+			}
+		})
+	}
+
+	if NewFoodSpacingGrid(nil, nil) != nil {
+		t.Error("Food spacing grid should be nil while MinFoodSpacing and FoodAvoidCircles are disabled")
+	}
+
+	t.Run("OverlapsCircle", func(t *testing.T) {
+		circles := NewSpatialGrid(10)
+		if circles.OverlapsCircle(types.NewDbVector2(0, 0), 5) {
+			t.Error("A grid without circles should overlap nothing")
+		}
+		circles.AddCircle(types.NewDbVector2(100, 100), 50)
+		circles.Add(types.NewDbVector2(0, 0))
+
+		if !circles.OverlapsCircle(types.NewDbVector2(100, 152), 3) {
+			t.Error("A circle touching the big circle's edge from several cells away should overlap")
+		}
+		if circles.OverlapsCircle(types.NewDbVector2(100, 160), 3) {
+			t.Error("A circle clear of the big circle should not overlap")
+		}
+		if circles.OverlapsCircle(types.NewDbVector2(0, 0), 3) {
+			t.Error("Plain positions should not count as circles")
+		}
+	})
+}
+
+func TestEffectiveFoodTarget(t *testing.T) {
+	config := constants.DefaultConfiguration()
+	config.TargetFoodCount = 100
+
+	t.Run("Fixed target by default", func(t *testing.T) {
+		if got := EffectiveFoodTarget(50, config); got != 100 {
+			t.Errorf("Expected the base target of 100, got %d", got)
+		}
+	})
+
+	t.Run("Grows with players", func(t *testing.T) {
+		config.FoodPerPlayer = 10
+		for players, expected := range map[uint64]uint64{0: 100, 5: 100, 10: 100, 11: 110, 50: 500} {
+			if got := EffectiveFoodTarget(players, config); got != expected {
+				t.Errorf("EffectiveFoodTarget(%d) = %d, want %d", players, got, expected)
+			}
+		}
 	})
 }
 
@@ -311,10 +847,44 @@ func TestScheduleConsumeEntity(t *testing.T) {
 		if !timer.ScheduledAt.IsTime() {
 			t.Error("Timer should be scheduled at specific time")
 		}
+		if *timer.ScheduledAt.GetTime() != timestamp {
+			t.Errorf("Default consume delay should schedule immediately: got %v, expected %v", *timer.ScheduledAt.GetTime(), timestamp)
+		}
+	})
+
+	t.Run("Configured delay", func(t *testing.T) {
+		withConfig(t, func(config *constants.Configuration) {
+			config.ConsumeDelay = 200 * time.Millisecond
+		})
+
+		timestamp := tables.NewTimestamp(1_000_000)
+		timer := ScheduleConsumeEntity(1, 2, timestamp)
+
+		expected := tables.NewTimestamp(1_200_000)
+		if *timer.ScheduledAt.GetTime() != expected {
+			t.Errorf("Scheduled time should include delay: got %v, expected %v", *timer.ScheduledAt.GetTime(), expected)
+		}
 	})
 }
 
 func TestRandomFunctions(t *testing.T) {
+	t.Run("DeriveSeed", func(t *testing.T) {
+		if DeriveSeed(42, 7) != DeriveSeed(42, 7) {
+			t.Error("DeriveSeed should be deterministic")
+		}
+		seen := map[int64]bool{}
+		for counter := uint64(0); counter < 100; counter++ {
+			seed := DeriveSeed(42, counter)
+			if seen[seed] {
+				t.Fatalf("DeriveSeed repeated a seed at counter %d", counter)
+			}
+			seen[seed] = true
+		}
+		if DeriveSeed(42, 0) == DeriveSeed(43, 0) {
+			t.Error("Different world seeds should derive different seeds")
+		}
+	})
+
 	t.Run("RangeFloat32", func(t *testing.T) {
 		rng := NewSeededRNG(42)
 		min := float32(10)
@@ -446,6 +1016,58 @@ func TestPhysicsAndMovement(t *testing.T) {
 		}
 	})
 
+	t.Run("MigrateEntitiesToWorld", func(t *testing.T) {
+		center := createTestEntity(1, 500, 500, 25)
+		corner := createTestEntity(2, 995, 995, 25)
+		entities := []*tables.Entity{center, corner}
+
+		moved := MigrateEntitiesToWorld(entities, 1000, 2000)
+		if len(moved) != 2 {
+			t.Errorf("Expected both entities to move, got %d", len(moved))
+		}
+		if !center.Position.Equal(types.NewDbVector2(1000, 1000)) {
+			t.Errorf("Center should scale by the size ratio to (1000, 1000), got %v", center.Position)
+		}
+
+		// Shrinking scales positions down but keeps every circle fully in bounds
+		MigrateEntitiesToWorld(entities, 2000, 100)
+		for _, entity := range entities {
+			if err := ValidateEntityPosition(entity, 100); err != nil {
+				t.Errorf("Entity should stay in bounds after rescaling: %v", err)
+			}
+		}
+		if !center.Position.Equal(types.NewDbVector2(50, 50)) {
+			t.Errorf("Center should stay centered at (50, 50), got %v", center.Position)
+		}
+
+		if moved := MigrateEntitiesToWorld(entities, 100, 100); len(moved) != 0 {
+			t.Errorf("Same-size migration should move nothing, moved %d", len(moved))
+		}
+	})
+
+	t.Run("DistanceToWorldEdge", func(t *testing.T) {
+		worldSize := uint64(100)
+
+		tests := []struct {
+			name     string
+			position types.DbVector2
+			expected float32
+		}{
+			{"Center", types.NewDbVector2(50, 50), 50},
+			{"Near left wall", types.NewDbVector2(5, 50), 5},
+			{"Near bottom wall", types.NewDbVector2(40, 98), 2},
+			{"On wall", types.NewDbVector2(100, 30), 0},
+			{"Outside left", types.NewDbVector2(-10, 50), -10},
+			{"Outside top right", types.NewDbVector2(103, -7), -7},
+		}
+
+		for _, tt := range tests {
+			if got := DistanceToWorldEdge(tt.position, worldSize); got != tt.expected {
+				t.Errorf("%s: DistanceToWorldEdge(%v) = %f, want %f", tt.name, tt.position, got, tt.expected)
+			}
+		}
+	})
+
 	t.Run("Clamp", func(t *testing.T) {
 		if Clamp(5, 0, 10) != 5 {
 			t.Error("Value within range should not change")
@@ -477,74 +1099,357 @@ func TestPhysicsAndMovement(t *testing.T) {
 			t.Errorf("Y position should not change: got %f", newPos.Y)
 		}
 	})
-}
-
-func TestSplitCirclePhysics(t *testing.T) {
-	t.Run("CalculateGravityPull early", func(t *testing.T) {
-		entityA := createTestEntity(1, 0, 0, 100)
-		entityB := createTestEntity(2, 10, 0, 100)
-		timeSinceSplit := float32(1.0) // Early in split
-		circleCount := 2
 
-		force := CalculateGravityPull(entityA, entityB, timeSinceSplit, circleCount)
+	t.Run("PredictPositionAfter", func(t *testing.T) {
+		entity := createTestEntity(1, 500, 500, constants.START_PLAYER_MASS)
+		deltaTime := float32(constants.GetGlobalConfiguration().MovePlayersInterval.Seconds())
+		step := constants.MassToMaxMoveSpeed(entity.Mass) * deltaTime
 
-		// Should be zero since it's too early for gravity
-		if !force.Equal(types.Zero()) {
-			t.Errorf("Gravity should be zero early in split: got %v", force)
+		predicted := PredictPositionAfter(entity, types.NewDbVector2(1, 0), 10, 1000)
+		if math.Abs(float64(predicted.X-(500+10*step))) > 0.01 || predicted.Y != 500 {
+			t.Errorf("Expected (%f, 500) after 10 ticks, got %v", 500+10*step, predicted)
+		}
+		if entity.Position.X != 500 || entity.Position.Y != 500 {
+			t.Errorf("Prediction should not mutate the entity, now at %v", entity.Position)
 		}
 	})
 
-	t.Run("CalculateGravityPull late", func(t *testing.T) {
-		entityA := createTestEntity(1, 0, 0, 100)
-		entityB := createTestEntity(2, 25, 0, 100) // Far apart (beyond radius sum)
-		timeSinceSplit := float32(4.0)             // Late in split cycle
-		circleCount := 2
-
-		force := CalculateGravityPull(entityA, entityB, timeSinceSplit, circleCount)
+	t.Run("PredictPositionAfter straight into a wall", func(t *testing.T) {
+		entity := createTestEntity(1, 900, 500, constants.START_PLAYER_MASS)
+		radius := constants.MassToRadius(entity.Mass)
 
-		// Should have some gravitational force
-		if force.Magnitude() == 0 {
-			t.Error("Should have gravitational force late in split")
+		predicted := PredictPositionAfter(entity, types.NewDbVector2(1, 0), 1000, 1000)
+		if math.Abs(float64(predicted.X-(1000-radius))) > 0.001 {
+			t.Errorf("Circle should stop against the wall at X %f, got %f", 1000-radius, predicted.X)
+		}
+		if predicted.Y != 500 {
+			t.Errorf("Y position should not change: got %f", predicted.Y)
 		}
 
-		// Force should pull A towards B (towards positive X since B is to the right of A)
-		if force.X <= 0 {
-			t.Errorf("Gravity force should be positive X (towards B): got %v", force)
+		if zero := PredictPositionAfter(entity, types.NewDbVector2(1, 0), 0, 1000); !zero.Equal(entity.Position) {
+			t.Errorf("Zero ticks should predict the current position, got %v", zero)
 		}
 	})
 
-	t.Run("CalculateSeparationForce", func(t *testing.T) {
-		// Two entities very close together
-		entityA := createTestEntity(1, 0, 0, 100)
-		entityB := createTestEntity(2, 1, 0, 100) // Very close
-
-		force := CalculateSeparationForce(entityA, entityB)
+	t.Run("UpdateCirclePosition minimum speed", func(t *testing.T) {
+		entity := createTestEntity(1, 500, 500, 1000000)
+		minSpeed := constants.GetGlobalConfiguration().MinMoveSpeed
 
-		// Should have separation force since they're overlapping
-		if force.Magnitude() == 0 {
-			t.Error("Should have separation force for overlapping circles")
+		if constants.MassToMaxMoveSpeed(entity.Mass) >= minSpeed {
+			t.Fatal("Test mass should be heavy enough to fall below the speed floor")
 		}
 
-		// Force should point away from B (negative X direction for A)
-		if force.X >= 0 {
-			t.Errorf("Separation force should be negative X: got %v", force)
+		newPos := UpdateCirclePosition(entity, types.NewDbVector2(1, 0), 1.0, 10000)
+		if moved := newPos.X - 500; moved < minSpeed-0.001 {
+			t.Errorf("High-mass circle should move at least %f, moved %f", minSpeed, moved)
 		}
 	})
 
-	t.Run("Zero distance handling", func(t *testing.T) {
-		// Test entities at exactly the same position
-		entityA := createTestEntity(1, 10, 10, 100)
-		entityB := createTestEntity(2, 10, 10, 100)
+	t.Run("UpdateCirclePosition max move per tick", func(t *testing.T) {
+		deltaTime := float32(constants.MOVE_PLAYERS_INTERVAL.Seconds())
 
-		gravityForce := CalculateGravityPull(entityA, entityB, 4.0, 2)
-		separationForce := CalculateSeparationForce(entityA, entityB)
+		// Gravity and separation from many split siblings pile up far past unit length
+		forces := NewForceAccumulator(1)
+		forces.Add(1, types.NewDbVector2(1, 0))
+		for i := 0; i < 20; i++ {
+			forces.Add(1, types.NewDbVector2(5, 0))
+		}
+		direction, _ := forces.Get(1)
 
-		// Should handle zero distance gracefully (use default direction)
-		if !gravityForce.IsValid() {
-			t.Error("Gravity force should be valid even at zero distance")
+		entity := createTestEntity(1, 500, 500, 15)
+		speed := constants.MassToMaxMoveSpeed(entity.Mass)
+
+		// By default the cap derives from max speed and leaves normal movement alone
+		normal := UpdateCirclePosition(entity, types.NewDbVector2(1, 0), deltaTime, 1000)
+		if moved := normal.X - 500; math.Abs(float64(moved-speed*deltaTime)) > 0.001 {
+			t.Errorf("Default cap should not clamp normal movement: moved %f, expected %f", moved, speed*deltaTime)
 		}
-		if !separationForce.IsValid() {
-			t.Error("Separation force should be valid even at zero distance")
+		defaultCap := constants.GetGlobalConfiguration().MaxMoveDistance(deltaTime)
+		if moved := UpdateCirclePosition(entity, direction, deltaTime, 1000).X - 500; moved > defaultCap+0.001 {
+			t.Errorf("Accumulated forces moved %f, past the default cap %f", moved, defaultCap)
+		}
+
+		withConfig(t, func(config *constants.Configuration) {
+			config.MaxMovePerTick = 0.5
+		})
+
+		newPos := UpdateCirclePosition(entity, direction, deltaTime, 1000)
+		if moved := newPos.X - 500; math.Abs(float64(moved-0.5)) > 0.001 {
+			t.Errorf("Movement should be clamped to 0.5 per tick, moved %f", moved)
+		}
+
+		// Half a tick's worth of time gets half the cap
+		halfPos := UpdateCirclePosition(entity, direction, deltaTime/2, 1000)
+		if moved := halfPos.X - 500; math.Abs(float64(moved-0.25)) > 0.001 {
+			t.Errorf("Movement over half a tick should be clamped to 0.25, moved %f", moved)
+		}
+	})
+}
+
+func TestFixedTimestep(t *testing.T) {
+	tests := []struct {
+		hz       time.Duration
+		expected []int
+	}{
+		{20, []int{1, 1, 1, 1}},
+		{60, []int{3, 3, 3, 3}},
+		{30, []int{1, 2, 1, 2}},
+		{100, []int{5, 5, 5, 5}},
+	}
+
+	for _, tt := range tests {
+		clock := NewFixedTimestep(time.Second / tt.hz)
+		for i, expected := range tt.expected {
+			if steps := clock.Advance(50 * time.Millisecond); steps != expected {
+				t.Errorf("%d Hz call %d: got %d substeps, want %d", tt.hz, i+1, steps, expected)
+			}
+		}
+	}
+}
+
+func TestForceAccumulator(t *testing.T) {
+	acc := NewForceAccumulator(4)
+	acc.Add(1, types.NewDbVector2(1, 2))
+	acc.Add(1, types.NewDbVector2(3, -1))
+
+	if force, ok := acc.Get(1); !ok || force != types.NewDbVector2(4, 1) {
+		t.Errorf("Get(1) = %v, %v; want (4, 1), true", force, ok)
+	}
+	if _, ok := acc.Get(2); ok {
+		t.Error("Get on an id with no force should report false")
+	}
+
+	acc.Reset()
+	if _, ok := acc.Get(1); ok {
+		t.Error("Reset should clear accumulated forces")
+	}
+}
+
+func TestClampToTurnCone(t *testing.T) {
+	const maxAngle = math.Pi / 4
+	current := types.NewDbVector2(1, 0)
+	closeTo := func(a, b types.DbVector2) bool {
+		return a.Distance(b) < 1e-5
+	}
+
+	t.Run("Inside the cone is unchanged", func(t *testing.T) {
+		desired := types.NewDbVector2(2, 1)
+		if got := ClampToTurnCone(current, desired, maxAngle); !got.Equal(desired) {
+			t.Errorf("Got %s, want %s", got.String(), desired.String())
+		}
+	})
+
+	t.Run("Outside the cone is clamped to the edge", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			desired  types.DbVector2
+			expected types.DbVector2
+		}{
+			{"counterclockwise", types.NewDbVector2(0, 2), types.NewDbVector2(math.Sqrt2, math.Sqrt2)},
+			{"clockwise", types.NewDbVector2(0, -1), types.NewDbVector2(math.Sqrt2/2, -math.Sqrt2/2)},
+			{"opposite", types.NewDbVector2(-1, 0), types.NewDbVector2(math.Sqrt2/2, math.Sqrt2/2)},
+		}
+		for _, tt := range tests {
+			got := ClampToTurnCone(current, tt.desired, maxAngle)
+			if !closeTo(got, tt.expected) {
+				t.Errorf("%s: got %s, want %s", tt.name, got.String(), tt.expected.String())
+			}
+			if angle := current.AngleTo(got); math.Abs(float64(angle-maxAngle)) > 1e-5 {
+				t.Errorf("%s: clamped direction is %f from current, want %f", tt.name, angle, maxAngle)
+			}
+		}
+	})
+
+	t.Run("Zero vectors", func(t *testing.T) {
+		desired := types.NewDbVector2(-3, 4)
+		if got := ClampToTurnCone(types.Zero(), desired, maxAngle); !got.Equal(desired) {
+			t.Errorf("Zero current should return desired, got %s", got.String())
+		}
+		if got := ClampToTurnCone(current, types.Zero(), maxAngle); !got.IsZero() {
+			t.Errorf("Zero desired should stay zero, got %s", got.String())
+		}
+	})
+}
+
+func TestFoodMagnetPull(t *testing.T) {
+	withConfig(t, func(config *constants.Configuration) {
+		config.FoodMagnetMinMass = 400
+		config.FoodMagnetRadius = 50
+		config.FoodMagnetStrength = 20
+	})
+
+	magnet := createTestEntity(1, 500, 500, 400)
+
+	t.Run("Food in range drifts toward the circle", func(t *testing.T) {
+		food := createTestEntity(2, 525, 500, 2)
+		pull := FoodMagnetPull(magnet, food, 1.0)
+
+		// Halfway to the edge of the range, the pull is half strength
+		if math.Abs(float64(pull.X+10)) > 0.001 || pull.Y != 0 {
+			t.Errorf("Expected pull (-10, 0), got %v", pull)
+		}
+	})
+
+	t.Run("Food out of range unaffected", func(t *testing.T) {
+		food := createTestEntity(2, 560, 500, 2)
+		if pull := FoodMagnetPull(magnet, food, 1.0); !pull.IsZero() {
+			t.Errorf("Food beyond the magnet radius should not move, got %v", pull)
+		}
+	})
+
+	t.Run("Small circles do not attract", func(t *testing.T) {
+		small := createTestEntity(3, 500, 500, 399)
+		food := createTestEntity(2, 510, 500, 2)
+		if pull := FoodMagnetPull(small, food, 1.0); !pull.IsZero() {
+			t.Errorf("Circle below the mass threshold should not attract, got %v", pull)
+		}
+	})
+
+	t.Run("Pull never overshoots the center", func(t *testing.T) {
+		food := createTestEntity(2, 501, 500, 2)
+		pull := FoodMagnetPull(magnet, food, 100.0)
+		if moved := food.Position.Add(pull); moved.X < magnet.Position.X-0.001 {
+			t.Errorf("Food should stop at the circle's center, ended at %v", moved)
+		}
+	})
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		withConfig(t, func(*constants.Configuration) {})
+
+		food := createTestEntity(2, 525, 500, 2)
+		if pull := FoodMagnetPull(createTestEntity(1, 500, 500, 100000), food, 1.0); !pull.IsZero() {
+			t.Errorf("Food magnet should be disabled by default, got %v", pull)
+		}
+	})
+}
+
+func TestSplitCirclePhysics(t *testing.T) {
+	t.Run("CalculateGravityPull early", func(t *testing.T) {
+		entityA := createTestEntity(1, 0, 0, 100)
+		entityB := createTestEntity(2, 10, 0, 100)
+		timeSinceSplit := float32(1.0) // Early in split
+		circleCount := 2
+
+		force := CalculateGravityPull(entityA, entityB, timeSinceSplit, circleCount)
+
+		// Should be zero since it's too early for gravity
+		if !force.Equal(types.Zero()) {
+			t.Errorf("Gravity should be zero early in split: got %v", force)
+		}
+	})
+
+	t.Run("CalculateGravityPull late", func(t *testing.T) {
+		entityA := createTestEntity(1, 0, 0, 100)
+		entityB := createTestEntity(2, 25, 0, 100) // Far apart (beyond radius sum)
+		timeSinceSplit := float32(4.0)             // Late in split cycle
+		circleCount := 2
+
+		force := CalculateGravityPull(entityA, entityB, timeSinceSplit, circleCount)
+
+		// Should have some gravitational force
+		if force.Magnitude() == 0 {
+			t.Error("Should have gravitational force late in split")
+		}
+
+		// Force should pull A towards B (towards positive X since B is to the right of A)
+		if force.X <= 0 {
+			t.Errorf("Gravity force should be positive X (towards B): got %v", force)
+		}
+	})
+
+	t.Run("CalculateSeparationForce", func(t *testing.T) {
+		// Two entities very close together
+		entityA := createTestEntity(1, 0, 0, 100)
+		entityB := createTestEntity(2, 1, 0, 100) // Very close
+
+		force := CalculateSeparationForce(entityA, entityB)
+
+		// Should have separation force since they're overlapping
+		if force.Magnitude() == 0 {
+			t.Error("Should have separation force for overlapping circles")
+		}
+
+		// Force should point away from B (negative X direction for A)
+		if force.X >= 0 {
+			t.Errorf("Separation force should be negative X: got %v", force)
+		}
+	})
+
+	t.Run("SeparationForce ramps with overlap depth", func(t *testing.T) {
+		radiusSum := constants.MassToRadius(100) * 2
+		allowed := radiusSum * constants.GetGlobalConfiguration().AllowedSplitCircleOverlapPct
+
+		// Shallow overlap: just inside the allowed distance
+		shallowA := createTestEntity(1, 0, 0, 100)
+		shallowB := createTestEntity(2, allowed*0.95, 0, 100)
+
+		// Deep overlap: circles nearly on top of each other
+		deepA := createTestEntity(1, 0, 0, 100)
+		deepB := createTestEntity(2, allowed*0.1, 0, 100)
+
+		shallowForce := CalculateSeparationForce(shallowA, shallowB)
+		deepForce := CalculateSeparationForce(deepA, deepB)
+
+		if shallowForce.Magnitude() == 0 {
+			t.Fatal("Shallow overlap should still produce a separation force")
+		}
+		if deepForce.Magnitude() <= shallowForce.Magnitude() {
+			t.Errorf("Deeper overlap should push harder: deep=%f, shallow=%f",
+				deepForce.Magnitude(), shallowForce.Magnitude())
+		}
+
+		// The deep push should grow faster than the overlap distance alone would explain
+		shallowOverlap := radiusSum - allowed*0.95
+		deepOverlap := radiusSum - allowed*0.1
+		if deepForce.Magnitude()/deepOverlap <= shallowForce.Magnitude()/shallowOverlap {
+			t.Error("Separation speed per unit of overlap should increase with depth")
+		}
+	})
+
+	t.Run("SeparationSpeedForOverlap bounds", func(t *testing.T) {
+		config := constants.GetGlobalConfiguration()
+
+		// At the allowed distance the speed matches the flat default
+		atThreshold := SeparationSpeedForOverlap(10, 10)
+		if math.Abs(float64(atThreshold-config.SelfCollisionSpeed)) > 1e-6 {
+			t.Errorf("Speed at threshold = %f, want %f", atThreshold, config.SelfCollisionSpeed)
+		}
+
+		// Shallow overlaps stay close to the flat default
+		shallow := SeparationSpeedForOverlap(9.9, 10)
+		if math.Abs(float64(shallow-config.SelfCollisionSpeed)) > 0.01 {
+			t.Errorf("Shallow overlap speed = %f, should be close to %f", shallow, config.SelfCollisionSpeed)
+		}
+
+		// Fully concentric circles reach the configured maximum and never exceed it
+		full := SeparationSpeedForOverlap(0, 10)
+		if math.Abs(float64(full-config.MaxSelfCollisionSpeed)) > 1e-6 {
+			t.Errorf("Full overlap speed = %f, want %f", full, config.MaxSelfCollisionSpeed)
+		}
+		if beyond := SeparationSpeedForOverlap(-5, 10); beyond > config.MaxSelfCollisionSpeed {
+			t.Errorf("Speed should be bounded by max: got %f", beyond)
+		}
+
+		// Degenerate allowed distance falls back to the flat default
+		if degenerate := SeparationSpeedForOverlap(0, 0); degenerate != config.SelfCollisionSpeed {
+			t.Errorf("Degenerate allowed distance speed = %f, want %f", degenerate, config.SelfCollisionSpeed)
+		}
+	})
+
+	t.Run("Zero distance handling", func(t *testing.T) {
+		// Test entities at exactly the same position
+		entityA := createTestEntity(1, 10, 10, 100)
+		entityB := createTestEntity(2, 10, 10, 100)
+
+		gravityForce := CalculateGravityPull(entityA, entityB, 4.0, 2)
+		separationForce := CalculateSeparationForce(entityA, entityB)
+
+		// Should handle zero distance gracefully (use default direction)
+		if !gravityForce.IsValid() {
+			t.Error("Gravity force should be valid even at zero distance")
+		}
+		if !separationForce.IsValid() {
+			t.Error("Separation force should be valid even at zero distance")
 		}
 	})
 }
@@ -591,6 +1496,21 @@ func TestValidation(t *testing.T) {
 		}
 	})
 
+	t.Run("ValidateCircleData non-normalized direction", func(t *testing.T) {
+		entity := createTestEntity(1, 50, 50, 100)
+		circle := tables.NewCircle(entity.EntityID, 42, types.NewDbVector2(3, 4), 0.5, tables.NewTimestampFromTime(time.Now()))
+
+		if err := ValidateCircleData(circle, entity); err == nil {
+			t.Error("Circle with a non-unit direction should fail validation")
+		}
+
+		// A stopped circle stores a zero direction
+		circle.Direction = types.Zero()
+		if err := ValidateCircleData(circle, entity); err != nil {
+			t.Errorf("Circle with a zero direction should pass validation: %v", err)
+		}
+	})
+
 	t.Run("ValidateCircleData entity ID mismatch", func(t *testing.T) {
 		entity := createTestEntity(1, 50, 50, 100)
 		direction := types.NewDbVector2(1, 0)
@@ -614,6 +1534,269 @@ func TestValidation(t *testing.T) {
 	})
 }
 
+// fakeGameState is an in-memory GameStateDatabase that can hold rows a real store would reject
+type fakeGameState struct {
+	entities []*tables.Entity
+	circles  []*tables.Circle
+	food     []*tables.Food
+	players  []*tables.Player
+}
+
+func (f *fakeGameState) GetAllEntities() ([]*tables.Entity, error) { return f.entities, nil }
+func (f *fakeGameState) GetAllCircles() ([]*tables.Circle, error)  { return f.circles, nil }
+func (f *fakeGameState) GetAllFood() ([]*tables.Food, error)       { return f.food, nil }
+func (f *fakeGameState) GetAllPlayers() ([]*tables.Player, error)  { return f.players, nil }
+
+func TestValidateGameState(t *testing.T) {
+	const worldSize = 1000
+
+	validState := func() *fakeGameState {
+		return &fakeGameState{
+			entities: []*tables.Entity{
+				createTestEntity(1, 100, 100, 25),
+				createTestEntity(2, 200, 200, 1),
+			},
+			circles: []*tables.Circle{{EntityID: 1, PlayerID: 7}},
+			food:    []*tables.Food{{EntityID: 2}},
+			players: []*tables.Player{tables.NewPlayer(tables.NewIdentity([16]byte{7}), 7, "p")},
+		}
+	}
+
+	t.Run("Valid state", func(t *testing.T) {
+		if errs := ValidateGameState(validState(), worldSize); len(errs) != 0 {
+			t.Errorf("Expected no violations, got %v", errs)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		corrupt func(*fakeGameState)
+		want    string
+	}{
+		{"Circle without entity", func(s *fakeGameState) {
+			s.circles = append(s.circles, &tables.Circle{EntityID: 9, PlayerID: 7})
+		}, "circle 9 has no backing entity"},
+		{"Food without entity", func(s *fakeGameState) {
+			s.food = append(s.food, &tables.Food{EntityID: 9})
+		}, "food 9 has no backing entity"},
+		{"Out of bounds", func(s *fakeGameState) {
+			s.entities[0].Position.X = worldSize + 50
+		}, "entity 1 X position out of bounds"},
+		{"Zero mass", func(s *fakeGameState) {
+			s.entities[1].Mass = 0
+		}, "entity 2 has zero mass"},
+		{"Missing player", func(s *fakeGameState) {
+			s.circles[0].PlayerID = 8
+		}, "circle 1 belongs to missing player 8"},
+		{"Duplicate entity", func(s *fakeGameState) {
+			s.entities = append(s.entities, createTestEntity(1, 100, 100, 25))
+		}, "duplicate entity id 1"},
+		{"Duplicate circle", func(s *fakeGameState) {
+			s.circles = append(s.circles, &tables.Circle{EntityID: 1, PlayerID: 7})
+		}, "duplicate circle entity id 1"},
+		{"Duplicate food", func(s *fakeGameState) {
+			s.food = append(s.food, &tables.Food{EntityID: 2})
+		}, "duplicate food entity id 2"},
+		{"Duplicate player", func(s *fakeGameState) {
+			s.players = append(s.players, tables.NewPlayer(tables.NewIdentity([16]byte{8}), 7, "q"))
+		}, "duplicate player id 7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := validState()
+			tt.corrupt(state)
+
+			errs := ValidateGameState(state, worldSize)
+			if len(errs) != 1 {
+				t.Fatalf("Expected exactly one violation, got %v", errs)
+			}
+			if !strings.Contains(errs[0].Error(), tt.want) {
+				t.Errorf("Expected violation containing %q, got %q", tt.want, errs[0])
+			}
+		})
+	}
+
+	t.Run("Reports all violations", func(t *testing.T) {
+		state := validState()
+		state.entities[1].Mass = 0
+		state.circles[0].PlayerID = 8
+		state.food = append(state.food, &tables.Food{EntityID: 9})
+
+		if errs := ValidateGameState(state, worldSize); len(errs) != 3 {
+			t.Errorf("Expected 3 violations, got %v", errs)
+		}
+	})
+}
+
+func TestResolveClusterConsumption(t *testing.T) {
+	// ownedBy maps entity ids to their owning player; other ids are food
+	ownedBy := func(owners map[uint32]uint32) func(uint32) (uint32, bool) {
+		return func(id uint32) (uint32, bool) {
+			owner, ok := owners[id]
+			return owner, ok
+		}
+	}
+
+	t.Run("Star", func(t *testing.T) {
+		entities := []*tables.Entity{
+			createTestEntity(3, 505, 500, 10),
+			createTestEntity(1, 500, 500, 400),
+			createTestEntity(5, 500, 495, 10),
+			createTestEntity(2, 495, 500, 10),
+			createTestEntity(4, 500, 505, 10),
+		}
+		owners := ownedBy(map[uint32]uint32{1: 1, 2: 2, 3: 3})
+
+		got := ResolveClusterConsumption(entities, owners, OverlapModeThreshold)
+		want := []ConsumePair{{1, 2}, {1, 3}, {1, 4}, {1, 5}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Star cluster pairs = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Chain", func(t *testing.T) {
+		// A can eat B and B can eat C, but C is out of A's reach
+		a := createTestEntity(1, 500, 500, 400)
+		b := createTestEntity(2, 515, 500, 300)
+		c := createTestEntity(3, 540, 500, 200)
+		if !CanConsume(b, c, OverlapModeThreshold) || CanConsume(a, c, OverlapModeThreshold) {
+			t.Fatal("Test layout should let B but not A reach C")
+		}
+		owners := ownedBy(map[uint32]uint32{1: 1, 2: 2, 3: 3})
+
+		want := []ConsumePair{{1, 2}}
+		for _, order := range [][]*tables.Entity{{a, b, c}, {c, b, a}, {b, c, a}} {
+			if got := ResolveClusterConsumption(order, owners, OverlapModeThreshold); !reflect.DeepEqual(got, want) {
+				t.Errorf("Chain pairs = %v, want %v regardless of input order", got, want)
+			}
+		}
+	})
+
+	t.Run("Same owner and food", func(t *testing.T) {
+		entities := []*tables.Entity{
+			createTestEntity(1, 500, 500, 400),
+			createTestEntity(2, 502, 500, 10), // Same player as 1
+			createTestEntity(3, 498, 500, 10), // Food
+		}
+		owners := ownedBy(map[uint32]uint32{1: 1, 2: 1})
+
+		got := ResolveClusterConsumption(entities, owners, OverlapModeThreshold)
+		want := []ConsumePair{{1, 3}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Pairs = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestSpatialGridNearestEntity(t *testing.T) {
+	rng := NewSeededRNG(11)
+	grid := NewSpatialGrid(50)
+	var entities []*tables.Entity
+	for id := uint32(1); id <= 300; id++ {
+		entity := createTestEntity(id, RangeFloat32(rng, 0, 1000), RangeFloat32(rng, 0, 1000), 10)
+		entities = append(entities, entity)
+		grid.AddEntity(entity)
+	}
+	evenIDs := func(entity *tables.Entity) bool { return entity.EntityID%2 == 0 }
+
+	// The ring search must agree with a brute-force scan from points inside and outside the world
+	for i := 0; i < 100; i++ {
+		point := types.NewDbVector2(RangeFloat32(rng, -200, 1200), RangeFloat32(rng, -200, 1200))
+
+		var want *tables.Entity
+		for _, entity := range entities {
+			if !evenIDs(entity) {
+				continue
+			}
+			if want == nil || point.DistanceSquared(entity.Position) < point.DistanceSquared(want.Position) {
+				want = entity
+			}
+		}
+
+		got, distance, found := grid.NearestEntity(point, evenIDs)
+		if !found || got.EntityID != want.EntityID {
+			t.Fatalf("NearestEntity(%v) = %v, want entity %d", point, got, want.EntityID)
+		}
+		if math.Abs(float64(distance-point.Distance(want.Position))) > 0.01 {
+			t.Errorf("NearestEntity(%v) distance = %f, want %f", point, distance, point.Distance(want.Position))
+		}
+	}
+
+	if _, _, found := NewSpatialGrid(50).NearestEntity(types.Zero(), nil); found {
+		t.Error("An empty grid should find nothing")
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	before := tables.NewGameSnapshot(tables.NewTimestamp(1_000_000))
+	before.Entities = []*tables.Entity{
+		createTestEntity(1, 100, 100, 20), // Moves
+		createTestEntity(2, 200, 200, 20), // Destroyed
+		createTestEntity(3, 300, 300, 20), // Unchanged
+	}
+
+	after := tables.NewGameSnapshot(tables.NewTimestamp(1_050_000))
+	after.Entities = []*tables.Entity{
+		createTestEntity(4, 400, 400, 2), // Created
+		createTestEntity(3, 300, 300, 20),
+		createTestEntity(1, 110, 100, 20),
+	}
+
+	diff := DiffSnapshots(before, after)
+	if len(diff.Created) != 1 || diff.Created[0].EntityID != 4 {
+		t.Errorf("Created = %v, want entity 4", diff.Created)
+	}
+	if len(diff.Updated) != 1 || diff.Updated[0].EntityID != 1 || diff.Updated[0].Position.X != 110 {
+		t.Errorf("Updated = %v, want entity 1 at its new position", diff.Updated)
+	}
+	if !reflect.DeepEqual(diff.Deleted, []uint32{2}) {
+		t.Errorf("Deleted = %v, want [2]", diff.Deleted)
+	}
+
+	t.Run("Identical snapshots", func(t *testing.T) {
+		if diff := DiffSnapshots(after, after); !diff.IsEmpty() {
+			t.Errorf("Diff of a snapshot with itself should be empty, got %+v", diff)
+		}
+	})
+
+	t.Run("Nil snapshots", func(t *testing.T) {
+		if diff := DiffSnapshots(nil, after); len(diff.Created) != 3 {
+			t.Errorf("Everything should be created from nothing, got %+v", diff)
+		}
+		if diff := DiffSnapshots(before, nil); !reflect.DeepEqual(diff.Deleted, []uint32{1, 2, 3}) {
+			t.Errorf("Everything should be deleted into nothing, got %v", diff.Deleted)
+		}
+	})
+}
+
+func TestExceedsClockDrift(t *testing.T) {
+	now := tables.NewTimestamp(10_000_000)
+	tolerance := 2 * time.Second
+
+	tests := []struct {
+		name      string
+		timestamp tables.Timestamp
+		tolerance time.Duration
+		expected  bool
+	}{
+		{"Same time", now, tolerance, false},
+		{"Within tolerance ahead", now.Add(tables.NewTimeDurationFromDuration(time.Second)), tolerance, false},
+		{"Within tolerance behind", tables.NewTimestamp(9_000_000), tolerance, false},
+		{"Far future", now.Add(tables.NewTimeDurationFromDuration(time.Hour)), tolerance, true},
+		{"Far past", tables.NewTimestamp(1_000_000), tolerance, true},
+		{"Disabled", now.Add(tables.NewTimeDurationFromDuration(time.Hour)), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExceedsClockDrift(tt.timestamp, now, tt.tolerance); got != tt.expected {
+				t.Errorf("ExceedsClockDrift() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestPerformanceMonitoring(t *testing.T) {
 	t.Run("PerformanceTimer", func(t *testing.T) {
 		timer := NewPerformanceTimer("test")
@@ -654,6 +1837,74 @@ func TestGameLogicHelpers(t *testing.T) {
 		}
 	})
 
+	t.Run("MaxCirclesForMass", func(t *testing.T) {
+		config := constants.DefaultConfiguration()
+		config.MaxCirclesPerPlayer = 8
+		config.CircleCapMassPerCircle = 100
+
+		// Flat ignores mass
+		for _, mass := range []uint32{0, 100, 100000} {
+			if got := MaxCirclesForMass(mass, config); got != 8 {
+				t.Errorf("Flat cap at mass %d: expected 8, got %d", mass, got)
+			}
+		}
+
+		config.CircleCapModel = constants.CircleCapMassScaled
+		for mass, want := range map[uint32]uint32{0: 1, 150: 1, 200: 2, 550: 5, 800: 8, 100000: 8} {
+			if got := MaxCirclesForMass(mass, config); got != want {
+				t.Errorf("Mass-scaled cap at mass %d: expected %d, got %d", mass, want, got)
+			}
+		}
+
+		withConfig(t, func(global *constants.Configuration) {
+			*global = *config
+		})
+		entity := createTestEntity(1, 50, 50, config.MinMassToSplit*2)
+		if CanPlayerSplitWithMass(entity, 3, 300) {
+			t.Error("Player at the mass-scaled cap should not be able to split")
+		}
+		if !CanPlayerSplitWithMass(entity, 3, 400) {
+			t.Error("More total mass should raise the mass-scaled cap")
+		}
+	})
+
+	t.Run("RemainingSplitBudget", func(t *testing.T) {
+		config := constants.DefaultConfiguration()
+		config.MaxCirclesPerPlayer = 4
+
+		for count, expected := range map[uint32]uint32{0: 4, 3: 1, 4: 0, 10: 0} {
+			if got := RemainingSplitBudget(count, 100, config); got != expected {
+				t.Errorf("RemainingSplitBudget(%d) = %d, want %d", count, got, expected)
+			}
+		}
+
+		// Mass-scaled caps count the player's total mass
+		config.CircleCapModel = constants.CircleCapMassScaled
+		config.CircleCapMassPerCircle = 100
+		for mass, expected := range map[uint32]uint32{100: 0, 300: 1, 1000: 2} {
+			if got := RemainingSplitBudget(2, mass, config); got != expected {
+				t.Errorf("RemainingSplitBudget(2, %d) = %d, want %d", mass, got, expected)
+			}
+		}
+	})
+
+	t.Run("TopByCircleCount", func(t *testing.T) {
+		counts := map[uint32]uint32{1: 2, 2: 5, 3: 1, 4: 5, 5: 3}
+
+		top := TopByCircleCount(counts, 3)
+		expected := []uint32{2, 4, 5}
+		if !reflect.DeepEqual(top, expected) {
+			t.Errorf("TopByCircleCount(3) = %v, want %v", top, expected)
+		}
+
+		if all := TopByCircleCount(counts, 10); len(all) != len(counts) || all[len(all)-1] != 3 {
+			t.Errorf("TopByCircleCount(10) should rank every player, got %v", all)
+		}
+		if none := TopByCircleCount(counts, 0); len(none) != 0 {
+			t.Errorf("TopByCircleCount(0) should be empty, got %v", none)
+		}
+	})
+
 	t.Run("CalculateHalfMass", func(t *testing.T) {
 		if CalculateHalfMass(100) != 50 {
 			t.Error("Half of 100 should be 50")
@@ -675,6 +1926,84 @@ func TestGameLogicHelpers(t *testing.T) {
 		}
 	})
 
+	t.Run("CanConsumeEntityNearEqualMasses", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			strict   bool
+			consumer uint32
+			consumed uint32
+			expected bool
+		}{
+			{"Ratio mode just under ratio", false, 100, 84, true},
+			{"Ratio mode at ratio", false, 100, 85, false},
+			{"Ratio mode one lighter", false, 100, 99, false},
+			{"Strict mode one lighter", true, 100, 99, true},
+			{"Strict mode equal mass", true, 100, 100, false},
+			{"Strict mode heavier", true, 100, 101, false},
+		}
+
+		for _, tt := range tests {
+			withConfig(t, func(config *constants.Configuration) {
+				config.StrictConsumption = tt.strict
+			})
+
+			if got := CanConsumeEntity(tt.consumer, tt.consumed); got != tt.expected {
+				t.Errorf("%s: CanConsumeEntity(%d, %d) = %v, want %v", tt.name, tt.consumer, tt.consumed, got, tt.expected)
+			}
+		}
+	})
+
+	t.Run("AddMassSaturating", func(t *testing.T) {
+		if got := AddMassSaturating(100, 50); got != 150 {
+			t.Errorf("Expected 150, got %d", got)
+		}
+		if got := AddMassSaturating(math.MaxUint32-10, 10); got != math.MaxUint32 {
+			t.Errorf("Sum landing exactly on the boundary should be kept, got %d", got)
+		}
+		if got := AddMassSaturating(math.MaxUint32-10, 11); got != math.MaxUint32 {
+			t.Errorf("Overflowing sum should saturate, got %d", got)
+		}
+		if got := AddMassSaturating(math.MaxUint32, math.MaxUint32); got != math.MaxUint32 {
+			t.Errorf("Overflowing sum should saturate, got %d", got)
+		}
+	})
+
+	t.Run("AddMassSaturating configured cap", func(t *testing.T) {
+		withConfig(t, func(config *constants.Configuration) {
+			config.MaxCircleMass = 1000
+		})
+
+		if got := AddMassSaturating(900, 100); got != 1000 {
+			t.Errorf("Sum at the cap should be kept, got %d", got)
+		}
+		if got := AddMassSaturating(900, 101); got != 1000 {
+			t.Errorf("Sum above the cap should clamp to 1000, got %d", got)
+		}
+		if got := AddMassSaturating(math.MaxUint32, 1); got != math.MaxUint32 {
+			t.Errorf("Circle already above the cap should not lose mass, got %d", got)
+		}
+	})
+
+	t.Run("MassOverflow", func(t *testing.T) {
+		if got := MassOverflow(900, 200); got != 0 {
+			t.Errorf("Uncapped consume should not overflow, got %d", got)
+		}
+
+		withConfig(t, func(config *constants.Configuration) {
+			config.MaxCircleMass = 1000
+		})
+
+		for _, tt := range []struct{ a, b, expected uint32 }{
+			{900, 100, 0},
+			{900, 250, 150},
+			{1200, 50, 50},
+		} {
+			if got := MassOverflow(tt.a, tt.b); got != tt.expected {
+				t.Errorf("MassOverflow(%d, %d) = %d, want %d", tt.a, tt.b, got, tt.expected)
+			}
+		}
+	})
+
 	t.Run("ShouldCircleDecay", func(t *testing.T) {
 		// Large circle should decay
 		entity1 := createTestEntity(1, 50, 50, constants.START_PLAYER_MASS+10)
@@ -689,6 +2018,178 @@ func TestGameLogicHelpers(t *testing.T) {
 		}
 	})
 
+	t.Run("ShouldCircleDecayAt", func(t *testing.T) {
+		spawnedAt := tables.NewTimestamp(10_000_000)
+		entity, circle, _ := SpawnCircleAt(42, constants.START_PLAYER_MASS+100, types.Zero(), spawnedAt)
+		if circle.SpawnedAt != spawnedAt {
+			t.Fatalf("SpawnedAt not set: got %v, expected %v", circle.SpawnedAt, spawnedAt)
+		}
+
+		// Default grace of zero decays immediately
+		if !ShouldCircleDecayAt(entity, circle, spawnedAt) {
+			t.Error("Large circle should decay immediately with no grace period")
+		}
+
+		withConfig(t, func(config *constants.Configuration) {
+			config.DecayGracePeriodSec = 3.0
+		})
+
+		if ShouldCircleDecayAt(entity, circle, spawnedAt.Add(tables.NewTimeDurationFromDuration(2*time.Second))) {
+			t.Error("Fresh circle should not decay within the grace period")
+		}
+		if !ShouldCircleDecayAt(entity, circle, spawnedAt.Add(tables.NewTimeDurationFromDuration(3*time.Second))) {
+			t.Error("Circle should decay once past the grace period")
+		}
+
+		small := createTestEntity(2, 0, 0, constants.START_PLAYER_MASS)
+		if ShouldCircleDecayAt(small, circle, spawnedAt.Add(tables.NewTimeDurationFromDuration(time.Minute))) {
+			t.Error("Small circle should never decay regardless of age")
+		}
+	})
+
+	t.Run("IsDecayExempt", func(t *testing.T) {
+		config := constants.DefaultConfiguration()
+		if IsDecayExempt(10, 1000, config) {
+			t.Error("Nothing should be exempt when the fraction is 0")
+		}
+
+		config.DecayExemptLeaderFraction = 0.25
+		if !IsDecayExempt(100, 1000, config) {
+			t.Error("Circle under a quarter of the leader's mass should be exempt")
+		}
+		if IsDecayExempt(250, 1000, config) || IsDecayExempt(1000, 1000, config) {
+			t.Error("Circles at or above the threshold should decay")
+		}
+	})
+
+	t.Run("Spawn protection", func(t *testing.T) {
+		spawnedAt := tables.NewTimestamp(10_000_000)
+		_, circle, _ := SpawnPlayerInitialCircle(42, 1000, NewSeededRNG(1), spawnedAt)
+		if IsCircleProtected(circle, spawnedAt) {
+			t.Error("Spawn protection should be off by default")
+		}
+
+		withConfig(t, func(config *constants.Configuration) {
+			config.SpawnProtectionSec = 3
+		})
+
+		_, circle, _ = SpawnPlayerSafeCircle(42, nil, 1000, NewSeededRNG(1), spawnedAt)
+		if expected := spawnedAt.Add(tables.NewTimeDurationFromDuration(3 * time.Second)); circle.ProtectedUntil != expected {
+			t.Errorf("ProtectedUntil = %v, expected %v", circle.ProtectedUntil, expected)
+		}
+		if !IsCircleProtected(circle, spawnedAt.Add(tables.NewTimeDurationFromDuration(2*time.Second))) {
+			t.Error("Circle should be protected during the protection window")
+		}
+		if IsCircleProtected(circle, circle.ProtectedUntil) {
+			t.Error("Protection should end at ProtectedUntil")
+		}
+
+		// Split circles are not fresh spawns and get no protection
+		_, split, _ := SpawnCircleAt(42, 100, types.Zero(), spawnedAt)
+		if IsCircleProtected(split, spawnedAt) {
+			t.Error("SpawnCircleAt should not grant spawn protection")
+		}
+	})
+
+	t.Run("VisualRadius", func(t *testing.T) {
+		entity := createTestEntity(1, 0, 0, 100)
+		base := constants.MassToRadius(entity.Mass)
+		grewAt := tables.NewTimestamp(10_000_000)
+		after := func(d time.Duration) tables.Timestamp {
+			return grewAt.Add(tables.NewTimeDurationFromDuration(d))
+		}
+
+		if r := VisualRadius(entity, grewAt, grewAt); math.Abs(float64(r-base*(1+constants.GROWTH_PULSE_SCALE))) > 1e-4 {
+			t.Errorf("Radius right after growing = %f, want the full pulse %f", r, base*(1+constants.GROWTH_PULSE_SCALE))
+		}
+		if r := VisualRadius(entity, grewAt, after(constants.GROWTH_PULSE_DURATION/2)); r <= base || r >= base*(1+constants.GROWTH_PULSE_SCALE) {
+			t.Errorf("Radius halfway through the pulse = %f, want between %f and the full pulse", r, base)
+		}
+		if r := VisualRadius(entity, grewAt, after(time.Minute)); r != base {
+			t.Errorf("Radius long after growing = %f, want the base radius %f", r, base)
+		}
+		if r := VisualRadius(entity, tables.Timestamp{}, grewAt); r != base {
+			t.Errorf("Radius of a circle that never grew = %f, want %f", r, base)
+		}
+	})
+
+	t.Run("CanTeamMerge", func(t *testing.T) {
+		consumer := createTestEntity(1, 0, 0, 200)
+		if !CanTeamMerge(consumer, createTestEntity(2, 10, 0, 190)) {
+			t.Error("A teammate whose center is covered should merge without a mass advantage")
+		}
+		if CanTeamMerge(consumer, createTestEntity(3, 0, 0, 210)) {
+			t.Error("A lighter circle should not absorb a heavier teammate")
+		}
+		if CanTeamMerge(consumer, createTestEntity(4, 20, 0, 100)) {
+			t.Error("A teammate whose center is outside the consumer should not merge")
+		}
+	})
+
+	t.Run("Circle colors", func(t *testing.T) {
+		if PlayerColor(3) != PlayerColor(3) || PlayerColor(3) == PlayerColor(4) {
+			t.Error("PlayerColor should be deterministic and differ between neighbouring players")
+		}
+		if PlayerColor(5)&0xff != 0xff {
+			t.Errorf("Default colors should be opaque, got %08x", PlayerColor(5))
+		}
+		if color := CircleColor(5, 0); color != PlayerColor(5) {
+			t.Errorf("CircleColor without a request = %08x, want %08x", color, PlayerColor(5))
+		}
+		if color := CircleColor(5, 0xabcdef10); color != 0xabcdefff {
+			t.Errorf("CircleColor should force full alpha, got %08x", color)
+		}
+
+		_, circle, _ := SpawnCircleAt(6, 100, types.Zero(), tables.Timestamp{})
+		if circle.Color != PlayerColor(6) {
+			t.Errorf("Spawned circle color = %08x, want %08x", circle.Color, PlayerColor(6))
+		}
+	})
+
+	t.Run("Spawn wall padding", func(t *testing.T) {
+		config := withConfig(t, func(config *constants.Configuration) {
+			config.SpawnWallPadding = 40
+		})
+
+		const worldSize = 200
+		assertPadded := func(kind string, entity *tables.Entity) {
+			t.Helper()
+			margin := constants.MassToRadius(entity.Mass) + config.SpawnWallPadding
+			p := entity.Position
+			if p.X < margin || p.Y < margin || p.X > worldSize-margin || p.Y > worldSize-margin {
+				t.Errorf("%s at %s is closer than %.2f to a wall", kind, p.String(), margin)
+			}
+		}
+
+		rng := NewSeededRNG(7)
+		for i := 0; i < 200; i++ {
+			food, _, _ := SpawnFoodEntity(worldSize, rng, tables.Timestamp{}, nil)
+			assertPadded("Food", food)
+
+			circle, _, _ := SpawnPlayerInitialCircle(1, worldSize, rng, tables.Timestamp{})
+			assertPadded("Initial circle", circle)
+
+			safe, _, _ := SpawnPlayerSafeCircle(1, nil, worldSize, rng, tables.Timestamp{})
+			assertPadded("Safe circle", safe)
+		}
+	})
+
+	t.Run("FoodMassGain", func(t *testing.T) {
+		if gain := FoodMassGain(3); gain != 3 {
+			t.Errorf("Default multiplier should keep food mass, got %d", gain)
+		}
+
+		withConfig(t, func(config *constants.Configuration) {
+			config.FoodMassMultiplier = 2.5
+		})
+		if gain := FoodMassGain(3); gain != 8 {
+			t.Errorf("FoodMassGain(3) at 2.5x = %d, expected 8", gain)
+		}
+		if gain := FoodMassGain(math.MaxUint32); gain != math.MaxUint32 {
+			t.Errorf("FoodMassGain should saturate, got %d", gain)
+		}
+	})
+
 	t.Run("CalculateDecayedMass", func(t *testing.T) {
 		original := uint32(100)
 		decayed := CalculateDecayedMass(original)
@@ -699,6 +2200,75 @@ func TestGameLogicHelpers(t *testing.T) {
 		}
 	})
 
+	t.Run("RoundMass", func(t *testing.T) {
+		tests := []struct {
+			input    float32
+			expected uint32
+		}{
+			{99.5, 100},  // Ties go to the even neighbour, up here...
+			{100.5, 100}, // ...and down here, so the two cancel out
+			{0.5, 0},
+			{1.5, 2},
+			{99.4, 99},
+			{100.6, 101},
+			{-3, 0},
+			{float32(math.NaN()), 0},
+			{1e12, math.MaxUint32},
+		}
+		for _, tt := range tests {
+			if got := RoundMass(tt.input); got != tt.expected {
+				t.Errorf("RoundMass(%v) = %d, expected %d", tt.input, got, tt.expected)
+			}
+		}
+	})
+
+	t.Run("CampingDecay", func(t *testing.T) {
+		start := tables.NewTimestamp(1_000_000)
+		if IsCamping(start, start.Add(tables.NewTimeDurationFromDuration(time.Hour))) {
+			t.Error("Camping should be disabled by default")
+		}
+
+		withConfig(t, func(config *constants.Configuration) {
+			config.CampingThresholdSec = 10
+			config.CampingDecayMultiplier = 3
+		})
+		if IsCamping(start, start.Add(tables.NewTimeDurationFromDuration(9*time.Second))) {
+			t.Error("Circle still should not be camping before the threshold")
+		}
+		if !IsCamping(start, start.Add(tables.NewTimeDurationFromDuration(10*time.Second))) {
+			t.Error("Circle should be camping once the threshold has passed")
+		}
+		if got := CalculateCampingDecayedMass(1000); got != 970 {
+			t.Errorf("Expected 3%% decay to 970, got %d", got)
+		}
+	})
+
+	t.Run("MassRoundingTotals", func(t *testing.T) {
+		// Splitting stays in integers, so an odd mass is conserved exactly
+		original := uint32(101)
+		half := CalculateHalfMass(original)
+		if half+(original-half) != original {
+			t.Errorf("Split of %d lost mass: %d + %d", original, half, original-half)
+		}
+
+		// 1% of 150 is exactly 1.5, which rounds to even, giving 148 rather than 149
+		if got := CalculateDecayedMass(150); got != 148 {
+			t.Errorf("CalculateDecayedMass(150) = %d, expected 148", got)
+		}
+
+		// 1% of 40 rounds away, but decay still takes a whole unit
+		if got := CalculateDecayedMass(40); got != 39 {
+			t.Errorf("CalculateDecayedMass(40) = %d, expected 39", got)
+		}
+		mass := uint32(100)
+		for tick := 0; tick < 80; tick++ {
+			mass = CalculateDecayedMass(mass)
+		}
+		if mass >= 50 {
+			t.Errorf("Repeated decay from 100 should keep going below 50, got %d", mass)
+		}
+	})
+
 	t.Run("ShouldRecombineCircles", func(t *testing.T) {
 		now := tables.NewTimestampFromTime(time.Now())
 		config := constants.GetGlobalConfiguration()
@@ -715,12 +2285,42 @@ func TestGameLogicHelpers(t *testing.T) {
 			t.Error("Old split should recombine")
 		}
 	})
+
+	t.Run("WithinRecombineDistance", func(t *testing.T) {
+		a := createTestEntity(1, 0, 0, 100)
+		b := createTestEntity(2, 100, 0, 100)
+		gap := 100 - 2*constants.MassToRadius(100)
+
+		if !WithinRecombineDistance(a, b, 0) {
+			t.Error("A max distance of 0 should put every pair in range")
+		}
+		if !WithinRecombineDistance(a, b, gap+1) {
+			t.Error("Circles within the max distance should be in range")
+		}
+		if WithinRecombineDistance(a, b, gap-1) {
+			t.Error("Circles beyond the max distance should be out of range")
+		}
+	})
+
+	t.Run("ClosestEntityPair", func(t *testing.T) {
+		entities := []*tables.Entity{
+			createTestEntity(1, 0, 0, 100),
+			createTestEntity(2, 500, 0, 100),
+			createTestEntity(3, 540, 30, 100),
+		}
+		if i, j := ClosestEntityPair(entities); i != 1 || j != 2 {
+			t.Errorf("Expected pair (1, 2), got (%d, %d)", i, j)
+		}
+		if i, j := ClosestEntityPair(entities[:1]); i != -1 || j != -1 {
+			t.Errorf("A single entity should have no pair, got (%d, %d)", i, j)
+		}
+	})
 }
 
 func TestDebugHelpers(t *testing.T) {
 	t.Run("EntityDebugInfo", func(t *testing.T) {
 		entity := createTestEntity(123, 50, 75, 100)
-		info := EntityDebugInfo(entity)
+		info := EntityDebugInfo(entity, nil)
 
 		if info["entity_id"] != uint32(123) {
 			t.Error("Debug info should include entity ID")
@@ -731,6 +2331,36 @@ func TestDebugHelpers(t *testing.T) {
 		if info["position"] == nil {
 			t.Error("Debug info should include position")
 		}
+		if info["kind"] != "unknown" {
+			t.Errorf("Entity without a kind should report unknown, got %v", info["kind"])
+		}
+	})
+
+	t.Run("EntityDebugInfo circle", func(t *testing.T) {
+		entity := createTestEntity(123, 50, 75, 100)
+		entity.Kind = tables.KindCircle
+		circle := tables.NewCircle(123, 42, types.Up(), 0, tables.Timestamp{})
+		info := EntityDebugInfo(entity, circle)
+
+		if info["kind"] != "circle" {
+			t.Errorf("Kind = %v, want circle", info["kind"])
+		}
+		if info["player_id"] != uint32(42) {
+			t.Errorf("Circle debug info should include player ID, got %v", info["player_id"])
+		}
+	})
+
+	t.Run("EntityDebugInfo food", func(t *testing.T) {
+		entity := createTestEntity(124, 10, 10, 3)
+		entity.Kind = tables.KindFood
+		info := EntityDebugInfo(entity, nil)
+
+		if info["kind"] != "food" {
+			t.Errorf("Kind = %v, want food", info["kind"])
+		}
+		if _, ok := info["player_id"]; ok {
+			t.Error("Food debug info should not include a player ID")
+		}
 	})
 
 	t.Run("CircleDebugInfo", func(t *testing.T) {
@@ -767,6 +2397,14 @@ func TestDebugHelpers(t *testing.T) {
 		if info["avg_mass"] != float32(150) {
 			t.Error("Debug info should show correct average mass")
 		}
+
+		empty := GameStateDebugInfo(nil, nil, nil)
+		if empty["entity_count"] != 0 || empty["total_mass"] != uint32(0) {
+			t.Errorf("Empty debug info should report no entities, got %v", empty)
+		}
+		if empty["avg_mass"] != float32(0) {
+			t.Errorf("Empty debug info should report an average mass of 0, got %v", empty["avg_mass"])
+		}
 	})
 }
 
@@ -825,3 +2463,48 @@ func BenchmarkRangeFloat32(b *testing.B) {
 		RangeFloat32(rng, 0, 100)
 	}
 }
+
+func benchmarkForceIDs() []uint32 {
+	ids := make([]uint32, 5000)
+	for i := range ids {
+		ids[i] = uint32(i + 1)
+	}
+	return ids
+}
+
+func BenchmarkForceAccumulator(b *testing.B) {
+	ids := benchmarkForceIDs()
+	force := types.NewDbVector2(1, 1)
+	acc := NewForceAccumulator(len(ids))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		acc.Reset()
+		for _, id := range ids {
+			acc.Add(id, force)
+			acc.Add(id, force)
+		}
+		for _, id := range ids {
+			_, _ = acc.Get(id)
+		}
+	}
+}
+
+func BenchmarkForceMapPerTick(b *testing.B) {
+	ids := benchmarkForceIDs()
+	force := types.NewDbVector2(1, 1)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		forces := make(map[uint32]types.DbVector2)
+		for _, id := range ids {
+			forces[id] = forces[id].Add(force)
+			forces[id] = forces[id].Add(force)
+		}
+		for _, id := range ids {
+			_ = forces[id]
+		}
+	}
+}
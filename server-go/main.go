@@ -519,7 +519,7 @@ func demoEntityManagement() {
 	}
 
 	// Test food spawning
-	foodEntity, food, err := logic.SpawnFoodEntity(1000, rng)
+	foodEntity, food, err := logic.SpawnFoodEntity(1000, rng, tables.NewTimestampFromTime(time.Now()), nil)
 	if err != nil {
 		fmt.Printf("Error spawning food: %v\n", err)
 	} else {
@@ -630,9 +630,9 @@ func demoValidationAndPerformance() {
 	fmt.Printf("Demo operation took: %v\n", duration)
 
 	// Test debug info
-	debugInfo := logic.EntityDebugInfo(entity)
-	fmt.Printf("Entity debug info: ID=%v, Mass=%v, Radius=%v\n",
-		debugInfo["entity_id"], debugInfo["mass"], debugInfo["radius"])
+	debugInfo := logic.EntityDebugInfo(entity, circle)
+	fmt.Printf("Entity debug info: ID=%v, Kind=%v, Mass=%v, Radius=%v\n",
+		debugInfo["entity_id"], debugInfo["kind"], debugInfo["mass"], debugInfo["radius"])
 
 	// Performance characteristics summary
 	fmt.Println("\n📊 Performance Characteristics:")
@@ -6,6 +6,8 @@ package reducers
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/clockworklabs/Blackholio/server-go/constants"
@@ -27,34 +29,49 @@ func InitReducer(ctx *ReducerContext, args []byte) ReducerResult {
 
 	LogInfo("Initializing Blackholio game module...")
 
-	// Initialize configuration
-	config := tables.NewConfig(0, constants.DEFAULT_WORLD_SIZE)
-	if err := ctx.Database.InsertConfig(config); err != nil {
-		return ErrorResult{Message: fmt.Sprintf("Failed to insert config: %v", err)}
+	// Initialize configuration, keeping the one from a previous Init on re-publish.
+	// The Init caller is the module owner and becomes the admin.
+	if _, err := ctx.Database.GetConfig(); err == nil {
+		LogInfo("Config already exists, skipping insert")
+	} else {
+		config := tables.NewConfig(tables.DefaultArenaID, constants.DEFAULT_WORLD_SIZE)
+		config.RngSeed = uint64(logic.DeriveSeed(ctx.Timestamp.Microseconds, 0))
+		config.AdminIdentity = ctx.Sender
+		if err := ctx.Database.InsertConfig(config); err != nil {
+			return ErrorResult{Message: fmt.Sprintf("Failed to insert config: %v", err)}
+		}
 	}
 
-	// Schedule periodic timers
-	moveInterval := tables.NewTimeDurationFromDuration(constants.MOVE_PLAYERS_INTERVAL)
-	spawnInterval := tables.NewTimeDurationFromDuration(constants.SPAWN_FOOD_INTERVAL)
-	decayInterval := tables.NewTimeDurationFromDuration(constants.CIRCLE_DECAY_INTERVAL)
-
-	// Schedule move all players timer
-	moveSchedule := tables.NewScheduleAtInterval(moveInterval)
-	if err := ctx.Database.ScheduleReducer("MoveAllPlayers", []byte{}, moveSchedule); err != nil {
-		return ErrorResult{Message: fmt.Sprintf("Failed to schedule move timer: %v", err)}
+	// Schedule periodic timers, skipping any that a previous Init already scheduled
+	timers := []struct {
+		reducer  string
+		label    string
+		interval time.Duration
+	}{
+		{"MoveAllPlayers", "move", constants.MOVE_PLAYERS_INTERVAL},
+		{"SpawnFood", "spawn", constants.SPAWN_FOOD_INTERVAL},
+		{"CircleDecay", "decay", constants.CIRCLE_DECAY_INTERVAL},
+		{"CleanupStalePlayers", "stale player cleanup", constants.CLEANUP_STALE_PLAYERS_INTERVAL},
 	}
+	for _, periodic := range timers {
+		scheduled, err := ctx.Database.CountScheduledReducers(periodic.reducer)
+		if err != nil {
+			return ErrorResult{Message: fmt.Sprintf("Failed to check %s timer: %v", periodic.label, err)}
+		}
+		if scheduled > 0 {
+			LogInfo(fmt.Sprintf("%s timer already scheduled, skipping", periodic.reducer))
+			continue
+		}
 
-	// Schedule food spawn timer
-	spawnSchedule := tables.NewScheduleAtInterval(spawnInterval)
-	if err := ctx.Database.ScheduleReducer("SpawnFood", []byte{}, spawnSchedule); err != nil {
-		return ErrorResult{Message: fmt.Sprintf("Failed to schedule spawn timer: %v", err)}
+		schedule := tables.NewScheduleAtInterval(tables.NewTimeDurationFromDuration(periodic.interval))
+		if err := ctx.Database.ScheduleReducer(periodic.reducer, []byte{}, schedule); err != nil {
+			return ErrorResult{Message: fmt.Sprintf("Failed to schedule %s timer: %v", periodic.label, err)}
+		}
 	}
 
-	// Schedule circle decay timer
-	decaySchedule := tables.NewScheduleAtInterval(decayInterval)
-	if err := ctx.Database.ScheduleReducer("CircleDecay", []byte{}, decaySchedule); err != nil {
-		return ErrorResult{Message: fmt.Sprintf("Failed to schedule decay timer: %v", err)}
-	}
+	moduleRuntimeMu.Lock()
+	moduleRuntime = logic.RuntimeState{StartedAt: ctx.Timestamp}
+	moduleRuntimeMu.Unlock()
 
 	LogInfo("Blackholio game module initialized successfully")
 	return SuccessResult{}
@@ -68,10 +85,24 @@ func ConnectReducer(ctx *ReducerContext, args []byte) ReducerResult {
 
 	LogInfo(fmt.Sprintf("Client connecting: %s", ctx.Sender.String()))
 
-	// Check if player was logged out and restore them
-	loggedOutPlayer, err := ctx.Database.GetLoggedOutPlayer(ctx.Sender)
-	if err == nil && loggedOutPlayer != nil {
+	// A player's name and stats (deaths, team) live on their player row, so restoring
+	// that row restores them; new players start from a zeroed row
+	if activePlayer, err := ctx.Database.GetPlayer(ctx.Sender); err == nil {
+		// Reconnecting before the old connection was logged out keeps the active row
+		activePlayer.LastSeen = ctx.Timestamp
+		if err := ctx.Database.UpdatePlayer(activePlayer); err != nil {
+			return ErrorResult{Message: fmt.Sprintf("Failed to refresh player: %v", err)}
+		}
+	} else if loggedOutPlayer, err := ctx.Database.GetLoggedOutPlayer(ctx.Sender); err == nil && loggedOutPlayer != nil {
+		// Players reconnecting within the grace window are admitted even at the cap
+		if !withinReconnectGrace(loggedOutPlayer, ctx.Timestamp) {
+			if err := checkPlayerCap(ctx); err != nil {
+				return ErrorResult{Message: err.Error()}
+			}
+		}
+
 		// Move from logged_out_player to player table
+		loggedOutPlayer.LastSeen = ctx.Timestamp
 		if err := ctx.Database.InsertPlayer(loggedOutPlayer); err != nil {
 			return ErrorResult{Message: fmt.Sprintf("Failed to restore player: %v", err)}
 		}
@@ -79,8 +110,13 @@ func ConnectReducer(ctx *ReducerContext, args []byte) ReducerResult {
 			LogWarn(fmt.Sprintf("Failed to remove logged out player: %v", err))
 		}
 	} else {
+		if err := checkPlayerCap(ctx); err != nil {
+			return ErrorResult{Message: err.Error()}
+		}
+
 		// Create new player
 		player := tables.NewPlayer(ctx.Sender, 0, "")
+		player.LastSeen = ctx.Timestamp
 		if err := ctx.Database.InsertPlayer(player); err != nil {
 			return ErrorResult{Message: fmt.Sprintf("Failed to create player: %v", err)}
 		}
@@ -90,6 +126,32 @@ func ConnectReducer(ctx *ReducerContext, args []byte) ReducerResult {
 	return SuccessResult{}
 }
 
+// checkPlayerCap returns a SERVER_FULL error when the active players already fill
+// MaxConcurrentPlayers
+func checkPlayerCap(ctx *ReducerContext) error {
+	playerCount, err := ctx.Database.GetPlayerCount()
+	if err != nil {
+		return fmt.Errorf("failed to get player count: %w", err)
+	}
+	maxPlayers := constants.GetGlobalConfiguration().MaxConcurrentPlayers
+	if playerCount >= uint64(maxPlayers) {
+		msg := fmt.Sprintf("server is full (%d/%d players)", playerCount, maxPlayers)
+		return NewReducerError(ErrorCodeServerFull, msg, nil)
+	}
+	return nil
+}
+
+// withinReconnectGrace reports whether a logged out player may skip the player cap.
+// With ReconnectGraceSec unset every returning player may; otherwise only those who
+// were last seen within that many seconds.
+func withinReconnectGrace(player *tables.Player, now tables.Timestamp) bool {
+	grace := constants.GetGlobalConfiguration().ReconnectGraceSec
+	if grace <= 0 {
+		return true
+	}
+	return now.Sub(player.LastSeen).ToDuration().Seconds() <= float64(grace)
+}
+
 // DisconnectReducer handles client disconnection
 // Matches: Rust disconnect() and C# Disconnect()
 func DisconnectReducer(ctx *ReducerContext, args []byte) ReducerResult {
@@ -104,16 +166,21 @@ func DisconnectReducer(ctx *ReducerContext, args []byte) ReducerResult {
 		return ErrorResult{Message: fmt.Sprintf("Player not found: %v", err)}
 	}
 
+	player.LastSeen = ctx.Timestamp
+	logOutPlayer(ctx, player)
+
+	LogInfo(fmt.Sprintf("Client disconnected: %s", ctx.Sender.String()))
+	return SuccessResult{}
+}
+
+// logOutPlayer removes a player's circles from the arena and moves them to logged_out_player
+func logOutPlayer(ctx *ReducerContext, player *tables.Player) {
 	// Remove all player circles from the arena
 	circles, err := ctx.Database.GetCirclesByPlayer(player.PlayerID)
 	if err != nil {
 		LogWarn(fmt.Sprintf("Failed to get player circles: %v", err))
-	} else {
-		for _, circle := range circles {
-			if err := logic.DestroyEntity(ctx.Database.DeleteEntity, circle.EntityID); err != nil {
-				LogWarn(fmt.Sprintf("Failed to destroy circle entity %d: %v", circle.EntityID, err))
-			}
-		}
+	} else if err := ctx.Database.DestroyEntities(circleEntityIDs(circles)); err != nil {
+		LogWarn(fmt.Sprintf("Failed to destroy circle entities: %v", err))
 	}
 
 	// Move player to logged_out_player table
@@ -122,17 +189,54 @@ func DisconnectReducer(ctx *ReducerContext, args []byte) ReducerResult {
 	}
 
 	// Remove from active player table
-	if err := ctx.Database.DeletePlayer(ctx.Sender); err != nil {
+	if err := ctx.Database.DeletePlayer(player.Identity); err != nil {
 		LogWarn(fmt.Sprintf("Failed to remove active player: %v", err))
 	}
+}
+
+// circleEntityIDs returns the entity ids of the given circles
+func circleEntityIDs(circles []*tables.Circle) []uint32 {
+	ids := make([]uint32, len(circles))
+	for i, circle := range circles {
+		ids[i] = circle.EntityID
+	}
+	return ids
+}
+
+// CleanupStalePlayersReducer logs out players whose connection vanished without a disconnect.
+// Players not seen within the configured StalePlayerTTL are moved to logged_out_player.
+func CleanupStalePlayersReducer(ctx *ReducerContext, args []byte) ReducerResult {
+	timer := NewPerformanceTimer("CleanupStalePlayers")
+	defer timer.Stop()
+
+	players, err := ctx.Database.GetAllPlayers()
+	if err != nil {
+		return ErrorResult{Message: fmt.Sprintf("Failed to get players: %v", err)}
+	}
+
+	ttl := constants.GetGlobalConfiguration().StalePlayerTTL
+	for _, player := range players {
+		if ctx.Timestamp.Sub(player.LastSeen).ToDuration() <= ttl {
+			continue
+		}
+
+		LogInfo(fmt.Sprintf("Logging out stale player: %s (last seen %s)", player.Identity.String(), player.LastSeen.String()))
+		logOutPlayer(ctx, player)
+	}
 
-	LogInfo(fmt.Sprintf("Client disconnected: %s", ctx.Sender.String()))
 	return SuccessResult{}
 }
 
 // EnterGameArgs represents the arguments for EnterGame reducer
 type EnterGameArgs struct {
 	Name string `json:"name"`
+
+	// Color optionally picks the circle color as 0xRRGGBBAA; the alpha is always forced
+	// to opaque and zero falls back to the player's default color
+	Color uint32 `json:"color,omitempty"`
+
+	// Team optionally joins a team; 0 plays without one
+	Team uint32 `json:"team,omitempty"`
 }
 
 // EnterGameReducer handles player entering the game with a name
@@ -154,7 +258,20 @@ func EnterGameReducer(ctx *ReducerContext, args []byte) ReducerResult {
 		return ErrorResult{Message: fmt.Sprintf("Player not found: %v", err)}
 	}
 
+	if constants.GetGlobalConfiguration().RequireUniqueNames {
+		taken, err := ctx.Database.IsPlayerNameTaken(gameArgs.Name, player.Identity)
+		if err != nil {
+			return ErrorResult{Message: fmt.Sprintf("Failed to check player name: %v", err)}
+		}
+		if taken {
+			msg := fmt.Sprintf("name '%s' is already in use", gameArgs.Name)
+			return ErrorResult{Message: NewReducerError(ErrorCodeInvalidState, msg, nil).Error()}
+		}
+	}
+
 	player.Name = gameArgs.Name
+	player.TeamID = gameArgs.Team
+	player.IsDead = false
 	if err := ctx.Database.UpdatePlayer(player); err != nil {
 		return ErrorResult{Message: fmt.Sprintf("Failed to update player: %v", err)}
 	}
@@ -165,20 +282,39 @@ func EnterGameReducer(ctx *ReducerContext, args []byte) ReducerResult {
 		return ErrorResult{Message: fmt.Sprintf("Failed to get config: %v", err)}
 	}
 
+	entities, err := ctx.Database.GetAllEntities()
+	if err != nil {
+		return ErrorResult{Message: fmt.Sprintf("Failed to get entities: %v", err)}
+	}
+
 	rng := ctx.Rng()
-	entity, circle, err := logic.SpawnPlayerInitialCircle(player.PlayerID, config.WorldSize, rng, ctx.Timestamp)
+	entity, circle, err := logic.SpawnPlayerSafeCircleWithMass(player.PlayerID, logic.PlayerStartMass(), entities, config.WorldSize, rng, ctx.Timestamp)
 	if err != nil {
 		return ErrorResult{Message: fmt.Sprintf("Failed to spawn initial circle: %v", err)}
 	}
+	circle.Color = logic.CircleColor(player.PlayerID, gameArgs.Color)
 
 	if err := ctx.Database.InsertEntity(entity); err != nil {
 		return ErrorResult{Message: fmt.Sprintf("Failed to insert entity: %v", err)}
 	}
 
+	circle.EntityID = entity.EntityID
 	if err := ctx.Database.InsertCircle(circle); err != nil {
 		return ErrorResult{Message: fmt.Sprintf("Failed to insert circle: %v", err)}
 	}
 
+	// Give the first player in an empty world a playable arena right away
+	// instead of waiting for the spawn timer to fill it
+	if gameConfig := constants.GetGlobalConfiguration(); gameConfig.InitialFoodBurst > 0 {
+		playerCount, playerErr := ctx.Database.GetPlayerCount()
+		foodCount, foodErr := ctx.Database.GetFoodCount()
+		if playerErr == nil && foodErr == nil && playerCount == 1 && foodCount == 0 {
+			burst := min(uint64(gameConfig.InitialFoodBurst), logic.EffectiveFoodTarget(playerCount, gameConfig))
+			spawned := spawnFood(ctx, config.WorldSize, burst)
+			LogInfo(fmt.Sprintf("Spawned initial food burst of %d", spawned))
+		}
+	}
+
 	LogInfo(fmt.Sprintf("Player '%s' entered game successfully", gameArgs.Name))
 	return SuccessResult{}
 }
@@ -203,16 +339,29 @@ func RespawnReducer(ctx *ReducerContext, args []byte) ReducerResult {
 		return ErrorResult{Message: fmt.Sprintf("Failed to get config: %v", err)}
 	}
 
+	entities, err := ctx.Database.GetAllEntities()
+	if err != nil {
+		return ErrorResult{Message: fmt.Sprintf("Failed to get entities: %v", err)}
+	}
+
 	rng := ctx.Rng()
-	entity, circle, err := logic.SpawnPlayerInitialCircle(player.PlayerID, config.WorldSize, rng, ctx.Timestamp)
+	entity, circle, err := logic.SpawnPlayerSafeCircleWithMass(player.PlayerID, logic.PlayerStartMass(), entities, config.WorldSize, rng, ctx.Timestamp)
 	if err != nil {
 		return ErrorResult{Message: fmt.Sprintf("Failed to spawn respawn circle: %v", err)}
 	}
 
+	if player.IsDead {
+		player.IsDead = false
+		if err := ctx.Database.UpdatePlayer(player); err != nil {
+			return ErrorResult{Message: fmt.Sprintf("Failed to update player: %v", err)}
+		}
+	}
+
 	if err := ctx.Database.InsertEntity(entity); err != nil {
 		return ErrorResult{Message: fmt.Sprintf("Failed to insert entity: %v", err)}
 	}
 
+	circle.EntityID = entity.EntityID
 	if err := ctx.Database.InsertCircle(circle); err != nil {
 		return ErrorResult{Message: fmt.Sprintf("Failed to insert circle: %v", err)}
 	}
@@ -241,10 +390,8 @@ func SuicideReducer(ctx *ReducerContext, args []byte) ReducerResult {
 		return ErrorResult{Message: fmt.Sprintf("Failed to get player circles: %v", err)}
 	}
 
-	for _, circle := range circles {
-		if err := logic.DestroyEntity(ctx.Database.DeleteEntity, circle.EntityID); err != nil {
-			LogWarn(fmt.Sprintf("Failed to destroy circle entity %d: %v", circle.EntityID, err))
-		}
+	if err := ctx.Database.DestroyEntities(circleEntityIDs(circles)); err != nil {
+		LogWarn(fmt.Sprintf("Failed to destroy circle entities: %v", err))
 	}
 
 	LogInfo(fmt.Sprintf("Player suicide completed: %s", ctx.Sender.String()))
@@ -267,18 +414,37 @@ func UpdatePlayerInputReducer(ctx *ReducerContext, args []byte) ReducerResult {
 		return ErrorResult{Message: fmt.Sprintf("Invalid arguments: %v", err)}
 	}
 
+	if err := checkInputClockDrift(ctx); err != nil {
+		return ErrorResult{Message: err.Error()}
+	}
+
 	// Get player
 	player, err := ctx.Database.GetPlayer(ctx.Sender)
 	if err != nil {
 		return ErrorResult{Message: fmt.Sprintf("Player not found: %v", err)}
 	}
 
+	player.LastSeen = ctx.Timestamp
+	if err := ctx.Database.UpdatePlayer(player); err != nil {
+		LogWarn(fmt.Sprintf("Failed to update player last seen: %v", err))
+	}
+
 	// Update all player circles
 	circles, err := ctx.Database.GetCirclesByPlayer(player.PlayerID)
 	if err != nil {
 		return ErrorResult{Message: fmt.Sprintf("Failed to get player circles: %v", err)}
 	}
 
+	// A dead player's input steers nothing; tell the client to respawn when configured to
+	if len(circles) == 0 {
+		IncrementCounter(MetricIgnoredInputs, 1)
+		if constants.GetGlobalConfiguration().RejectInputWithoutCircles {
+			msg := "player has no circles, respawn to move"
+			return ErrorResult{Message: NewReducerError(ErrorCodeInvalidState, msg, nil).Error()}
+		}
+		return SuccessResult{}
+	}
+
 	for _, circle := range circles {
 		circle.Direction = inputArgs.Direction.Normalized()
 		circle.Speed = Clamp(inputArgs.Direction.Magnitude(), 0.0, 1.0)
@@ -291,6 +457,22 @@ func UpdatePlayerInputReducer(ctx *ReducerContext, args []byte) ReducerResult {
 	return SuccessResult{}
 }
 
+// checkInputClockDrift rejects player input whose timestamp is too far from the server's
+// wall clock, so a client can't spoof timestamps to game timing-based logic
+func checkInputClockDrift(ctx *ReducerContext) error {
+	config := constants.GetGlobalConfiguration()
+	serverNow := Now()
+	if !logic.ExceedsClockDrift(ctx.Timestamp, serverNow, config.MaxInputClockDrift) {
+		return nil
+	}
+
+	IncrementCounter(MetricRejectedInputs, 1)
+	msg := fmt.Sprintf("input timestamp %s drifts more than %v from server time %s",
+		ctx.Timestamp.String(), config.MaxInputClockDrift, serverNow.String())
+	LogWarn(fmt.Sprintf("Rejecting input from %s: %s", ctx.Sender.String(), msg))
+	return NewReducerError(ErrorCodeInvalidArguments, msg, nil)
+}
+
 // PlayerSplitReducer handles player circle splitting
 // Matches: Rust player_split() and C# PlayerSplit()
 func PlayerSplitReducer(ctx *ReducerContext, args []byte) ReducerResult {
@@ -299,34 +481,53 @@ func PlayerSplitReducer(ctx *ReducerContext, args []byte) ReducerResult {
 
 	LogInfo(fmt.Sprintf("Player attempting split: %s", ctx.Sender.String()))
 
+	if err := checkInputClockDrift(ctx); err != nil {
+		return ErrorResult{Message: err.Error()}
+	}
+
 	// Get player
 	player, err := ctx.Database.GetPlayer(ctx.Sender)
 	if err != nil {
 		return ErrorResult{Message: fmt.Sprintf("Player not found: %v", err)}
 	}
 
-	// Get current circles
-	circles, err := ctx.Database.GetCirclesByPlayer(player.PlayerID)
+	// Check the circle cap, which can depend on the player's total mass, before loading the circles
+	circleCount, err := ctx.Database.GetCircleCountByPlayer(player.PlayerID)
 	if err != nil {
-		return ErrorResult{Message: fmt.Sprintf("Failed to get player circles: %v", err)}
+		return ErrorResult{Message: fmt.Sprintf("Failed to count player circles: %v", err)}
+	}
+	entities, err := ctx.Database.GetPlayerEntities(player.PlayerID)
+	if err != nil {
+		return ErrorResult{Message: fmt.Sprintf("Failed to get player entities: %v", err)}
+	}
+	entityMap := make(map[uint32]*tables.Entity, len(entities))
+	totalMass := uint32(0)
+	for _, entity := range entities {
+		entityMap[entity.EntityID] = entity
+		totalMass += entity.Mass
 	}
 
-	circleCount := uint32(len(circles))
 	config := constants.GetGlobalConfiguration()
-
-	if circleCount >= config.MaxCirclesPerPlayer {
+	budget := logic.RemainingSplitBudget(circleCount, totalMass, config)
+	if budget == 0 {
 		return SuccessResult{} // Can't split anymore
 	}
 
-	// Attempt to split circles
+	// Get current circles
+	circles, err := ctx.Database.GetCirclesByPlayer(player.PlayerID)
+	if err != nil {
+		return ErrorResult{Message: fmt.Sprintf("Failed to get player circles: %v", err)}
+	}
+
+	// Attempt to split circles; splitting conserves mass, so totalMass holds throughout
 	for _, circle := range circles {
-		entity, err := ctx.Database.GetEntity(circle.EntityID)
-		if err != nil {
-			LogWarn(fmt.Sprintf("Failed to get entity for circle %d: %v", circle.EntityID, err))
+		entity := entityMap[circle.EntityID]
+		if entity == nil {
+			LogWarn(fmt.Sprintf("No entity for circle %d", circle.EntityID))
 			continue
 		}
 
-		if logic.CanPlayerSplit(entity, circleCount) {
+		if logic.CanPlayerSplitWithMass(entity, circleCount, totalMass) {
 			halfMass := logic.CalculateHalfMass(entity.Mass)
 
 			// Create new circle
@@ -336,6 +537,7 @@ func PlayerSplitReducer(ctx *ReducerContext, args []byte) ReducerResult {
 				LogWarn(fmt.Sprintf("Failed to spawn split circle: %v", err))
 				continue
 			}
+			newCircle.Color = circle.Color
 
 			// Insert new entities
 			if err := ctx.Database.InsertEntity(newEntity); err != nil {
@@ -343,6 +545,7 @@ func PlayerSplitReducer(ctx *ReducerContext, args []byte) ReducerResult {
 				continue
 			}
 
+			newCircle.EntityID = newEntity.EntityID
 			if err := ctx.Database.InsertCircle(newCircle); err != nil {
 				LogWarn(fmt.Sprintf("Failed to insert new circle: %v", err))
 				continue
@@ -361,27 +564,353 @@ func PlayerSplitReducer(ctx *ReducerContext, args []byte) ReducerResult {
 			}
 
 			circleCount++
-			if circleCount >= config.MaxCirclesPerPlayer {
+			budget--
+			if budget == 0 {
 				break
 			}
 		}
 	}
 
-	// Schedule recombine timer
+	scheduleRecombine(ctx, player.PlayerID)
+
+	LogWarn("Player split!")
+	return SuccessResult{}
+}
+
+// scheduleRecombine schedules a CircleRecombine for the player once SplitRecombineDelaySec has passed
+func scheduleRecombine(ctx *ReducerContext, playerID uint32) {
+	config := constants.GetGlobalConfiguration()
 	recombineDelay := tables.NewTimeDurationFromDuration(time.Duration(config.SplitRecombineDelaySec) * time.Second)
 	recombineTime := ctx.Timestamp.Add(recombineDelay)
 	recombineSchedule := tables.NewScheduleAtTime(recombineTime)
 
 	recombineArgs, _ := json.Marshal(map[string]interface{}{
-		"player_id": player.PlayerID,
+		"player_id": playerID,
 	})
 
 	if err := ctx.Database.ScheduleReducer("CircleRecombine", recombineArgs, recombineSchedule); err != nil {
 		LogWarn(fmt.Sprintf("Failed to schedule recombine timer: %v", err))
 	}
+}
 
-	LogWarn("Player split!")
-	return SuccessResult{}
+// CollisionKind describes what a player circle has collided with
+type CollisionKind int
+
+const (
+	// CollisionNone means the other entity is neither food nor a circle
+	CollisionNone CollisionKind = iota
+
+	// CollisionFood means the other entity is food
+	CollisionFood
+
+	// CollisionOwnCircle means the other entity is a circle of the same player
+	CollisionOwnCircle
+
+	// CollisionEnemyCircle means the other entity is a circle of another player
+	CollisionEnemyCircle
+)
+
+// String returns the string representation of the collision kind
+func (k CollisionKind) String() string {
+	switch k {
+	case CollisionFood:
+		return "Food"
+	case CollisionOwnCircle:
+		return "OwnCircle"
+	case CollisionEnemyCircle:
+		return "EnemyCircle"
+	default:
+		return "None"
+	}
+}
+
+// ClassifyCollision determines what kind of entity a player circle collided with.
+// The other entity's Kind is used when set; rows without one fall back to checking
+// the food table explicitly rather than inferring food from a missing circle.
+func ClassifyCollision(db *DatabaseContext, circle *tables.Circle, other *tables.Entity) (CollisionKind, error) {
+	switch other.Kind {
+	case tables.KindFood:
+		return CollisionFood, nil
+	case tables.KindCircle:
+		return classifyCircleCollision(db, circle, other.EntityID), nil
+	}
+
+	isFood, err := db.IsFood(other.EntityID)
+	if err != nil {
+		return CollisionNone, err
+	}
+	if isFood {
+		return CollisionFood, nil
+	}
+	return classifyCircleCollision(db, circle, other.EntityID), nil
+}
+
+// classifyCircleCollision tells own circles from enemy ones by their owning player
+func classifyCircleCollision(db *DatabaseContext, circle *tables.Circle, otherEntityID uint32) CollisionKind {
+	otherPlayerID, owned := db.GetPlayerIDForEntity(otherEntityID)
+	if !owned {
+		return CollisionNone
+	}
+	if otherPlayerID == circle.PlayerID {
+		return CollisionOwnCircle
+	}
+	return CollisionEnemyCircle
+}
+
+// scheduleConsume schedules a ConsumeEntity call after the configured consume delay
+func scheduleConsume(ctx *ReducerContext, consumerEntityID, consumedEntityID uint32) {
+	consumeArgs, _ := json.Marshal(map[string]interface{}{
+		"consumer_entity_id": consumerEntityID,
+		"consumed_entity_id": consumedEntityID,
+	})
+
+	consumeTimer := logic.ScheduleConsumeEntity(consumerEntityID, consumedEntityID, ctx.Timestamp)
+	if err := ctx.Database.ScheduleReducer("ConsumeEntity", consumeArgs, consumeTimer.ScheduledAt); err != nil {
+		LogWarn(fmt.Sprintf("Failed to schedule ConsumeEntity: %v", err))
+	}
+}
+
+// consumePair is a consumption found by the collision pass
+type consumePair struct {
+	ConsumerEntityID uint32
+	ConsumedEntityID uint32
+}
+
+// selectConsumePairs orders candidate consumptions by consumer and then consumed entity ID,
+// keeping only the first pair each entity appears in. Every entity therefore takes part in
+// at most one consume per tick, and the outcome doesn't depend on iteration order.
+func selectConsumePairs(pairs []consumePair) []consumePair {
+	sorted := make([]consumePair, len(pairs))
+	copy(sorted, pairs)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].ConsumerEntityID != sorted[j].ConsumerEntityID {
+			return sorted[i].ConsumerEntityID < sorted[j].ConsumerEntityID
+		}
+		return sorted[i].ConsumedEntityID < sorted[j].ConsumedEntityID
+	})
+
+	claimed := make(map[uint32]bool, len(sorted)*2)
+	selected := sorted[:0]
+	for _, pair := range sorted {
+		if claimed[pair.ConsumerEntityID] || claimed[pair.ConsumedEntityID] {
+			continue
+		}
+		claimed[pair.ConsumerEntityID] = true
+		claimed[pair.ConsumedEntityID] = true
+		selected = append(selected, pair)
+	}
+	return selected
+}
+
+// Circles whose collision checks were cut short by the per-tick budget.
+// They are checked first on the next tick so no circle is starved.
+// The backlog is a best-effort scheduling hint kept in module memory, not game state:
+// it is lost on restart or republish and isn't rolled back with a failed reducer.
+// Either way only the order of the next tick's checks changes, falling back to largest first.
+var (
+	collisionBacklog   = make(map[uint32]bool)
+	collisionBacklogMu sync.Mutex
+)
+
+// runCollisionPass checks circles against all entities not owned by the same player and schedules consumption.
+// At most MaxCollisionChecksPerTick pairs are examined; backlogged circles go first,
+// then larger circles, and a circle that would exceed the budget checks its nearest
+// entities first. With ResolveCircleOverlaps enabled, enemy circles too evenly matched
+// to consume each other are pushed apart instead. Teammates never eat each other, but
+// with TeamMergeAllowed they merge under logic.CanTeamMerge. Spawn-protected circles take
+// part in no consumes, and the rest are scheduled through selectConsumePairs so each
+// entity is in at most one. Returns the number of pairs checked.
+func runCollisionPass(ctx *ReducerContext, allCircles []*tables.Circle, allEntities []*tables.Entity, entityMap map[uint32]*tables.Entity) int {
+	gameConfig := constants.GetGlobalConfiguration()
+	budget := int(gameConfig.MaxCollisionChecksPerTick)
+	resolveOverlaps := gameConfig.ResolveCircleOverlaps
+	teams := playerTeams(ctx)
+
+	collisionBacklogMu.Lock()
+	backlog := collisionBacklog
+	collisionBacklogMu.Unlock()
+
+	circles := make([]*tables.Circle, 0, len(allCircles))
+	owners := make(map[uint32]uint32, len(allCircles))
+	protected := make(map[uint32]bool)
+	for _, circle := range allCircles {
+		owners[circle.EntityID] = circle.PlayerID
+		if logic.IsCircleProtected(circle, ctx.Timestamp) {
+			protected[circle.EntityID] = true
+		}
+		if entityMap[circle.EntityID] != nil {
+			circles = append(circles, circle)
+		}
+	}
+	sort.SliceStable(circles, func(i, j int) bool {
+		a, b := circles[i].EntityID, circles[j].EntityID
+		if backlog[a] != backlog[b] {
+			return backlog[a]
+		}
+		if entityMap[a].Mass != entityMap[b].Mass {
+			return entityMap[a].Mass > entityMap[b].Mass
+		}
+		return a < b
+	})
+
+	checks := 0
+	nextBacklog := make(map[uint32]bool)
+	var candidates []consumePair
+	for i, circle := range circles {
+		if checks >= budget {
+			for _, skipped := range circles[i:] {
+				nextBacklog[skipped.EntityID] = true
+			}
+			break
+		}
+
+		circleEntity := entityMap[circle.EntityID]
+
+		others := allEntities
+		if budget-checks < len(allEntities)-1 {
+			// This circle won't finish within the budget, so check its nearest entities first
+			others = make([]*tables.Entity, len(allEntities))
+			copy(others, allEntities)
+			sort.SliceStable(others, func(a, b int) bool {
+				return others[a].Position.DistanceSquared(circleEntity.Position) <
+					others[b].Position.DistanceSquared(circleEntity.Position)
+			})
+		}
+
+		for _, otherEntity := range others {
+			if otherEntity.EntityID == circleEntity.EntityID {
+				continue
+			}
+
+			// Circles of the same player may overlap freely; they only merge through
+			// CircleRecombine, so only separation physics applies to them here
+			if ownerID, owned := owners[otherEntity.EntityID]; owned && ownerID == circle.PlayerID {
+				continue
+			}
+
+			if checks >= budget {
+				nextBacklog[circle.EntityID] = true
+				break
+			}
+			checks++
+
+			// Evenly matched enemy circles bump into each other instead of interpenetrating
+			if _, owned := owners[otherEntity.EntityID]; owned && resolveOverlaps &&
+				logic.ResolveOverlapPositions(circleEntity, otherEntity) {
+				for _, moved := range []*tables.Entity{circleEntity, otherEntity} {
+					if err := ctx.Database.UpdateEntity(moved); err != nil {
+						LogWarn(fmt.Sprintf("Failed to update separated entity %d: %v", moved.EntityID, err))
+					}
+				}
+				continue
+			}
+
+			// Spawn-protected circles neither eat nor get eaten
+			if protected[circleEntity.EntityID] || protected[otherEntity.EntityID] {
+				continue
+			}
+
+			// Teammates don't eat each other, though they may opt in to merging
+			if ownerID, owned := owners[otherEntity.EntityID]; owned && teams.sameTeam(circle.PlayerID, ownerID) {
+				if gameConfig.TeamMergeAllowed && logic.CanTeamMerge(circleEntity, otherEntity) {
+					candidates = append(candidates, consumePair{circleEntity.EntityID, otherEntity.EntityID})
+				}
+				continue
+			}
+
+			if !logic.CanConsume(circleEntity, otherEntity, logic.DefaultOverlapMode) {
+				continue
+			}
+
+			kind, err := ClassifyCollision(ctx.Database, circle, otherEntity)
+			if err != nil {
+				LogWarn(fmt.Sprintf("Failed to classify collision with entity %d: %v", otherEntity.EntityID, err))
+				continue
+			}
+
+			// Player vs food and player vs player collisions are candidates for an immediate consume
+			if kind == CollisionFood || kind == CollisionEnemyCircle {
+				candidates = append(candidates, consumePair{circleEntity.EntityID, otherEntity.EntityID})
+			}
+		}
+	}
+
+	for _, pair := range selectConsumePairs(candidates) {
+		scheduleConsume(ctx, pair.ConsumerEntityID, pair.ConsumedEntityID)
+	}
+
+	if len(nextBacklog) > 0 {
+		IncrementCounter(MetricCollisionBudgetOverflows, 1)
+		LogWarn(fmt.Sprintf("Collision budget of %d checks exhausted, deferring %d circles", budget, len(nextBacklog)))
+	}
+	IncrementCounter(MetricCollisionChecks, uint64(checks))
+
+	collisionBacklogMu.Lock()
+	collisionBacklog = nextBacklog
+	collisionBacklogMu.Unlock()
+
+	return checks
+}
+
+// teamMap maps player ids to the team they play on
+type teamMap map[uint32]uint32
+
+// playerTeams returns the team of every active player that is on one
+func playerTeams(ctx *ReducerContext) teamMap {
+	teams := make(teamMap)
+	players, err := ctx.Database.GetAllPlayers()
+	if err != nil {
+		LogWarn(fmt.Sprintf("Failed to get players for teams: %v", err))
+		return teams
+	}
+	for _, player := range players {
+		if player.TeamID != 0 {
+			teams[player.PlayerID] = player.TeamID
+		}
+	}
+	return teams
+}
+
+// sameTeam reports whether two different players are on the same team
+func (t teamMap) sameTeam(a, b uint32) bool {
+	team, ok := t[a]
+	return ok && a != b && t[b] == team
+}
+
+// Fixed-timestep clock shared by MoveAllPlayers calls, so the fraction of a physics
+// substep left over from one tick carries into the next
+var (
+	physicsClock   *logic.FixedTimestep
+	physicsClockMu sync.Mutex
+)
+
+// physicsSubsteps advances the physics clock by one MovePlayersInterval and returns how
+// many substeps are due and the length of each in seconds
+func physicsSubsteps(config *constants.Configuration) (int, float32) {
+	physicsClockMu.Lock()
+	defer physicsClockMu.Unlock()
+
+	if step := config.PhysicsStep(); physicsClock == nil || physicsClock.Step != step {
+		physicsClock = logic.NewFixedTimestep(step)
+	}
+	steps := physicsClock.Advance(config.MovePlayersInterval)
+	return steps, float32(physicsClock.Step.Seconds())
+}
+
+// idleTick reports whether a scheduled tick can be skipped because no players are
+// connected and SkipIdleTicks is enabled. Entities, food included, are left as they are.
+func idleTick(ctx *ReducerContext) bool {
+	if !constants.GetGlobalConfiguration().SkipIdleTicks {
+		return false
+	}
+	playerCount, err := ctx.Database.GetPlayerCount()
+	return err == nil && playerCount == 0
+}
+
+// forceAccumulatorPool holds the per-circle force scratch buffers reused across
+// MoveAllPlayers ticks
+var forceAccumulatorPool = sync.Pool{
+	New: func() any { return logic.NewForceAccumulator(0) },
 }
 
 // MoveAllPlayersReducer handles moving all players (main game tick)
@@ -390,6 +919,14 @@ func MoveAllPlayersReducer(ctx *ReducerContext, args []byte) ReducerResult {
 	timer := NewPerformanceTimer("MoveAllPlayers")
 	defer timer.Stop()
 
+	moduleRuntimeMu.Lock()
+	moduleRuntime.Ticks++
+	moduleRuntimeMu.Unlock()
+
+	if idleTick(ctx) {
+		return SuccessResult{}
+	}
+
 	// Get world configuration
 	config, err := GetConfig(ctx)
 	if err != nil {
@@ -414,9 +951,11 @@ func MoveAllPlayersReducer(ctx *ReducerContext, args []byte) ReducerResult {
 	}
 
 	// Calculate movement directions for all circles
-	circleDirections := make(map[uint32]types.DbVector2)
+	circleDirections := forceAccumulatorPool.Get().(*logic.ForceAccumulator)
+	defer forceAccumulatorPool.Put(circleDirections)
+	circleDirections.Reset()
 	for _, circle := range allCircles {
-		circleDirections[circle.EntityID] = circle.Direction.Mul(circle.Speed)
+		circleDirections.Add(circle.EntityID, circle.Direction.Mul(circle.Speed))
 	}
 
 	// Handle split circle physics for each player
@@ -459,83 +998,104 @@ func MoveAllPlayersReducer(ctx *ReducerContext, args []byte) ReducerResult {
 					forceA := gravityForce.Add(separationForce).Div(2.0)
 					forceB := gravityForce.Mul(-1).Add(separationForce.Mul(-1)).Div(2.0)
 
-					if dir, exists := circleDirections[entityA.EntityID]; exists {
-						circleDirections[entityA.EntityID] = dir.Add(forceA)
+					if _, exists := circleDirections.Get(entityA.EntityID); exists {
+						circleDirections.Add(entityA.EntityID, forceA)
 					}
-					if dir, exists := circleDirections[entityB.EntityID]; exists {
-						circleDirections[entityB.EntityID] = dir.Add(forceB)
+					if _, exists := circleDirections.Get(entityB.EntityID); exists {
+						circleDirections.Add(entityB.EntityID, forceB)
 					}
 				}
 			}
 		}
 	}
 
-	// Move all circles
+	// Move all circles, in as many physics substeps as PhysicsTickHz calls for this tick
+	steps, stepSeconds := physicsSubsteps(constants.GetGlobalConfiguration())
+	IncrementCounter(MetricPhysicsSubsteps, uint64(steps))
+	// Circle.LastMovedAt is only kept up to date while the camping penalty is on
+	trackCamping := constants.GetGlobalConfiguration().CampingThresholdSec > 0
 	for _, circle := range allCircles {
 		entity := entityMap[circle.EntityID]
 		if entity == nil {
 			continue
 		}
 
-		direction := circleDirections[circle.EntityID]
-		newPosition := logic.UpdateCirclePosition(entity, direction, 0.05, config.WorldSize) // 50ms delta
-
-		entity.Position = newPosition
+		direction, _ := circleDirections.Get(circle.EntityID)
+		before := entity.Position
+		for step := 0; step < steps; step++ {
+			entity.Position = logic.UpdateCirclePosition(entity, direction, stepSeconds, config.WorldSize)
+		}
 		if err := ctx.Database.UpdateEntity(entity); err != nil {
 			LogWarn(fmt.Sprintf("Failed to update entity position %d: %v", entity.EntityID, err))
 		}
+		if trackCamping && entity.Position != before {
+			circle.LastMovedAt = ctx.Timestamp
+			if err := ctx.Database.UpdateCircle(circle); err != nil {
+				LogWarn(fmt.Sprintf("Failed to update circle %d last movement: %v", circle.EntityID, err))
+			}
+		}
 	}
 
+	// Large circles draw in nearby food
+	applyFoodMagnet(ctx, allCircles, allEntities, entityMap, config.WorldSize, steps, stepSeconds)
+
 	// Check collisions
+	runCollisionPass(ctx, allCircles, allEntities, entityMap)
+
+	return SuccessResult{}
+}
+
+// applyFoodMagnet drifts food toward every circle heavy enough to act as a magnet,
+// once per physics substep of stepSeconds. It does nothing unless FoodMagnetStrength
+// is configured.
+func applyFoodMagnet(ctx *ReducerContext, allCircles []*tables.Circle, allEntities []*tables.Entity, entityMap map[uint32]*tables.Entity, worldSize uint64, steps int, stepSeconds float32) {
+	gameConfig := constants.GetGlobalConfiguration()
+	if gameConfig.FoodMagnetStrength <= 0 {
+		return
+	}
+
+	circleIDs := make(map[uint32]bool, len(allCircles))
+	var magnets []*tables.Entity
 	for _, circle := range allCircles {
-		circleEntity := entityMap[circle.EntityID]
-		if circleEntity == nil {
-			continue
+		circleIDs[circle.EntityID] = true
+		if entity := entityMap[circle.EntityID]; entity != nil && entity.Mass >= gameConfig.FoodMagnetMinMass {
+			magnets = append(magnets, entity)
 		}
+	}
+	if len(magnets) == 0 || steps == 0 {
+		return
+	}
 
-		for _, otherEntity := range allEntities {
-			if otherEntity.EntityID == circleEntity.EntityID {
+	for _, food := range allEntities {
+		if circleIDs[food.EntityID] || food.Kind == tables.KindCircle {
+			continue
+		}
+		if food.Kind != tables.KindFood {
+			if isFood, err := ctx.Database.IsFood(food.EntityID); err != nil || !isFood {
 				continue
 			}
+		}
 
-			if logic.IsOverlapping(circleEntity, otherEntity) {
-				// Check if it's another circle from a different player
-				otherCircle, err := ctx.Database.GetCircle(otherEntity.EntityID)
-				if err == nil && otherCircle != nil {
-					if otherCircle.PlayerID != circle.PlayerID {
-						// Player vs player collision
-						if logic.CanConsumeEntity(circleEntity.Mass, otherEntity.Mass) {
-							// Schedule consumption immediately
-							consumeArgs, _ := json.Marshal(map[string]interface{}{
-								"consumer_entity_id": circleEntity.EntityID,
-								"consumed_entity_id": otherEntity.EntityID,
-							})
-
-							// Schedule for immediate execution (current timestamp)
-							schedule := tables.NewScheduleAtTime(ctx.Timestamp)
-							if err := ctx.Database.ScheduleReducer("ConsumeEntity", consumeArgs, schedule); err != nil {
-								LogWarn(fmt.Sprintf("Failed to schedule ConsumeEntity: %v", err))
-							}
-						}
-					}
-				} else {
-					// Player vs food collision - schedule for immediate consumption
-					consumeArgs, _ := json.Marshal(map[string]interface{}{
-						"consumer_entity_id": circleEntity.EntityID,
-						"consumed_entity_id": otherEntity.EntityID,
-					})
-
-					// Schedule for immediate execution (current timestamp)
-					schedule := tables.NewScheduleAtTime(ctx.Timestamp)
-					if err := ctx.Database.ScheduleReducer("ConsumeEntity", consumeArgs, schedule); err != nil {
-						LogWarn(fmt.Sprintf("Failed to schedule ConsumeEntity: %v", err))
-					}
-				}
+		start := food.Position
+		radius := constants.MassToRadius(food.Mass)
+		for step := 0; step < steps; step++ {
+			var pull types.DbVector2
+			for _, magnet := range magnets {
+				pull = pull.Add(logic.FoodMagnetPull(magnet, food, stepSeconds))
 			}
+			if pull.IsZero() {
+				break
+			}
+			food.Position = logic.ClampPositionToWorld(food.Position.Add(pull), radius, worldSize)
+		}
+		if food.Position == start {
+			continue
 		}
-	}
 
-	return SuccessResult{}
+		if err := ctx.Database.UpdateEntity(food); err != nil {
+			LogWarn(fmt.Sprintf("Failed to update attracted food %d: %v", food.EntityID, err))
+		}
+	}
 }
 
 // Helper function to clamp float values
@@ -574,10 +1134,34 @@ func SpawnFoodReducer(ctx *ReducerContext, args []byte) ReducerResult {
 		return ErrorResult{Message: fmt.Sprintf("Failed to get world config: %v", err)}
 	}
 
-	// Spawn food until we reach target count
+	// Clear out food nobody has eaten within FoodTTL so it respawns somewhere else
+	if config.FoodTTL > 0 {
+		despawned, err := logic.DespawnStaleFood(ctx.Database, config.FoodTTL, ctx.Timestamp)
+		if err != nil {
+			LogWarn(fmt.Sprintf("Failed to despawn stale food: %v", err))
+		}
+		if despawned > 0 {
+			foodCount -= min(foodCount, uint64(despawned))
+			LogInfo(fmt.Sprintf("Despawned %d stale food", despawned))
+		}
+	}
+
+	// Spawn food until we reach the target count for the current number of players
+	target := logic.EffectiveFoodTarget(playerCount, config)
+	if foodCount < target {
+		spawnFood(ctx, worldConfig.WorldSize, target-foodCount)
+	}
+
+	return SuccessResult{}
+}
+
+// spawnFood inserts up to count food entities and returns how many were spawned
+func spawnFood(ctx *ReducerContext, worldSize uint64, count uint64) uint64 {
 	rng := ctx.Rng()
-	for foodCount < uint64(config.TargetFoodCount) {
-		entity, food, err := logic.SpawnFoodEntity(worldConfig.WorldSize, rng)
+	nearby := foodSpawnGrid(ctx)
+	spawned := uint64(0)
+	for spawned < count {
+		entity, food, err := logic.SpawnFoodEntity(worldSize, rng, ctx.Timestamp, nearby)
 		if err != nil {
 			LogWarn(fmt.Sprintf("Failed to spawn food entity: %v", err))
 			break
@@ -588,16 +1172,41 @@ func SpawnFoodReducer(ctx *ReducerContext, args []byte) ReducerResult {
 			continue
 		}
 
+		food.EntityID = entity.EntityID
 		if err := ctx.Database.InsertFood(food); err != nil {
 			LogWarn(fmt.Sprintf("Failed to insert food: %v", err))
 			continue
 		}
 
-		foodCount++
+		spawned++
 		LogInfo(fmt.Sprintf("Spawned food! EntityID: %d", entity.EntityID))
 	}
+	return spawned
+}
 
-	return SuccessResult{}
+// foodSpawnGrid returns a grid of the existing food and circles for spawnFood to keep
+// MinFoodSpacing and FoodAvoidCircles against, or nil when both are disabled
+func foodSpawnGrid(ctx *ReducerContext) *logic.SpatialGrid {
+	config := constants.GetGlobalConfiguration()
+	if config.MinFoodSpacing <= 0 && !config.FoodAvoidCircles {
+		return nil
+	}
+
+	entities, err := ctx.Database.GetAllEntities()
+	if err != nil {
+		LogWarn(fmt.Sprintf("Failed to get entities for food spawning: %v", err))
+		return logic.NewFoodSpacingGrid(nil, nil)
+	}
+	var food, circles []*tables.Entity
+	for _, entity := range entities {
+		switch entity.Kind {
+		case tables.KindFood:
+			food = append(food, entity)
+		case tables.KindCircle:
+			circles = append(circles, entity)
+		}
+	}
+	return logic.NewFoodSpacingGrid(food, circles)
 }
 
 // CircleDecayReducer handles circle mass decay
@@ -606,12 +1215,25 @@ func CircleDecayReducer(ctx *ReducerContext, args []byte) ReducerResult {
 	timer := NewPerformanceTimer("CircleDecay")
 	defer timer.Stop()
 
+	if idleTick(ctx) {
+		return SuccessResult{}
+	}
+
 	// Get all circles
 	circles, err := ctx.Database.GetAllCircles()
 	if err != nil {
 		return ErrorResult{Message: fmt.Sprintf("Failed to get circles: %v", err)}
 	}
 
+	// Circles far behind the leader can be exempt from decay
+	config := constants.GetGlobalConfiguration()
+	leaderMass := uint32(0)
+	if config.DecayExemptLeaderFraction > 0 {
+		if leader, err := ctx.Database.GetLargestEntity(); err == nil {
+			leaderMass = leader.Mass
+		}
+	}
+
 	// Decay each circle that is above starting mass
 	for _, circle := range circles {
 		entity, err := ctx.Database.GetEntity(circle.EntityID)
@@ -620,8 +1242,17 @@ func CircleDecayReducer(ctx *ReducerContext, args []byte) ReducerResult {
 			continue
 		}
 
-		if logic.ShouldCircleDecay(entity) {
-			entity.Mass = logic.CalculateDecayedMass(entity.Mass)
+		if logic.IsDecayExempt(entity.Mass, leaderMass, config) {
+			continue
+		}
+
+		if logic.ShouldCircleDecayAt(entity, circle, ctx.Timestamp) {
+			// Circles that sit still too long decay faster
+			if logic.IsCamping(circle.LastMovedAt, ctx.Timestamp) {
+				entity.Mass = logic.CalculateCampingDecayedMass(entity.Mass)
+			} else {
+				entity.Mass = logic.CalculateDecayedMass(entity.Mass)
+			}
 
 			if err := ctx.Database.UpdateEntity(entity); err != nil {
 				LogWarn(fmt.Sprintf("Failed to update decayed entity %d: %v", entity.EntityID, err))
@@ -639,6 +1270,11 @@ type CircleRecombineArgs struct {
 
 // CircleRecombineReducer handles circle recombination for a player
 // Matches: Rust circle_recombine() and C# CircleRecombine()
+// Ready circles further than RecombineMaxDistance from the first one are retried on the
+// next MovePlayersInterval. After MaxRecombineAttempts retries the closest of them are
+// teleported together and merged, so a player can't stay stuck waiting to recombine.
+// The attempt count and the pending retry live on the player row, and a player has at
+// most one retry pending however many splits scheduled a recombine.
 func CircleRecombineReducer(ctx *ReducerContext, args []byte) ReducerResult {
 	timer := NewPerformanceTimer("CircleRecombine")
 	defer timer.Stop()
@@ -648,14 +1284,57 @@ func CircleRecombineReducer(ctx *ReducerContext, args []byte) ReducerResult {
 		return ErrorResult{Message: fmt.Sprintf("Invalid arguments: %v", err)}
 	}
 
-	// Get player circles
-	circles, err := ctx.Database.GetCirclesByPlayer(recombineArgs.PlayerID)
+	stranded, err := recombineReadyCircles(ctx, recombineArgs.PlayerID)
 	if err != nil {
-		return ErrorResult{Message: fmt.Sprintf("Failed to get player circles: %v", err)}
+		return ErrorResult{Message: fmt.Sprintf("Failed to recombine circles: %v", err)}
+	}
+
+	player, err := ctx.Database.GetPlayerByPlayerID(recombineArgs.PlayerID)
+	if err != nil {
+		return SuccessResult{} // The player has left, nobody to retry for
 	}
 
+	config := constants.GetGlobalConfiguration()
+	if len(stranded) > 1 {
+		player.RecombineAttempts++
+		if config.MaxRecombineAttempts > 0 && player.RecombineAttempts > config.MaxRecombineAttempts {
+			stranded = forceRecombineClosest(ctx, stranded)
+			player.RecombineAttempts = 0
+		}
+	} else {
+		player.RecombineAttempts = 0
+	}
+
+	// Try the stranded circles again once physics has had a tick to pull them together,
+	// unless a retry is already on its way
+	if len(stranded) > 1 && player.RecombineRetryAt.Microseconds <= ctx.Timestamp.Microseconds {
+		retryArgs, _ := json.Marshal(map[string]interface{}{
+			"player_id": recombineArgs.PlayerID,
+		})
+		retryTime := ctx.Timestamp.Add(tables.NewTimeDurationFromDuration(config.MovePlayersInterval))
+		if err := ctx.Database.ScheduleReducer("CircleRecombine", retryArgs, tables.NewScheduleAtTime(retryTime)); err != nil {
+			LogWarn(fmt.Sprintf("Failed to reschedule recombine timer: %v", err))
+		} else {
+			player.RecombineRetryAt = retryTime
+		}
+	}
+
+	if err := ctx.Database.UpdatePlayer(player); err != nil {
+		return ErrorResult{Message: fmt.Sprintf("Failed to update player recombine state: %v", err)}
+	}
+	return SuccessResult{}
+}
+
+// recombineReadyCircles schedules merges of a player's circles that are past the
+// recombine delay and within RecombineMaxDistance of the first of them. It returns the
+// ready circles left unmerged, including that first one; one or none means nothing is left.
+func recombineReadyCircles(ctx *ReducerContext, playerID uint32) ([]*tables.Entity, error) {
+	circles, err := ctx.Database.GetCirclesByPlayer(playerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get player circles: %w", err)
+	}
 	if len(circles) <= 1 {
-		return SuccessResult{} // No circles to recombine
+		return nil, nil // No circles to recombine
 	}
 
 	// Find circles that are ready to recombine
@@ -675,26 +1354,67 @@ func CircleRecombineReducer(ctx *ReducerContext, args []byte) ReducerResult {
 	}
 
 	if len(recombiningEntities) <= 1 {
-		return SuccessResult{} // Nothing to recombine
+		return nil, nil // Nothing to recombine
 	}
 
-	// Schedule consumption of all circles into the first one
-	baseEntityID := recombiningEntities[0].EntityID
+	// Schedule consumption of all circles in range into the first one
+	base := recombiningEntities[0]
+	stranded := []*tables.Entity{base}
 	for i := 1; i < len(recombiningEntities); i++ {
-		// Schedule consumption for immediate execution
-		consumeArgs, _ := json.Marshal(map[string]interface{}{
-			"consumer_entity_id": baseEntityID,
-			"consumed_entity_id": recombiningEntities[i].EntityID,
-		})
-
-		// Schedule for immediate execution (current timestamp)
-		schedule := tables.NewScheduleAtTime(ctx.Timestamp)
-		if err := ctx.Database.ScheduleReducer("ConsumeEntity", consumeArgs, schedule); err != nil {
-			LogWarn(fmt.Sprintf("Failed to schedule ConsumeEntity for recombine: %v", err))
+		if !logic.WithinRecombineDistance(base, recombiningEntities[i], config.RecombineMaxDistance) {
+			stranded = append(stranded, recombiningEntities[i])
+			continue
 		}
+
+		scheduleRecombineConsume(ctx, base.EntityID, recombiningEntities[i].EntityID)
 	}
+	return stranded, nil
+}
 
-	return SuccessResult{}
+// scheduleRecombineConsume schedules a ConsumeEntity call merging two circles of the same player
+func scheduleRecombineConsume(ctx *ReducerContext, consumerEntityID, consumedEntityID uint32) {
+	consumeArgs, _ := json.Marshal(map[string]interface{}{
+		"consumer_entity_id": consumerEntityID,
+		"consumed_entity_id": consumedEntityID,
+	})
+
+	// Schedule for immediate execution (current timestamp)
+	schedule := tables.NewScheduleAtTime(ctx.Timestamp)
+	if err := ctx.Database.ScheduleReducer("ConsumeEntity", consumeArgs, schedule); err != nil {
+		LogWarn(fmt.Sprintf("Failed to schedule ConsumeEntity for recombine: %v", err))
+	}
+}
+
+// forceRecombineClosest teleports the smaller of the two closest entities onto the larger
+// and schedules the merge, returning the entities still left to recombine
+func forceRecombineClosest(ctx *ReducerContext, entities []*tables.Entity) []*tables.Entity {
+	i, j := logic.ClosestEntityPair(entities)
+	if i < 0 {
+		return entities
+	}
+	consumer, consumed := entities[i], entities[j]
+	if consumed.Mass > consumer.Mass {
+		consumer, consumed = consumed, consumer
+	}
+
+	consumed.Position = consumer.Position
+	if err := ctx.Database.UpdateEntity(consumed); err != nil {
+		LogWarn(fmt.Sprintf("Failed to teleport entity %d for forced recombine: %v", consumed.EntityID, err))
+		return entities
+	}
+	scheduleRecombineConsume(ctx, consumer.EntityID, consumed.EntityID)
+
+	IncrementCounter(MetricForcedRecombines, 1)
+	LogWarn(fmt.Sprintf("Forced recombine of entity %d into %d after %d attempts",
+		consumed.EntityID, consumer.EntityID, constants.GetGlobalConfiguration().MaxRecombineAttempts))
+
+	remaining := make([]*tables.Entity, 0, len(entities)-1)
+	for _, entity := range entities {
+		if entity.EntityID != consumed.EntityID {
+			remaining = append(remaining, entity)
+		}
+	}
+	return remaining
 }
 
 // ConsumeEntityArgs represents the arguments for ConsumeEntity reducer
@@ -725,14 +1445,37 @@ func ConsumeEntityReducer(ctx *ReducerContext, args []byte) ReducerResult {
 		return ErrorResult{Message: fmt.Sprintf("Consumer entity doesn't exist: %v", err)}
 	}
 
-	// Transfer mass
-	consumerEntity.Mass += consumedEntity.Mass
+	// Remember the owner before the circle row goes away
+	consumedPlayerID, consumedCircle := ctx.Database.GetPlayerIDForEntity(consumedEntity.EntityID)
+
+	// Recombining circles meet at their center of mass; eating food or enemies never moves the eater
+	if consumedCircle && constants.GetGlobalConfiguration().RecombineAtCenterOfMass {
+		if consumerPlayerID, consumerCircle := ctx.Database.GetPlayerIDForEntity(consumerEntity.EntityID); consumerCircle && consumerPlayerID == consumedPlayerID {
+			consumerEntity.Position = logic.CalculateCenterOfMass([]*tables.Entity{consumerEntity, consumedEntity})
+		}
+	}
+
+	// Transfer mass; only food is scaled, circles always hand over their full mass
+	gained := consumedEntity.Mass
+	if isFood, _ := ctx.Database.IsFood(consumedEntity.EntityID); isFood {
+		gained = logic.FoodMassGain(consumedEntity.Mass)
+	}
+	overflow := logic.MassOverflow(consumerEntity.Mass, gained)
+	consumerEntity.Mass = logic.AddMassSaturating(consumerEntity.Mass, gained)
 
 	// Destroy consumed entity
-	if err := logic.DestroyEntity(ctx.Database.DeleteEntity, consumedEntity.EntityID); err != nil {
+	if err := logic.DestroyEntity(ctx.Database.DestroyEntity, consumedEntity.EntityID); err != nil {
 		LogWarn(fmt.Sprintf("Failed to destroy consumed entity %d: %v", consumedEntity.EntityID, err))
 	}
 
+	if consumedCircle {
+		recordDeathIfLastCircle(ctx, consumedPlayerID, consumerEntity.EntityID)
+	}
+
+	if overflow > 0 && constants.GetGlobalConfiguration().SplitMassOverflow {
+		spawnOverflowCircle(ctx, consumerEntity, overflow)
+	}
+
 	// Update consumer entity
 	if err := ctx.Database.UpdateEntity(consumerEntity); err != nil {
 		return ErrorResult{Message: fmt.Sprintf("Failed to update consumer entity: %v", err)}
@@ -741,26 +1484,321 @@ func ConsumeEntityReducer(ctx *ReducerContext, args []byte) ReducerResult {
 	return SuccessResult{}
 }
 
+// spawnOverflowCircle turns mass a consume pushed past MaxCircleMass into a new circle
+// for the consumer's player, when they have circle budget left, and schedules it to
+// recombine like a split. The new circle holds at most MaxCircleMass; whatever doesn't
+// fit, or all of it when no circle can be spawned, is discarded. Returns the discarded mass.
+func spawnOverflowCircle(ctx *ReducerContext, consumerEntity *tables.Entity, overflow uint32) uint32 {
+	circle, err := ctx.Database.GetCircle(consumerEntity.EntityID)
+	if err != nil {
+		return overflow // Only player circles split
+	}
+
+	config := constants.GetGlobalConfiguration()
+	circleCount, err := ctx.Database.GetCircleCountByPlayer(circle.PlayerID)
+	if err != nil {
+		return overflow
+	}
+	entities, err := ctx.Database.GetPlayerEntities(circle.PlayerID)
+	if err != nil {
+		return overflow
+	}
+	// The consumer's row isn't updated yet, so count its new mass plus the overflow
+	totalMass := consumerEntity.Mass + overflow
+	for _, entity := range entities {
+		if entity.EntityID != consumerEntity.EntityID {
+			totalMass += entity.Mass
+		}
+	}
+	if logic.RemainingSplitBudget(circleCount, totalMass, config) == 0 {
+		return overflow
+	}
+
+	mass := overflow
+	if mass > config.MaxCircleMass {
+		mass = config.MaxCircleMass
+	}
+
+	newPosition := consumerEntity.Position.Add(circle.Direction)
+	newEntity, newCircle, err := logic.SpawnCircleAt(circle.PlayerID, mass, newPosition, ctx.Timestamp)
+	if err != nil {
+		LogWarn(fmt.Sprintf("Failed to spawn overflow circle: %v", err))
+		return overflow
+	}
+	newCircle.Color = circle.Color
+	if err := ctx.Database.InsertEntity(newEntity); err != nil {
+		LogWarn(fmt.Sprintf("Failed to insert overflow entity: %v", err))
+		return overflow
+	}
+	newCircle.EntityID = newEntity.EntityID
+	if err := ctx.Database.InsertCircle(newCircle); err != nil {
+		LogWarn(fmt.Sprintf("Failed to insert overflow circle: %v", err))
+		return overflow
+	}
+	scheduleRecombine(ctx, circle.PlayerID)
+
+	LogInfo(fmt.Sprintf("Split %d overflow mass from entity %d into circle %d", mass, consumerEntity.EntityID, newEntity.EntityID))
+	if discarded := overflow - mass; discarded > 0 {
+		LogInfo(fmt.Sprintf("Discarded %d overflow mass above MaxCircleMass from entity %d", discarded, consumerEntity.EntityID))
+		return discarded
+	}
+	return 0
+}
+
+// recordDeathIfLastCircle counts a death for a player left without circles after a consume,
+// flagging them as dead when MarkDeadPlayers is enabled
+func recordDeathIfLastCircle(ctx *ReducerContext, playerID, consumerEntityID uint32) {
+	remaining, err := ctx.Database.GetCircleCountByPlayer(playerID)
+	if err != nil || remaining > 0 {
+		return
+	}
+
+	player, err := ctx.Database.GetPlayerByPlayerID(playerID)
+	if err != nil {
+		LogWarn(fmt.Sprintf("Failed to get dead player %d: %v", playerID, err))
+		return
+	}
+
+	player.Deaths++
+	if constants.GetGlobalConfiguration().MarkDeadPlayers {
+		player.IsDead = true
+	}
+	if err := ctx.Database.UpdatePlayer(player); err != nil {
+		LogWarn(fmt.Sprintf("Failed to record death of player %d: %v", playerID, err))
+		return
+	}
+
+	IncrementCounter(MetricPlayerDeaths, 1)
+	LogInfo(fmt.Sprintf("Player %d died, last circle consumed by entity %d", playerID, consumerEntityID))
+}
+
+// SetWorldSizeArgs represents the arguments for SetWorldSize reducer
+type SetWorldSizeArgs struct {
+	WorldSize uint64 `json:"world_size"`
+	ArenaID   uint32 `json:"arena_id,omitempty"` // Defaults to tables.DefaultArenaID
+	Rescale   bool   `json:"rescale,omitempty"`  // Scale positions with the world instead of clamping
+}
+
+// SetWorldSizeReducer lets an admin grow or shrink the arena while the game is running.
+// Entities left outside the new bounds are clamped back inside, or in rescale mode
+// every position is scaled by the size ratio to preserve the layout.
+func SetWorldSizeReducer(ctx *ReducerContext, args []byte) ReducerResult {
+	timer := NewPerformanceTimer("SetWorldSize")
+	defer timer.Stop()
+
+	if err := RequireAdmin(ctx); err != nil {
+		return ErrorResult{Message: err.Error()}
+	}
+
+	var sizeArgs SetWorldSizeArgs
+	if err := UnmarshalArgs(args, &sizeArgs); err != nil {
+		return ErrorResult{Message: fmt.Sprintf("Invalid arguments: %v", err)}
+	}
+
+	if err := constants.ValidateWorldSize(sizeArgs.WorldSize); err != nil {
+		return ErrorResult{Message: NewReducerError(ErrorCodeInvalidArguments, err.Error(), nil).Error()}
+	}
+
+	config, err := GetArenaConfig(ctx, sizeArgs.ArenaID)
+	if err != nil {
+		return ErrorResult{Message: fmt.Sprintf("Failed to get config: %v", err)}
+	}
+
+	oldWorldSize := config.WorldSize
+	config.WorldSize = sizeArgs.WorldSize
+	if err := ctx.Database.UpdateConfig(config); err != nil {
+		return ErrorResult{Message: fmt.Sprintf("Failed to update config: %v", err)}
+	}
+
+	// Entities all live in the default arena, so other arenas have nothing to reclamp
+	moved := 0
+	if config.ID == tables.DefaultArenaID {
+		if sizeArgs.Rescale {
+			moved, err = RescaleAllEntities(ctx, oldWorldSize, config.WorldSize)
+		} else {
+			moved, err = ReclampAllEntities(ctx, config.WorldSize)
+		}
+		if err != nil {
+			return ErrorResult{Message: fmt.Sprintf("Failed to move entities: %v", err)}
+		}
+	}
+
+	LogInfo(fmt.Sprintf("Arena %d world size changed from %d to %d (%d entities moved)", config.ID, oldWorldSize, config.WorldSize, moved))
+	return SuccessResult{}
+}
+
+// SetConfigReducer lets an admin tune game constants while the game is running.
+// The arguments are a partial JSON Configuration whose fields are merged onto the
+// current global configuration; invalid merges are rejected and change nothing.
+// A new DefaultWorldSize also resizes the default arena, clamping entities inside.
+func SetConfigReducer(ctx *ReducerContext, args []byte) ReducerResult {
+	timer := NewPerformanceTimer("SetConfig")
+	defer timer.Stop()
+
+	if err := RequireAdmin(ctx); err != nil {
+		return ErrorResult{Message: err.Error()}
+	}
+
+	current := constants.GetGlobalConfiguration()
+	merged, err := current.Merge(args)
+	if err != nil {
+		return ErrorResult{Message: NewReducerError(ErrorCodeInvalidArguments, err.Error(), nil).Error()}
+	}
+
+	// Validate and apply the global configuration before touching the database so a
+	// rejected merge leaves the arena as it was
+	if err := constants.SetGlobalConfiguration(merged); err != nil {
+		return ErrorResult{Message: NewReducerError(ErrorCodeInvalidArguments, err.Error(), nil).Error()}
+	}
+
+	if merged.DefaultWorldSize != current.DefaultWorldSize {
+		if err := resizeDefaultArena(ctx, merged.DefaultWorldSize); err != nil {
+			if restoreErr := constants.SetGlobalConfiguration(current); restoreErr != nil {
+				LogError(fmt.Sprintf("Failed to restore configuration: %v", restoreErr))
+			}
+			return ErrorResult{Message: fmt.Sprintf("Failed to resize arena: %v", err)}
+		}
+	}
+
+	LogInfo(fmt.Sprintf("Game configuration updated by %s: %s", ctx.Sender.String(), string(args)))
+	return SuccessResult{}
+}
+
+// resizeDefaultArena writes a new world size to the default arena's config row and
+// clamps every entity inside it
+func resizeDefaultArena(ctx *ReducerContext, worldSize uint64) error {
+	worldConfig, err := GetConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+	worldConfig.WorldSize = worldSize
+	if err := ctx.Database.UpdateConfig(worldConfig); err != nil {
+		return fmt.Errorf("failed to update config: %w", err)
+	}
+	if _, err := ReclampAllEntities(ctx, worldSize); err != nil {
+		return fmt.Errorf("failed to move entities: %w", err)
+	}
+	return nil
+}
+
+// Module state kept outside the database, reported by GetModuleStatus
+var (
+	moduleRuntime   logic.RuntimeState
+	moduleRuntimeMu sync.Mutex
+)
+
+// GetModuleStatus returns a health snapshot of the module at ctx.Timestamp
+func GetModuleStatus(ctx *ReducerContext) (*logic.Status, error) {
+	moduleRuntimeMu.Lock()
+	state := moduleRuntime
+	moduleRuntimeMu.Unlock()
+	state.Paused = idleTick(ctx)
+
+	return logic.ModuleStatus(ctx.Database, state, ctx.Timestamp)
+}
+
+// StatusReducer logs a health snapshot of the module as JSON
+func StatusReducer(ctx *ReducerContext, args []byte) ReducerResult {
+	status, err := GetModuleStatus(ctx)
+	if err != nil {
+		return ErrorResult{Message: fmt.Sprintf("Failed to get module status: %v", err)}
+	}
+
+	statusJSON, err := json.Marshal(status)
+	if err != nil {
+		return ErrorResult{Message: fmt.Sprintf("Failed to marshal module status: %v", err)}
+	}
+	LogInfo(fmt.Sprintf("Module status: %s", string(statusJSON)))
+	return SuccessResult{}
+}
+
+// ExportMetricsReducer refreshes the game gauges and logs all metrics in the
+// Prometheus text format
+func ExportMetricsReducer(ctx *ReducerContext, args []byte) ReducerResult {
+	if err := recordGameGauges(ctx); err != nil {
+		return ErrorResult{Message: fmt.Sprintf("Failed to record game gauges: %v", err)}
+	}
+	LogInfo(fmt.Sprintf("Metrics:\n%s", ExportPrometheus()))
+	return SuccessResult{}
+}
+
+// ReclampAllEntities clamps every entity back inside a world of the given size
+// and returns the number of entities that had to be moved
+func ReclampAllEntities(ctx *ReducerContext, worldSize uint64) (int, error) {
+	entities, err := ctx.Database.GetAllEntities()
+	if err != nil {
+		return 0, err
+	}
+
+	moved := 0
+	for _, entity := range entities {
+		radius := constants.MassToRadius(entity.Mass)
+		clamped := logic.ClampPositionToWorld(entity.Position, radius, worldSize)
+		if clamped.Equal(entity.Position) {
+			continue
+		}
+
+		entity.Position = clamped
+		if err := ctx.Database.UpdateEntity(entity); err != nil {
+			LogWarn(fmt.Sprintf("Failed to reclamp entity %d: %v", entity.EntityID, err))
+			continue
+		}
+		moved++
+	}
+
+	return moved, nil
+}
+
+// RescaleAllEntities scales every entity position from a world of oldWorldSize to one of
+// newWorldSize and returns the number of entities that were moved
+func RescaleAllEntities(ctx *ReducerContext, oldWorldSize, newWorldSize uint64) (int, error) {
+	entities, err := ctx.Database.GetAllEntities()
+	if err != nil {
+		return 0, err
+	}
+
+	moved := 0
+	for _, entity := range logic.MigrateEntitiesToWorld(entities, oldWorldSize, newWorldSize) {
+		if err := ctx.Database.UpdateEntity(entity); err != nil {
+			LogWarn(fmt.Sprintf("Failed to rescale entity %d: %v", entity.EntityID, err))
+			continue
+		}
+		moved++
+	}
+
+	return moved, nil
+}
+
 // Register all Blackholio reducers
 func init() {
+	// Sanitized vector arithmetic is reported through the reducer logger
+	types.SetNonFiniteReporter(LogWarn)
+
 	// Lifecycle reducers
 	RegisterReducer(NewLifecycleReducer("Init", LifecycleInit, InitReducer))
 	RegisterReducer(NewLifecycleReducer("Connect", LifecycleClientConnected, ConnectReducer))
 	RegisterReducer(NewLifecycleReducer("Disconnect", LifecycleClientDisconnected, DisconnectReducer))
 
 	// Game reducers
-	RegisterReducer(NewReducer("EnterGame", EnterGameReducer).WithArgumentNames([]string{"name"}))
+	RegisterReducer(NewReducer("EnterGame", EnterGameReducer).WithArgumentNames([]string{"name"}).WithArgumentType(EnterGameArgs{}))
 	RegisterReducer(NewReducer("Respawn", RespawnReducer))
 	RegisterReducer(NewReducer("Suicide", SuicideReducer))
-	RegisterReducer(NewReducer("UpdatePlayerInput", UpdatePlayerInputReducer).WithArgumentNames([]string{"direction"}))
+	RegisterReducer(NewReducer("UpdatePlayerInput", UpdatePlayerInputReducer).WithArgumentNames([]string{"direction"}).WithArgumentType(UpdatePlayerInputArgs{}))
 	RegisterReducer(NewReducer("PlayerSplit", PlayerSplitReducer))
 
 	// Scheduled reducers
 	RegisterReducer(NewReducer("MoveAllPlayers", MoveAllPlayersReducer))
 	RegisterReducer(NewReducer("SpawnFood", SpawnFoodReducer))
 	RegisterReducer(NewReducer("CircleDecay", CircleDecayReducer))
-	RegisterReducer(NewReducer("CircleRecombine", CircleRecombineReducer).WithArgumentNames([]string{"player_id"}))
-	RegisterReducer(NewReducer("ConsumeEntity", ConsumeEntityReducer).WithArgumentNames([]string{"consumer_entity_id", "consumed_entity_id"}))
+	RegisterReducer(NewReducer("CircleRecombine", CircleRecombineReducer).WithArgumentNames([]string{"player_id"}).WithArgumentType(CircleRecombineArgs{}))
+	RegisterReducer(NewReducer("ConsumeEntity", ConsumeEntityReducer).WithArgumentNames([]string{"consumer_entity_id", "consumed_entity_id"}).WithArgumentType(ConsumeEntityArgs{}))
+	RegisterReducer(NewReducer("CleanupStalePlayers", CleanupStalePlayersReducer))
+
+	// Admin reducers
+	RegisterReducer(NewReducer("SetWorldSize", SetWorldSizeReducer).WithArgumentNames([]string{"world_size"}).WithArgumentType(SetWorldSizeArgs{}))
+	RegisterReducer(NewReducer("SetConfig", SetConfigReducer))
+	RegisterReducer(NewReducer("Status", StatusReducer))
+	RegisterReducer(NewReducer("ExportMetrics", ExportMetricsReducer))
 
 	LogInfo("Blackholio reducers registered successfully")
 }
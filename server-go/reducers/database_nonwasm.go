@@ -4,153 +4,906 @@ package reducers
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 
+	"github.com/clockworklabs/Blackholio/server-go/logic"
 	"github.com/clockworklabs/Blackholio/server-go/tables"
+	"github.com/clockworklabs/Blackholio/server-go/types"
 )
 
-// Non-WASM database operations (mock implementations for testing)
+// Non-WASM database operations (in-memory implementations for testing)
 // These methods are implemented properly in wasm.go for WASM builds
 
+// memoryStore holds the in-memory tables used by non-WASM builds.
+// Rows are copied on the way in and out so callers must write changes back
+// through the Update* methods, matching the behavior of the real database.
+//
+// SpacetimeDB runs WASM reducers one at a time, so the WASM build needs no locking.
+// Test harnesses, however, may run scheduled and client reducers from several
+// goroutines at once, so every access to the maps below is guarded by mu.
+type memoryStore struct {
+	mu sync.RWMutex
+
+	config           map[uint32]*tables.Config
+	entities         map[uint32]*tables.Entity
+	circles          map[uint32]*tables.Circle
+	circlesByPlayer  map[uint32]map[uint32]bool // Player id -> entity ids of the player's circles
+	players          map[tables.Identity]*tables.Player
+	playerNames      map[string]map[tables.Identity]bool // Lowercased name -> active players using it
+	loggedOutPlayers map[tables.Identity]*tables.Player
+	food             map[uint32]*tables.Food
+	scheduled        map[uint64]*scheduledCall
+
+	// entitiesByMass caches the entity rows ordered by mass then id; nil means stale
+	entitiesByMass []*tables.Entity
+
+	// entityGrid caches the entity rows bucketed for NearestEntity; nil means stale
+	entityGrid *logic.SpatialGrid
+
+	nextEntityID    uint32
+	nextPlayerID    uint32
+	nextScheduledID uint64
+}
+
+// scheduledCall is a reducer invocation waiting in the in-memory scheduler
+type scheduledCall struct {
+	ScheduledID uint64
+	Name        string
+	Args        []byte
+	Schedule    tables.ScheduleAt
+	NextRun     tables.Timestamp
+}
+
+// newMemoryStore creates an empty in-memory store
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		config:           make(map[uint32]*tables.Config),
+		entities:         make(map[uint32]*tables.Entity),
+		circles:          make(map[uint32]*tables.Circle),
+		circlesByPlayer:  make(map[uint32]map[uint32]bool),
+		players:          make(map[tables.Identity]*tables.Player),
+		playerNames:      make(map[string]map[tables.Identity]bool),
+		loggedOutPlayers: make(map[tables.Identity]*tables.Player),
+		food:             make(map[uint32]*tables.Food),
+		scheduled:        make(map[uint64]*scheduledCall),
+		nextEntityID:     1,
+		nextPlayerID:     1,
+		nextScheduledID:  1,
+	}
+}
+
+// indexPlayerName records an active player under its case-insensitive name; callers hold mu
+func (s *memoryStore) indexPlayerName(player *tables.Player) {
+	if player.Name == "" {
+		return
+	}
+	key := strings.ToLower(player.Name)
+	if s.playerNames[key] == nil {
+		s.playerNames[key] = make(map[tables.Identity]bool)
+	}
+	s.playerNames[key][player.Identity] = true
+}
+
+// unindexPlayerName removes an active player from the name index; callers hold mu
+func (s *memoryStore) unindexPlayerName(player *tables.Player) {
+	key := strings.ToLower(player.Name)
+	delete(s.playerNames[key], player.Identity)
+	if len(s.playerNames[key]) == 0 {
+		delete(s.playerNames, key)
+	}
+}
+
+// indexCircle records a circle under its owning player; callers hold mu
+func (s *memoryStore) indexCircle(circle *tables.Circle) {
+	if s.circlesByPlayer[circle.PlayerID] == nil {
+		s.circlesByPlayer[circle.PlayerID] = make(map[uint32]bool)
+	}
+	s.circlesByPlayer[circle.PlayerID][circle.EntityID] = true
+}
+
+// unindexCircle removes a circle from the per-player index; callers hold mu
+func (s *memoryStore) unindexCircle(circle *tables.Circle) {
+	delete(s.circlesByPlayer[circle.PlayerID], circle.EntityID)
+	if len(s.circlesByPlayer[circle.PlayerID]) == 0 {
+		delete(s.circlesByPlayer, circle.PlayerID)
+	}
+}
+
+// invalidateEntityIndexes drops the cached entity orderings after an entity write; callers hold mu
+func (s *memoryStore) invalidateEntityIndexes() {
+	s.entitiesByMass = nil
+	s.entityGrid = nil
+}
+
+// mem returns the in-memory store, creating it on first use
+func (db *DatabaseContext) mem() *memoryStore {
+	db.storeOnce.Do(func() {
+		if db.store == nil {
+			db.store = newMemoryStore()
+		}
+	})
+	return db.store
+}
+
 // InsertConfig inserts a config record
 func (db *DatabaseContext) InsertConfig(config *tables.Config) error {
-	// TODO: Implement for non-WASM builds
-	return fmt.Errorf("not implemented for non-WASM builds")
+	store := db.mem()
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if _, exists := store.config[config.ID]; exists {
+		return fmt.Errorf("config %d already exists", config.ID)
+	}
+	row := *config
+	store.config[config.ID] = &row
+	return nil
+}
+
+// UpdateConfig updates a config record
+func (db *DatabaseContext) UpdateConfig(config *tables.Config) error {
+	store := db.mem()
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if _, exists := store.config[config.ID]; !exists {
+		return fmt.Errorf("config %d not found", config.ID)
+	}
+	row := *config
+	store.config[config.ID] = &row
+	return nil
+}
+
+// AdvanceRngCounter returns the default arena's RNG seed together with the counter
+// value for this call, incrementing the stored counter atomically
+func (db *DatabaseContext) AdvanceRngCounter() (seed, counter uint64, err error) {
+	store := db.mem()
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	config, exists := store.config[tables.DefaultArenaID]
+	if !exists {
+		return 0, 0, fmt.Errorf("config %d not found", tables.DefaultArenaID)
+	}
+	counter = config.RngCounter
+	config.RngCounter++
+	return config.RngSeed, counter, nil
 }
 
 // GetLoggedOutPlayer retrieves a logged out player by identity
 func (db *DatabaseContext) GetLoggedOutPlayer(identity tables.Identity) (*tables.Player, error) {
-	// TODO: Implement for non-WASM builds
-	return nil, fmt.Errorf("not implemented for non-WASM builds")
+	store := db.mem()
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	player, exists := store.loggedOutPlayers[identity]
+	if !exists {
+		return nil, fmt.Errorf("logged out player %s not found", identity.String())
+	}
+	row := *player
+	return &row, nil
 }
 
-// InsertPlayer inserts a player record
+// InsertPlayer inserts a player record, assigning a new PlayerID when it is zero
 func (db *DatabaseContext) InsertPlayer(player *tables.Player) error {
-	// TODO: Implement for non-WASM builds
-	return fmt.Errorf("not implemented for non-WASM builds")
+	store := db.mem()
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if _, exists := store.players[player.Identity]; exists {
+		return fmt.Errorf("player %s already exists", player.Identity.String())
+	}
+	if player.PlayerID == 0 {
+		player.PlayerID = store.nextPlayerID
+		store.nextPlayerID++
+	} else if player.PlayerID >= store.nextPlayerID {
+		store.nextPlayerID = player.PlayerID + 1
+	}
+	row := *player
+	store.players[player.Identity] = &row
+	store.indexPlayerName(&row)
+	return nil
 }
 
 // DeleteLoggedOutPlayer deletes a logged out player by identity
 func (db *DatabaseContext) DeleteLoggedOutPlayer(identity tables.Identity) error {
-	// TODO: Implement for non-WASM builds
-	return fmt.Errorf("not implemented for non-WASM builds")
+	store := db.mem()
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if _, exists := store.loggedOutPlayers[identity]; !exists {
+		return fmt.Errorf("logged out player %s not found", identity.String())
+	}
+	delete(store.loggedOutPlayers, identity)
+	return nil
 }
 
 // GetPlayer retrieves a player by identity
 func (db *DatabaseContext) GetPlayer(identity tables.Identity) (*tables.Player, error) {
-	// TODO: Implement for non-WASM builds
-	return nil, fmt.Errorf("not implemented for non-WASM builds")
+	store := db.mem()
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	player, exists := store.players[identity]
+	if !exists {
+		return nil, fmt.Errorf("player %s not found", identity.String())
+	}
+	row := *player
+	return &row, nil
+}
+
+// GetPlayerByPlayerID retrieves a player by their player ID
+func (db *DatabaseContext) GetPlayerByPlayerID(playerID uint32) (*tables.Player, error) {
+	store := db.mem()
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	for _, player := range store.players {
+		if player.PlayerID == playerID {
+			row := *player
+			return &row, nil
+		}
+	}
+	return nil, fmt.Errorf("player %d not found", playerID)
 }
 
 // GetCirclesByPlayer retrieves all circles for a player
 func (db *DatabaseContext) GetCirclesByPlayer(playerID uint32) ([]*tables.Circle, error) {
-	// TODO: Implement for non-WASM builds
-	return nil, fmt.Errorf("not implemented for non-WASM builds")
+	store := db.mem()
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	var circles []*tables.Circle
+	for entityID := range store.circlesByPlayer[playerID] {
+		row := *store.circles[entityID]
+		circles = append(circles, &row)
+	}
+	sort.Slice(circles, func(i, j int) bool { return circles[i].EntityID < circles[j].EntityID })
+	return circles, nil
+}
+
+// GetPlayerEntities retrieves the entity rows backing all of a player's circles,
+// ordered by entity ID. Circles without an entity row are skipped.
+func (db *DatabaseContext) GetPlayerEntities(playerID uint32) ([]*tables.Entity, error) {
+	store := db.mem()
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	var entities []*tables.Entity
+	for entityID := range store.circlesByPlayer[playerID] {
+		if entity, exists := store.entities[entityID]; exists {
+			row := *entity
+			entities = append(entities, &row)
+		}
+	}
+	sort.Slice(entities, func(i, j int) bool { return entities[i].EntityID < entities[j].EntityID })
+	return entities, nil
+}
+
+// GetCircleCountByPlayer counts a player's circles from the per-player index without copying the rows
+func (db *DatabaseContext) GetCircleCountByPlayer(playerID uint32) (uint32, error) {
+	store := db.mem()
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	return uint32(len(store.circlesByPlayer[playerID])), nil
+}
+
+// GetCircleCounts returns the number of circles owned by each player in a single pass
+func (db *DatabaseContext) GetCircleCounts() (map[uint32]uint32, error) {
+	store := db.mem()
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	counts := make(map[uint32]uint32)
+	for _, circle := range store.circles {
+		counts[circle.PlayerID]++
+	}
+	return counts, nil
 }
 
 // UpdatePlayer updates a player record
 func (db *DatabaseContext) UpdatePlayer(player *tables.Player) error {
-	// TODO: Implement for non-WASM builds
-	return fmt.Errorf("not implemented for non-WASM builds")
+	store := db.mem()
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	existing, exists := store.players[player.Identity]
+	if !exists {
+		return fmt.Errorf("player %s not found", player.Identity.String())
+	}
+	store.unindexPlayerName(existing)
+	row := *player
+	store.players[player.Identity] = &row
+	store.indexPlayerName(&row)
+	return nil
 }
 
 // InsertCircle inserts a circle record
 func (db *DatabaseContext) InsertCircle(circle *tables.Circle) error {
-	// TODO: Implement for non-WASM builds
-	return fmt.Errorf("not implemented for non-WASM builds")
+	store := db.mem()
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if _, exists := store.circles[circle.EntityID]; exists {
+		return fmt.Errorf("circle %d already exists", circle.EntityID)
+	}
+	row := *circle
+	store.circles[circle.EntityID] = &row
+	store.indexCircle(&row)
+	return nil
 }
 
 // UpdateCircle updates a circle record
 func (db *DatabaseContext) UpdateCircle(circle *tables.Circle) error {
-	// TODO: Implement for non-WASM builds
-	return fmt.Errorf("not implemented for non-WASM builds")
+	store := db.mem()
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	existing, exists := store.circles[circle.EntityID]
+	if !exists {
+		return fmt.Errorf("circle %d not found", circle.EntityID)
+	}
+	store.unindexCircle(existing)
+	row := *circle
+	store.circles[circle.EntityID] = &row
+	store.indexCircle(&row)
+	return nil
 }
 
 // GetEntity retrieves an entity by ID
 func (db *DatabaseContext) GetEntity(entityID uint32) (*tables.Entity, error) {
-	// TODO: Implement for non-WASM builds
-	return nil, fmt.Errorf("not implemented for non-WASM builds")
+	store := db.mem()
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	entity, exists := store.entities[entityID]
+	if !exists {
+		return nil, fmt.Errorf("entity %d not found", entityID)
+	}
+	row := *entity
+	return &row, nil
+}
+
+// GetLargestEntity returns the entity with the highest mass, preferring the lowest
+// entity id on ties
+func (db *DatabaseContext) GetLargestEntity() (*tables.Entity, error) {
+	store := db.mem()
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	var largest *tables.Entity
+	for _, entity := range store.entities {
+		if largest == nil || entity.Mass > largest.Mass ||
+			(entity.Mass == largest.Mass && entity.EntityID < largest.EntityID) {
+			largest = entity
+		}
+	}
+	if largest == nil {
+		return nil, fmt.Errorf("no entities")
+	}
+	row := *largest
+	return &row, nil
 }
 
 // UpdateEntity updates an entity record
 func (db *DatabaseContext) UpdateEntity(entity *tables.Entity) error {
-	// TODO: Implement for non-WASM builds
-	return fmt.Errorf("not implemented for non-WASM builds")
+	store := db.mem()
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if _, exists := store.entities[entity.EntityID]; !exists {
+		return fmt.Errorf("entity %d not found", entity.EntityID)
+	}
+	row := *entity
+	store.entities[entity.EntityID] = &row
+	store.invalidateEntityIndexes()
+	return nil
 }
 
 // GetAllCircles retrieves all circles
 func (db *DatabaseContext) GetAllCircles() ([]*tables.Circle, error) {
-	// TODO: Implement for non-WASM builds
-	return nil, fmt.Errorf("not implemented for non-WASM builds")
+	store := db.mem()
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	circles := make([]*tables.Circle, 0, len(store.circles))
+	for _, circle := range store.circles {
+		row := *circle
+		circles = append(circles, &row)
+	}
+	sort.Slice(circles, func(i, j int) bool { return circles[i].EntityID < circles[j].EntityID })
+	return circles, nil
 }
 
 // GetAllEntities retrieves all entities
 func (db *DatabaseContext) GetAllEntities() ([]*tables.Entity, error) {
-	// TODO: Implement for non-WASM builds
-	return nil, fmt.Errorf("not implemented for non-WASM builds")
+	store := db.mem()
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	entities := make([]*tables.Entity, 0, len(store.entities))
+	for _, entity := range store.entities {
+		row := *entity
+		entities = append(entities, &row)
+	}
+	sort.Slice(entities, func(i, j int) bool { return entities[i].EntityID < entities[j].EntityID })
+	return entities, nil
+}
+
+// GetEntitiesSortedByMass retrieves all entities ordered by mass, ascending or descending.
+// Entities of equal mass are always ordered by ascending id. The ordering is cached
+// until the next entity write, so repeated calls between writes don't re-sort.
+func (db *DatabaseContext) GetEntitiesSortedByMass(ascending bool) ([]*tables.Entity, error) {
+	store := db.mem()
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if store.entitiesByMass == nil {
+		sorted := make([]*tables.Entity, 0, len(store.entities))
+		for _, entity := range store.entities {
+			sorted = append(sorted, entity)
+		}
+		sort.Slice(sorted, func(i, j int) bool {
+			if sorted[i].Mass != sorted[j].Mass {
+				return sorted[i].Mass < sorted[j].Mass
+			}
+			return sorted[i].EntityID < sorted[j].EntityID
+		})
+		store.entitiesByMass = sorted
+	}
+
+	entities := make([]*tables.Entity, 0, len(store.entitiesByMass))
+	appendRow := func(entity *tables.Entity) {
+		row := *entity
+		entities = append(entities, &row)
+	}
+	if ascending {
+		for _, entity := range store.entitiesByMass {
+			appendRow(entity)
+		}
+		return entities, nil
+	}
+
+	// Walk runs of equal mass from heaviest to lightest, keeping each run in id order
+	for end := len(store.entitiesByMass); end > 0; {
+		start := end - 1
+		for start > 0 && store.entitiesByMass[start-1].Mass == store.entitiesByMass[end-1].Mass {
+			start--
+		}
+		for _, entity := range store.entitiesByMass[start:end] {
+			appendRow(entity)
+		}
+		end = start
+	}
+	return entities, nil
+}
+
+// entityGridCellSize is the cell size of the grid NearestEntity searches
+const entityGridCellSize float32 = 50
+
+// NearestEntity finds the entity whose center is closest to point among those passing filter,
+// returning a copy of it and the distance. Equally close entities go to the lowest id.
+// A nil filter accepts every entity. The entities are bucketed into a spatial grid that is
+// reused until the next entity write. filter runs with the store locked, so it must not
+// call back into the database.
+func (db *DatabaseContext) NearestEntity(point types.DbVector2, filter func(*tables.Entity) bool) (*tables.Entity, float32, error) {
+	store := db.mem()
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if store.entityGrid == nil {
+		store.entityGrid = logic.NewSpatialGrid(entityGridCellSize)
+		for _, entity := range store.entities {
+			store.entityGrid.AddEntity(entity)
+		}
+	}
+
+	nearest, distance, found := store.entityGrid.NearestEntity(point, func(entity *tables.Entity) bool {
+		if filter == nil {
+			return true
+		}
+		row := *entity
+		return filter(&row)
+	})
+	if !found {
+		return nil, 0, fmt.Errorf("no matching entity")
+	}
+	row := *nearest
+	return &row, distance, nil
+}
+
+// GetAllFood retrieves all food rows
+func (db *DatabaseContext) GetAllFood() ([]*tables.Food, error) {
+	store := db.mem()
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	food := make([]*tables.Food, 0, len(store.food))
+	for _, row := range store.food {
+		copied := *row
+		food = append(food, &copied)
+	}
+	sort.Slice(food, func(i, j int) bool { return food[i].EntityID < food[j].EntityID })
+	return food, nil
 }
 
 // GetAllPlayers retrieves all players
 func (db *DatabaseContext) GetAllPlayers() ([]*tables.Player, error) {
-	// TODO: Implement for non-WASM builds
-	return nil, fmt.Errorf("not implemented for non-WASM builds")
+	store := db.mem()
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	players := make([]*tables.Player, 0, len(store.players))
+	for _, player := range store.players {
+		row := *player
+		players = append(players, &row)
+	}
+	sort.Slice(players, func(i, j int) bool { return players[i].PlayerID < players[j].PlayerID })
+	return players, nil
 }
 
 // GetCircle retrieves a circle by entity ID
 func (db *DatabaseContext) GetCircle(entityID uint32) (*tables.Circle, error) {
-	// TODO: Implement for non-WASM builds
-	return nil, fmt.Errorf("not implemented for non-WASM builds")
+	store := db.mem()
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	circle, exists := store.circles[entityID]
+	if !exists {
+		return nil, fmt.Errorf("circle %d not found", entityID)
+	}
+	row := *circle
+	return &row, nil
+}
+
+// GetPlayerIDForEntity returns the player owning a circle entity.
+// The second result is false for food and unknown entities.
+func (db *DatabaseContext) GetPlayerIDForEntity(entityID uint32) (uint32, bool) {
+	store := db.mem()
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	circle, exists := store.circles[entityID]
+	if !exists {
+		return 0, false
+	}
+	return circle.PlayerID, true
 }
 
 // GetPlayerCount retrieves the count of active players
 func (db *DatabaseContext) GetPlayerCount() (uint64, error) {
-	// TODO: Implement for non-WASM builds
-	return 0, fmt.Errorf("not implemented for non-WASM builds")
+	store := db.mem()
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	return uint64(len(store.players)), nil
+}
+
+// GetEntityCount retrieves the count of entities
+func (db *DatabaseContext) GetEntityCount() (uint64, error) {
+	store := db.mem()
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	return uint64(len(store.entities)), nil
 }
 
 // GetFoodCount retrieves the count of food entities
 func (db *DatabaseContext) GetFoodCount() (uint64, error) {
-	// TODO: Implement for non-WASM builds
-	return 0, fmt.Errorf("not implemented for non-WASM builds")
+	store := db.mem()
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	return uint64(len(store.food)), nil
 }
 
 // InsertFood inserts a food record
 func (db *DatabaseContext) InsertFood(food *tables.Food) error {
-	// TODO: Implement for non-WASM builds
-	return fmt.Errorf("not implemented for non-WASM builds")
+	store := db.mem()
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if _, exists := store.food[food.EntityID]; exists {
+		return fmt.Errorf("food %d already exists", food.EntityID)
+	}
+	row := *food
+	store.food[food.EntityID] = &row
+	return nil
+}
+
+// IsFood returns true if the entity has a row in the food table
+func (db *DatabaseContext) IsFood(entityID uint32) (bool, error) {
+	store := db.mem()
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	_, exists := store.food[entityID]
+	return exists, nil
 }
 
 // InsertLoggedOutPlayer inserts a logged out player record
 func (db *DatabaseContext) InsertLoggedOutPlayer(player *tables.Player) error {
-	// TODO: Implement for non-WASM builds
-	return fmt.Errorf("not implemented for non-WASM builds")
+	store := db.mem()
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if _, exists := store.loggedOutPlayers[player.Identity]; exists {
+		return fmt.Errorf("logged out player %s already exists", player.Identity.String())
+	}
+	row := *player
+	store.loggedOutPlayers[player.Identity] = &row
+	return nil
 }
 
 // DeletePlayer deletes a player by identity
 func (db *DatabaseContext) DeletePlayer(identity tables.Identity) error {
-	// TODO: Implement for non-WASM builds
-	return fmt.Errorf("not implemented for non-WASM builds")
+	store := db.mem()
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	existing, exists := store.players[identity]
+	if !exists {
+		return fmt.Errorf("player %s not found", identity.String())
+	}
+	store.unindexPlayerName(existing)
+	delete(store.players, identity)
+	return nil
 }
 
-// ScheduleReducer schedules a reducer for future execution
+// IsPlayerNameTaken reports whether an active player other than except uses name,
+// ignoring case
+func (db *DatabaseContext) IsPlayerNameTaken(name string, except tables.Identity) (bool, error) {
+	store := db.mem()
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	for identity := range store.playerNames[strings.ToLower(name)] {
+		if identity != except {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ScheduleReducer schedules a reducer for future execution.
+// One-shot schedules run at their time; interval schedules first run one interval
+// after being scheduled and then re-arm. Calls only run when RunDueTimers is invoked.
 func (db *DatabaseContext) ScheduleReducer(name string, args []byte, schedule tables.ScheduleAt) error {
-	// TODO: Implement for non-WASM builds
-	return fmt.Errorf("not implemented for non-WASM builds")
+	_, err := db.ScheduleReducerWithID(name, args, schedule)
+	return err
+}
+
+// ScheduleReducerWithID schedules a reducer and returns the scheduled ID used to cancel it
+func (db *DatabaseContext) ScheduleReducerWithID(name string, args []byte, schedule tables.ScheduleAt) (uint64, error) {
+	var nextRun tables.Timestamp
+	switch {
+	case schedule.IsTime():
+		nextRun = *schedule.GetTime()
+	case schedule.IsInterval():
+		nextRun = Now().Add(*schedule.GetInterval())
+	default:
+		return 0, fmt.Errorf("schedule for %s has neither a time nor an interval", name)
+	}
+
+	store := db.mem()
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	call := &scheduledCall{
+		ScheduledID: store.nextScheduledID,
+		Name:        name,
+		Args:        append([]byte(nil), args...),
+		Schedule:    schedule,
+		NextRun:     nextRun,
+	}
+	store.scheduled[call.ScheduledID] = call
+	store.nextScheduledID++
+	return call.ScheduledID, nil
+}
+
+// CancelScheduledReducer removes a scheduled call so it never fires.
+// Cancelling an ID that doesn't exist, or has already fired, is not an error.
+func (db *DatabaseContext) CancelScheduledReducer(scheduledID uint64) error {
+	store := db.mem()
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	delete(store.scheduled, scheduledID)
+	return nil
+}
+
+// CountScheduledReducers returns how many pending scheduled calls target the named reducer
+func (db *DatabaseContext) CountScheduledReducers(name string) (int, error) {
+	store := db.mem()
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	count := 0
+	for _, call := range store.scheduled {
+		if call.Name == name {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetScheduledTimers lists every pending scheduled call ordered by scheduled ID.
+// Interval timers report the time of their next run along with their interval.
+func (db *DatabaseContext) GetScheduledTimers() ([]ScheduledTimerInfo, error) {
+	store := db.mem()
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	timers := make([]ScheduledTimerInfo, 0, len(store.scheduled))
+	for _, call := range store.scheduled {
+		timers = append(timers, ScheduledTimerInfo{
+			ScheduledID: call.ScheduledID,
+			Name:        call.Name,
+			Schedule:    call.Schedule,
+			NextRun:     call.NextRun,
+		})
+	}
+	sort.Slice(timers, func(i, j int) bool { return timers[i].ScheduledID < timers[j].ScheduledID })
+	return timers, nil
+}
+
+// RunDueTimers invokes every scheduled reducer whose time has come, in schedule order.
+// One-shot calls are removed once they fire and interval calls re-arm one interval after now.
+// Calls scheduled by the fired reducers run on a later RunDueTimers. Returns the number of reducers invoked.
+func (db *DatabaseContext) RunDueTimers(now tables.Timestamp) int {
+	store := db.mem()
+	store.mu.Lock()
+	var due []scheduledCall
+	for id, call := range store.scheduled {
+		if call.NextRun.Microseconds > now.Microseconds {
+			continue
+		}
+		due = append(due, *call)
+		if call.Schedule.IsInterval() {
+			call.NextRun = now.Add(*call.Schedule.GetInterval())
+		} else {
+			delete(store.scheduled, id)
+		}
+	}
+	store.mu.Unlock()
+
+	sort.Slice(due, func(i, j int) bool {
+		if due[i].NextRun.Microseconds != due[j].NextRun.Microseconds {
+			return due[i].NextRun.Microseconds < due[j].NextRun.Microseconds
+		}
+		return due[i].ScheduledID < due[j].ScheduledID
+	})
+
+	// Reducers run outside the store lock since they access the database themselves
+	fired := 0
+	for _, call := range due {
+		reducer, exists := globalRegistry.GetByName(call.Name)
+		if !exists {
+			LogWarn(fmt.Sprintf("Scheduled reducer %s is not registered", call.Name))
+			continue
+		}
+
+		ctx := &ReducerContext{
+			Timestamp: now,
+			Database:  db,
+		}
+		if result := reducer.Invoke(ctx, call.Args); !result.IsSuccess() {
+			LogWarn(fmt.Sprintf("Scheduled reducer %s failed: %s", call.Name, result.Error()))
+		}
+		fired++
+	}
+
+	return fired
 }
 
-// InsertEntity inserts an entity record
+// InsertEntity inserts an entity record, assigning a new EntityID when it is zero
 func (db *DatabaseContext) InsertEntity(entity *tables.Entity) error {
-	// TODO: Implement for non-WASM builds
-	return fmt.Errorf("not implemented for non-WASM builds")
+	store := db.mem()
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if entity.EntityID == 0 {
+		entity.EntityID = store.nextEntityID
+		store.nextEntityID++
+	} else if entity.EntityID >= store.nextEntityID {
+		store.nextEntityID = entity.EntityID + 1
+	}
+	if _, exists := store.entities[entity.EntityID]; exists {
+		return fmt.Errorf("entity %d already exists", entity.EntityID)
+	}
+	row := *entity
+	store.entities[entity.EntityID] = &row
+	store.invalidateEntityIndexes()
+	return nil
 }
 
 // DeleteEntity deletes an entity by ID
 func (db *DatabaseContext) DeleteEntity(entityID uint32) error {
-	// TODO: Implement for non-WASM builds
-	return fmt.Errorf("not implemented for non-WASM builds")
+	store := db.mem()
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if _, exists := store.entities[entityID]; !exists {
+		return fmt.Errorf("entity %d not found", entityID)
+	}
+	delete(store.entities, entityID)
+	store.invalidateEntityIndexes()
+	return nil
 }
 
-// GetConfig retrieves the game configuration from the database
+// DestroyEntity removes an entity together with its food and circle rows under a single lock,
+// following the deletion order from logic.DestroyEntityIDs
+func (db *DatabaseContext) DestroyEntity(entityID uint32) error {
+	store := db.mem()
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if _, exists := store.entities[entityID]; !exists {
+		return fmt.Errorf("entity %d not found", entityID)
+	}
+	deleteEntityRows(store, entityID)
+	store.invalidateEntityIndexes()
+	return nil
+}
+
+// DestroyEntities removes many entities with their food and circle rows under a single
+// lock. Every existing id is removed even when some are missing; the missing ids are
+// reported in the returned error.
+func (db *DatabaseContext) DestroyEntities(entityIDs []uint32) error {
+	store := db.mem()
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	var missing []uint32
+	for _, entityID := range entityIDs {
+		if _, exists := store.entities[entityID]; !exists {
+			missing = append(missing, entityID)
+			continue
+		}
+		deleteEntityRows(store, entityID)
+	}
+	store.invalidateEntityIndexes()
+	if len(missing) > 0 {
+		return fmt.Errorf("entities %v not found", missing)
+	}
+	return nil
+}
+
+// deleteEntityRows removes an entity's food, circle and entity rows in the order given by
+// logic.DestroyEntityIDs. Callers hold mu and invalidate the entity indexes afterwards.
+func deleteEntityRows(store *memoryStore, entityID uint32) {
+	for _, deletion := range logic.DestroyEntityIDs(entityID) {
+		switch deletion.Type {
+		case "food":
+			delete(store.food, deletion.EntityID)
+		case "circle":
+			if circle, exists := store.circles[deletion.EntityID]; exists {
+				store.unindexCircle(circle)
+				delete(store.circles, deletion.EntityID)
+			}
+		case "entity":
+			delete(store.entities, deletion.EntityID)
+		}
+	}
+}
+
+// GetConfig retrieves the default arena's configuration from the database
 func (db *DatabaseContext) GetConfig() (*tables.Config, error) {
-	// TODO: Implement for non-WASM builds
-	return nil, fmt.Errorf("not implemented for non-WASM builds")
+	return db.GetConfigByID(tables.DefaultArenaID)
+}
+
+// GetConfigByID retrieves the configuration of the arena with the given id
+func (db *DatabaseContext) GetConfigByID(id uint32) (*tables.Config, error) {
+	store := db.mem()
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	config, exists := store.config[id]
+	if !exists {
+		return nil, fmt.Errorf("config %d not found", id)
+	}
+	row := *config
+	return &row, nil
 }
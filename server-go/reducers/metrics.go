@@ -0,0 +1,264 @@
+package reducers
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Game metrics
+// Counters recorded by the game reducers for monitoring and tests
+
+const (
+	// MetricCollisionChecks counts entity pairs examined by the collision pass
+	MetricCollisionChecks = "collision_checks"
+
+	// MetricCollisionBudgetOverflows counts ticks where the collision pass hit MaxCollisionChecksPerTick
+	MetricCollisionBudgetOverflows = "collision_budget_overflows"
+
+	// MetricForcedRecombines counts circle pairs force-merged after MaxRecombineAttempts
+	MetricForcedRecombines = "forced_recombines"
+
+	// MetricPhysicsSubsteps counts fixed-timestep physics substeps run by MoveAllPlayers
+	MetricPhysicsSubsteps = "physics_substeps"
+
+	// MetricPlayerDeaths counts players whose last circle was consumed
+	MetricPlayerDeaths = "player_deaths"
+
+	// MetricRejectedInputs counts player inputs rejected for exceeding MaxInputClockDrift
+	MetricRejectedInputs = "rejected_inputs"
+
+	// MetricIgnoredInputs counts player inputs dropped because the player had no circles
+	MetricIgnoredInputs = "ignored_inputs"
+)
+
+// Game gauges, refreshed by recordGameGauges
+const (
+	// MetricEntities is the number of entities, food included
+	MetricEntities = "entities"
+
+	// MetricPlayers is the number of connected players
+	MetricPlayers = "players"
+
+	// MetricFood is the number of food entities
+	MetricFood = "food"
+
+	// MetricTotalMass is the summed mass of all entities
+	MetricTotalMass = "total_mass"
+)
+
+// reducerDurationSamples is how many recent durations per reducer the quantiles are taken over
+const reducerDurationSamples = 1024
+
+// reducerDurationQuantiles are the quantiles exported for each reducer's durations
+var reducerDurationQuantiles = []float64{0.5, 0.9, 0.99}
+
+// reducerStats tracks invocations of a single reducer
+type reducerStats struct {
+	calls   uint64
+	total   time.Duration
+	samples []time.Duration // ring buffer of the most recent durations
+	next    int
+}
+
+var (
+	metricsMu      sync.Mutex
+	counters       = make(map[string]uint64)
+	gauges         = make(map[string]float64)
+	reducerMetrics = make(map[string]*reducerStats)
+)
+
+// IncrementCounter adds delta to the named counter
+func IncrementCounter(name string, delta uint64) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	counters[name] += delta
+}
+
+// GetCounter returns the current value of the named counter
+func GetCounter(name string) uint64 {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	return counters[name]
+}
+
+// GetCounterNames returns the names of all recorded counters in sorted order
+func GetCounterNames() []string {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	names := make([]string, 0, len(counters))
+	for name := range counters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetGauge sets the named gauge to value
+func SetGauge(name string, value float64) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	gauges[name] = value
+}
+
+// GetGauge returns the current value of the named gauge
+func GetGauge(name string) float64 {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	return gauges[name]
+}
+
+// RecordReducerCall counts one invocation of the named reducer that took duration
+func RecordReducerCall(name string, duration time.Duration) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	stats := reducerMetrics[name]
+	if stats == nil {
+		stats = &reducerStats{}
+		reducerMetrics[name] = stats
+	}
+	stats.calls++
+	stats.total += duration
+	if len(stats.samples) < reducerDurationSamples {
+		stats.samples = append(stats.samples, duration)
+	} else {
+		stats.samples[stats.next] = duration
+		stats.next = (stats.next + 1) % reducerDurationSamples
+	}
+}
+
+// GetReducerCalls returns how many times the named reducer has been invoked
+func GetReducerCalls(name string) uint64 {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	if stats := reducerMetrics[name]; stats != nil {
+		return stats.calls
+	}
+	return 0
+}
+
+// ResetMetrics clears all counters, gauges and reducer statistics
+func ResetMetrics() {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	counters = make(map[string]uint64)
+	gauges = make(map[string]float64)
+	reducerMetrics = make(map[string]*reducerStats)
+}
+
+// recordGameGauges refreshes the game gauges from the database
+func recordGameGauges(ctx *ReducerContext) error {
+	players, err := ctx.Database.GetPlayerCount()
+	if err != nil {
+		return fmt.Errorf("failed to count players: %w", err)
+	}
+	food, err := ctx.Database.GetFoodCount()
+	if err != nil {
+		return fmt.Errorf("failed to count food: %w", err)
+	}
+	entities, err := ctx.Database.GetAllEntities()
+	if err != nil {
+		return fmt.Errorf("failed to get entities: %w", err)
+	}
+	totalMass := uint64(0)
+	for _, entity := range entities {
+		totalMass += uint64(entity.Mass)
+	}
+
+	SetGauge(MetricPlayers, float64(players))
+	SetGauge(MetricFood, float64(food))
+	SetGauge(MetricEntities, float64(len(entities)))
+	SetGauge(MetricTotalMass, float64(totalMass))
+	return nil
+}
+
+// Prometheus export
+
+// prometheusPrefix namespaces every exported metric
+const prometheusPrefix = "blackholio_"
+
+var prometheusLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// ExportPrometheus formats the collected metrics in the Prometheus text exposition format:
+// per-reducer call counts and duration quantiles over the last reducerDurationSamples
+// calls, the game counters, and the game gauges as of their last refresh.
+func ExportPrometheus() []byte {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	var buf bytes.Buffer
+	names := make([]string, 0, len(reducerMetrics))
+	for name := range reducerMetrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if len(names) > 0 {
+		writePrometheusHeader(&buf, "reducer_calls_total", "counter", "Reducer invocations.")
+		for _, name := range names {
+			fmt.Fprintf(&buf, "%sreducer_calls_total{reducer=\"%s\"} %d\n", prometheusPrefix, prometheusLabelEscaper.Replace(name), reducerMetrics[name].calls)
+		}
+
+		writePrometheusHeader(&buf, "reducer_duration_seconds", "summary", "Reducer execution time in seconds.")
+		for _, name := range names {
+			stats := reducerMetrics[name]
+			label := prometheusLabelEscaper.Replace(name)
+			sorted := append([]time.Duration(nil), stats.samples...)
+			sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+			for _, q := range reducerDurationQuantiles {
+				fmt.Fprintf(&buf, "%sreducer_duration_seconds{reducer=\"%s\",quantile=\"%s\"} %s\n",
+					prometheusPrefix, label, formatPrometheusFloat(q), formatPrometheusFloat(durationQuantile(sorted, q).Seconds()))
+			}
+			fmt.Fprintf(&buf, "%sreducer_duration_seconds_sum{reducer=\"%s\"} %s\n", prometheusPrefix, label, formatPrometheusFloat(stats.total.Seconds()))
+			fmt.Fprintf(&buf, "%sreducer_duration_seconds_count{reducer=\"%s\"} %d\n", prometheusPrefix, label, stats.calls)
+		}
+	}
+
+	counterNames := make([]string, 0, len(counters))
+	for name := range counters {
+		counterNames = append(counterNames, name)
+	}
+	sort.Strings(counterNames)
+	for _, name := range counterNames {
+		writePrometheusHeader(&buf, name+"_total", "counter", "Game counter "+name+".")
+		fmt.Fprintf(&buf, "%s%s_total %d\n", prometheusPrefix, name, counters[name])
+	}
+
+	for _, name := range []string{MetricEntities, MetricPlayers, MetricFood, MetricTotalMass} {
+		writePrometheusHeader(&buf, name, "gauge", "Game gauge "+name+".")
+		fmt.Fprintf(&buf, "%s%s %s\n", prometheusPrefix, name, formatPrometheusFloat(gauges[name]))
+	}
+	return buf.Bytes()
+}
+
+// writePrometheusHeader writes the HELP and TYPE lines introducing a metric
+func writePrometheusHeader(buf *bytes.Buffer, name, metricType, help string) {
+	fmt.Fprintf(buf, "# HELP %s%s %s\n", prometheusPrefix, name, help)
+	fmt.Fprintf(buf, "# TYPE %s%s %s\n", prometheusPrefix, name, metricType)
+}
+
+// durationQuantile returns the nearest-rank q quantile of sorted, or 0 if it is empty
+func durationQuantile(sorted []time.Duration, q float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(q*float64(len(sorted)))) - 1
+	return sorted[max(0, min(rank, len(sorted)-1))]
+}
+
+func formatPrometheusFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
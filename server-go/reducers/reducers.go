@@ -7,9 +7,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/rand"
+	"reflect"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/clockworklabs/Blackholio/server-go/constants"
+	"github.com/clockworklabs/Blackholio/server-go/logic"
 	"github.com/clockworklabs/Blackholio/server-go/tables"
 )
 
@@ -40,20 +44,38 @@ type ReducerContext struct {
 type DatabaseContext struct {
 	// Internal database handle - will be populated by WASM host calls
 	handle uintptr
+
+	// In-memory tables backing non-WASM builds
+	store     *memoryStore
+	storeOnce sync.Once
 }
 
 // Database operation methods are implemented in:
-// - database_nonwasm.go for non-WASM builds (mock implementations)
+// - database_nonwasm.go for non-WASM builds (in-memory implementations)
 // - wasm.go for WASM builds (real SpacetimeDB integration)
 
+// ScheduledTimerInfo describes a pending scheduled reducer call, for debugging the scheduler
+type ScheduledTimerInfo struct {
+	ScheduledID uint64            `json:"scheduled_id"`
+	Name        string            `json:"name"`
+	Schedule    tables.ScheduleAt `json:"schedule"`
+	NextRun     tables.Timestamp  `json:"next_run"`
+}
+
 // Rng returns a random number generator seeded for this reducer execution
 func (ctx *ReducerContext) Rng() *rand.Rand {
 	ctx.rngMu.Lock()
 	defer ctx.rngMu.Unlock()
 
 	if ctx.rng == nil {
-		// Use timestamp as seed for deterministic behavior
+		// Derive from the world's stored seed so a game can be replayed from Init,
+		// falling back to the timestamp for worlds without one
 		seed := int64(ctx.Timestamp.Microseconds)
+		if ctx.Database != nil {
+			if worldSeed, counter, err := ctx.Database.AdvanceRngCounter(); err == nil && worldSeed != 0 {
+				seed = logic.DeriveSeed(worldSeed, counter)
+			}
+		}
 		ctx.rng = rand.New(rand.NewSource(seed))
 	}
 	return ctx.rng
@@ -194,10 +216,11 @@ func (r *ReducerRegistry) ListReducers() map[string]ReducerFunction {
 
 // GenericReducer is a concrete implementation of ReducerFunction
 type GenericReducer struct {
-	name          string
-	lifecycle     *LifecycleType
-	argumentNames []string
-	handler       func(*ReducerContext, []byte) ReducerResult
+	name           string
+	lifecycle      *LifecycleType
+	argumentNames  []string
+	argumentSchema []ArgumentField
+	handler        func(*ReducerContext, []byte) ReducerResult
 }
 
 // NewReducer creates a new generic reducer
@@ -229,9 +252,16 @@ func (r *GenericReducer) Lifecycle() *LifecycleType {
 	return r.lifecycle
 }
 
-// Invoke calls the reducer
+// Invoke validates the arguments against the reducer's argument schema (if any) and calls the reducer
 func (r *GenericReducer) Invoke(ctx *ReducerContext, args []byte) ReducerResult {
-	return r.handler(ctx, args)
+	if err := r.ValidateArgs(args); err != nil {
+		details := map[string]interface{}{"reducer": r.name}
+		return ErrorResult{Message: NewReducerError(ErrorCodeInvalidArguments, err.Error(), details).Error()}
+	}
+	start := clock.Now()
+	result := r.handler(ctx, args)
+	RecordReducerCall(r.name, clock.Now().Sub(start))
+	return result
 }
 
 // ArgumentNames returns the argument names
@@ -245,6 +275,137 @@ func (r *GenericReducer) WithArgumentNames(names []string) *GenericReducer {
 	return r
 }
 
+// WithArgumentType derives the reducer's argument schema from its argument struct,
+// so malformed calls are rejected before the handler runs
+func (r *GenericReducer) WithArgumentType(argType interface{}) *GenericReducer {
+	r.argumentSchema = ArgumentSchemaFor(argType)
+	return r
+}
+
+// ArgumentSchema returns the fields required in the reducer's JSON arguments
+func (r *GenericReducer) ArgumentSchema() []ArgumentField {
+	return r.argumentSchema
+}
+
+// ValidateArgs checks that every schema field is present with the expected JSON type.
+// Reducers without a schema accept any arguments.
+func (r *GenericReducer) ValidateArgs(args []byte) error {
+	if len(r.argumentSchema) == 0 {
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(args, &fields); err != nil || fields == nil {
+		return fmt.Errorf("arguments must be a JSON object")
+	}
+
+	for _, field := range r.argumentSchema {
+		raw, exists := fields[field.Name]
+		if !exists {
+			return fmt.Errorf("missing required field %q", field.Name)
+		}
+		if kind := jsonKind(raw); field.Kind != "" && kind != field.Kind {
+			return fmt.Errorf("field %q must be a %s, got %s", field.Name, field.Kind, kind)
+		}
+	}
+	return nil
+}
+
+// Argument schemas
+
+// ArgumentField describes a required field in a reducer's JSON arguments
+type ArgumentField struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"` // "string", "number", "boolean", "object", "array", or "" for any
+}
+
+// ArgumentSchemaFor derives the required fields of an argument struct from its json tags.
+// Fields tagged omitempty are optional and types with custom JSON decoding accept any kind.
+func ArgumentSchemaFor(argType interface{}) []ArgumentField {
+	t := reflect.TypeOf(argType)
+	if t == nil {
+		return nil
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var schema []ArgumentField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		if tag := field.Tag.Get("json"); tag != "" {
+			parts := strings.Split(tag, ",")
+			if parts[0] == "-" {
+				continue
+			}
+			if parts[0] != "" {
+				name = parts[0]
+			}
+			if len(parts) > 1 && parts[1] == "omitempty" {
+				continue
+			}
+		}
+
+		schema = append(schema, ArgumentField{Name: name, Kind: jsonKindForType(field.Type)})
+	}
+	return schema
+}
+
+// jsonKindForType returns the JSON kind a Go type is decoded from
+func jsonKindForType(t reflect.Type) string {
+	if reflect.PointerTo(t).Implements(reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()) {
+		return ""
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	default:
+		return ""
+	}
+}
+
+// jsonKind returns the JSON kind of a raw value based on its first byte
+func jsonKind(raw json.RawMessage) string {
+	trimmed := strings.TrimSpace(string(raw))
+	if trimmed == "" {
+		return ""
+	}
+
+	switch trimmed[0] {
+	case '"':
+		return "string"
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case 't', 'f':
+		return "boolean"
+	case 'n':
+		return "null"
+	default:
+		return "number"
+	}
+}
+
 // Serialization utilities for reducer arguments
 
 // MarshalArgs marshals reducer arguments to JSON bytes
@@ -280,19 +441,61 @@ func HandleResult(result interface{}) ReducerResult {
 
 // Logging utilities for reducers
 
+// Logger is the backend LogInfo, LogWarn and LogError write to
+type Logger interface {
+	Info(message string)
+	Warn(message string)
+	Error(message string)
+}
+
+// stdoutLogger prints log messages to standard output
+type stdoutLogger struct{}
+
+func (stdoutLogger) Info(message string)  { fmt.Printf("[INFO] %s\n", message) }
+func (stdoutLogger) Warn(message string)  { fmt.Printf("[WARN] %s\n", message) }
+func (stdoutLogger) Error(message string) { fmt.Printf("[ERROR] %s\n", message) }
+
+// Console log levels understood by the SpacetimeDB host
+const (
+	hostLogLevelError uint8 = 0
+	hostLogLevelWarn  uint8 = 1
+	hostLogLevelInfo  uint8 = 2
+)
+
+// hostLogger forwards log messages to the host console through write,
+// which WASM builds bind to the host's console_log import
+type hostLogger struct {
+	write func(level uint8, message string)
+}
+
+func (l hostLogger) Info(message string)  { l.write(hostLogLevelInfo, message) }
+func (l hostLogger) Warn(message string)  { l.write(hostLogLevelWarn, message) }
+func (l hostLogger) Error(message string) { l.write(hostLogLevelError, message) }
+
+var logger Logger = stdoutLogger{}
+
+// SetLogger replaces the logging backend; nil restores the stdout logger.
+// It should be called during initialization, before any reducer runs.
+func SetLogger(l Logger) {
+	if l == nil {
+		l = stdoutLogger{}
+	}
+	logger = l
+}
+
 // LogInfo logs an info message from a reducer
 func LogInfo(message string) {
-	fmt.Printf("[INFO] %s\n", message)
+	logger.Info(message)
 }
 
 // LogWarn logs a warning message from a reducer
 func LogWarn(message string) {
-	fmt.Printf("[WARN] %s\n", message)
+	logger.Warn(message)
 }
 
 // LogError logs an error message from a reducer
 func LogError(message string) {
-	fmt.Printf("[ERROR] %s\n", message)
+	logger.Error(message)
 }
 
 // Utility functions for common reducer patterns
@@ -308,14 +511,32 @@ func RequirePlayer(ctx *ReducerContext) (*tables.Player, error) {
 	}, nil
 }
 
-// GetConfig retrieves the game configuration
+// GetConfig retrieves the game configuration of the default arena
 func GetConfig(ctx *ReducerContext) (*tables.Config, error) {
-	// TODO: Implement database query when database context is available
-	// For now, return a mock implementation
-	return &tables.Config{
-		ID:        0,
-		WorldSize: 1000,
-	}, nil
+	return ctx.Database.GetConfig()
+}
+
+// GetArenaConfig retrieves the game configuration of the given arena
+func GetArenaConfig(ctx *ReducerContext, arenaID uint32) (*tables.Config, error) {
+	return ctx.Database.GetConfigByID(arenaID)
+}
+
+// IsAdmin returns true if the identity is the admin recorded in the default arena's
+// config row and so may call admin-gated reducers
+func IsAdmin(ctx *ReducerContext, identity tables.Identity) bool {
+	config, err := GetConfig(ctx)
+	if err != nil {
+		return false
+	}
+	return !config.AdminIdentity.IsZero() && config.AdminIdentity == identity
+}
+
+// RequireAdmin ensures that the caller is the recorded admin
+func RequireAdmin(ctx *ReducerContext) error {
+	if !IsAdmin(ctx, ctx.Sender) {
+		return NewReducerError(ErrorCodeUnauthorized, fmt.Sprintf("%s is not an admin", ctx.Sender.String()), nil)
+	}
+	return nil
 }
 
 // ScheduleTimer schedules a timer for future execution
@@ -325,6 +546,32 @@ func ScheduleTimer(ctx *ReducerContext, scheduledAt tables.ScheduleAt) error {
 	return nil
 }
 
+// Clock supplies the wall-clock time reducers read outside of ReducerContext.Timestamp
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock reads the real time
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+var clock Clock = systemClock{}
+
+// SetClock replaces the clock reducers read; nil restores the system clock.
+// Tests install a fake clock to advance time explicitly.
+func SetClock(c Clock) {
+	if c == nil {
+		c = systemClock{}
+	}
+	clock = c
+}
+
+// Now returns the current time of the installed clock as a Timestamp
+func Now() tables.Timestamp {
+	return tables.NewTimestampFromTime(clock.Now())
+}
+
 // Performance monitoring for reducers
 
 // PerformanceTimer tracks reducer execution time
@@ -337,14 +584,22 @@ type PerformanceTimer struct {
 func NewPerformanceTimer(name string) *PerformanceTimer {
 	return &PerformanceTimer{
 		Name:      name,
-		StartTime: time.Now(),
+		StartTime: clock.Now(),
 	}
 }
 
-// Stop stops the timer and logs the execution time
+// Stop stops the timer and returns the execution time. Runs of at least the configured
+// SlowReducerThreshold are always logged as warnings; the rest only with
+// EnablePerformanceLogging.
 func (pt *PerformanceTimer) Stop() time.Duration {
-	duration := time.Since(pt.StartTime)
-	LogInfo(fmt.Sprintf("Performance[%s]: %v", pt.Name, duration))
+	duration := clock.Now().Sub(pt.StartTime)
+	config := constants.GetGlobalConfiguration()
+	switch {
+	case config.SlowReducerThreshold > 0 && duration >= config.SlowReducerThreshold:
+		LogWarn(fmt.Sprintf("Slow reducer[%s]: %v exceeds %v", pt.Name, duration, config.SlowReducerThreshold))
+	case config.EnablePerformanceLogging:
+		LogInfo(fmt.Sprintf("Performance[%s]: %v", pt.Name, duration))
+	}
 	return duration
 }
 
@@ -474,4 +729,5 @@ const (
 	ErrorCodeInternalError    = "INTERNAL_ERROR"
 	ErrorCodeUnauthorized     = "UNAUTHORIZED"
 	ErrorCodeInvalidState     = "INVALID_STATE"
+	ErrorCodeServerFull       = "SERVER_FULL"
 )
@@ -2,10 +2,16 @@ package reducers
 
 import (
 	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/clockworklabs/Blackholio/server-go/constants"
+	"github.com/clockworklabs/Blackholio/server-go/logic"
 	"github.com/clockworklabs/Blackholio/server-go/tables"
 	"github.com/clockworklabs/Blackholio/server-go/types"
 )
@@ -14,7 +20,7 @@ import (
 
 func createTestContext() *ReducerContext {
 	identity := tables.NewIdentity([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
-	timestamp := tables.NewTimestampFromTime(time.Now())
+	timestamp := Now()
 
 	return &ReducerContext{
 		Sender:       identity,
@@ -24,6 +30,68 @@ func createTestContext() *ReducerContext {
 	}
 }
 
+func createTestWorld(t *testing.T, worldSize uint64) *ReducerContext {
+	t.Helper()
+	ctx := createTestContext()
+	if err := ctx.Database.InsertConfig(tables.NewConfig(0, worldSize)); err != nil {
+		t.Fatalf("Failed to insert config: %v", err)
+	}
+	return ctx
+}
+
+// withConfig installs the default configuration with modify applied as the global
+// configuration for the rest of the test, restoring the previous one afterwards
+func withConfig(t *testing.T, modify func(*constants.Configuration)) *constants.Configuration {
+	t.Helper()
+	previous := constants.GetGlobalConfiguration()
+	config := constants.DefaultConfiguration()
+	modify(config)
+	if err := constants.SetGlobalConfiguration(config); err != nil {
+		t.Fatalf("SetGlobalConfiguration failed: %v", err)
+	}
+	t.Cleanup(func() { constants.SetGlobalConfiguration(previous) })
+	return config
+}
+
+// createAdminWorld is createTestWorld with the sender recorded as the arena admin
+func createAdminWorld(t *testing.T, worldSize uint64) *ReducerContext {
+	t.Helper()
+	ctx := createTestContext()
+	config := tables.NewConfig(0, worldSize)
+	config.AdminIdentity = ctx.Sender
+	if err := ctx.Database.InsertConfig(config); err != nil {
+		t.Fatalf("Failed to insert config: %v", err)
+	}
+	return ctx
+}
+
+func insertTestEntity(t *testing.T, db *DatabaseContext, x, y float32, mass uint32) *tables.Entity {
+	t.Helper()
+	entity := tables.NewEntity(0, types.NewDbVector2(x, y), mass)
+	if err := db.InsertEntity(entity); err != nil {
+		t.Fatalf("Failed to insert entity: %v", err)
+	}
+	return entity
+}
+
+// manualClock is a Clock that only moves when advanced
+type manualClock struct {
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time { return c.now }
+
+func (c *manualClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+// installManualClock replaces the reducer clock with a manualClock for the rest of the test
+func installManualClock(t *testing.T) *manualClock {
+	t.Helper()
+	c := &manualClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	SetClock(c)
+	t.Cleanup(func() { SetClock(nil) })
+	return c
+}
+
 func createTestPlayer() *tables.Player {
 	identity := tables.NewIdentity([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
 	return tables.NewPlayer(identity, 1, "TestPlayer")
@@ -235,6 +303,58 @@ func TestGenericReducer(t *testing.T) {
 			t.Error("Argument names should match")
 		}
 	})
+
+	t.Run("Reducer with argument type", func(t *testing.T) {
+		called := false
+		reducer := NewReducer("EnterGame", func(ctx *ReducerContext, args []byte) ReducerResult {
+			called = true
+			return SuccessResult{}
+		}).WithArgumentType(EnterGameArgs{})
+
+		schema := reducer.ArgumentSchema()
+		if len(schema) != 1 || schema[0].Name != "name" || schema[0].Kind != "string" {
+			t.Fatalf("Unexpected schema for EnterGameArgs: %+v", schema)
+		}
+
+		ctx := createTestContext()
+		invalid := map[string]string{
+			"Missing required field": `{}`,
+			"Wrong-typed field":      `{"name": 42}`,
+			"Not an object":          `["name"]`,
+		}
+		for name, args := range invalid {
+			result := reducer.Invoke(ctx, []byte(args))
+			if result.IsSuccess() {
+				t.Errorf("%s: invalid arguments should be rejected", name)
+			}
+			if !strings.Contains(result.Error(), ErrorCodeInvalidArguments) {
+				t.Errorf("%s: expected %s error, got %s", name, ErrorCodeInvalidArguments, result.Error())
+			}
+		}
+		if called {
+			t.Error("Handler should not run for invalid arguments")
+		}
+
+		if result := reducer.Invoke(ctx, []byte(`{"name": "Player"}`)); !result.IsSuccess() || !called {
+			t.Errorf("Valid arguments should reach the handler: %s", result.Error())
+		}
+	})
+
+	t.Run("Argument schema for custom JSON types", func(t *testing.T) {
+		reducer := NewReducer("UpdatePlayerInput", func(ctx *ReducerContext, args []byte) ReducerResult {
+			return SuccessResult{}
+		}).WithArgumentType(UpdatePlayerInputArgs{})
+
+		// DbVector2 decodes from both object and array forms
+		for _, args := range []string{`{"direction": {"x": 1, "y": 0}}`, `{"direction": [1, 0]}`} {
+			if err := reducer.ValidateArgs([]byte(args)); err != nil {
+				t.Errorf("ValidateArgs(%s) failed: %v", args, err)
+			}
+		}
+		if err := reducer.ValidateArgs([]byte(`{}`)); err == nil {
+			t.Error("Missing direction should be rejected")
+		}
+	})
 }
 
 // Test LifecycleType
@@ -339,6 +459,147 @@ func TestHandleResult(t *testing.T) {
 	})
 }
 
+// Test logging backends
+
+func TestLogger(t *testing.T) {
+	defer SetLogger(nil)
+
+	t.Run("Host logger routes levels", func(t *testing.T) {
+		type line struct {
+			level   uint8
+			message string
+		}
+		var lines []line
+		SetLogger(hostLogger{write: func(level uint8, message string) {
+			lines = append(lines, line{level, message})
+		}})
+
+		LogInfo("info")
+		LogWarn("warn")
+		LogError("error")
+
+		expected := []line{
+			{hostLogLevelInfo, "info"},
+			{hostLogLevelWarn, "warn"},
+			{hostLogLevelError, "error"},
+		}
+		if !reflect.DeepEqual(lines, expected) {
+			t.Errorf("Host console received %v, want %v", lines, expected)
+		}
+	})
+
+	t.Run("Nil restores stdout", func(t *testing.T) {
+		SetLogger(nil)
+		if _, ok := logger.(stdoutLogger); !ok {
+			t.Errorf("Expected the stdout logger, got %T", logger)
+		}
+	})
+}
+
+// Test the injectable clock
+
+func TestClock(t *testing.T) {
+	t.Run("Scheduled intervals start from the clock", func(t *testing.T) {
+		clk := installManualClock(t)
+		ctx := createTestContext()
+		if ctx.Timestamp != tables.NewTimestampFromTime(clk.now) {
+			t.Errorf("Context timestamp %s should come from the clock", ctx.Timestamp.String())
+		}
+
+		interval := tables.NewTimeDurationFromDuration(5 * time.Second)
+		if _, err := ctx.Database.ScheduleReducerWithID("SpawnFood", nil, tables.NewScheduleAtInterval(interval)); err != nil {
+			t.Fatalf("ScheduleReducerWithID failed: %v", err)
+		}
+		timers, _ := ctx.Database.GetScheduledTimers()
+		if len(timers) != 1 || timers[0].NextRun != Now().Add(interval) {
+			t.Fatalf("Expected one timer due at %s, got %+v", Now().Add(interval).String(), timers)
+		}
+	})
+
+	t.Run("Performance timer", func(t *testing.T) {
+		clk := installManualClock(t)
+		timer := NewPerformanceTimer("test")
+		clk.Advance(250 * time.Millisecond)
+		if duration := timer.Stop(); duration != 250*time.Millisecond {
+			t.Errorf("Timer measured %v, want 250ms", duration)
+		}
+	})
+
+	t.Run("Recombine once the delay has passed", func(t *testing.T) {
+		clk := installManualClock(t)
+		ctx := createTestWorld(t, 1000)
+		for _, x := range []float32{400, 600} {
+			entity := insertTestEntity(t, ctx.Database, x, 500, 50)
+			if err := ctx.Database.InsertCircle(tables.NewCircle(entity.EntityID, 1, types.Right(), 0, Now())); err != nil {
+				t.Fatalf("InsertCircle failed: %v", err)
+			}
+		}
+		consumeTimers := func() int {
+			timers, _ := ctx.Database.GetScheduledTimers()
+			count := 0
+			for _, timer := range timers {
+				if timer.Name == "ConsumeEntity" {
+					count++
+				}
+			}
+			return count
+		}
+
+		delay := time.Duration(constants.GetGlobalConfiguration().SplitRecombineDelaySec * float32(time.Second))
+		args, _ := MarshalArgs(CircleRecombineArgs{PlayerID: 1})
+
+		clk.Advance(delay - time.Second)
+		ctx.Timestamp = Now()
+		CircleRecombineReducer(ctx, args)
+		if count := consumeTimers(); count != 0 {
+			t.Fatalf("Circles should not recombine before the delay, got %d consume timers", count)
+		}
+
+		clk.Advance(time.Second)
+		ctx.Timestamp = Now()
+		CircleRecombineReducer(ctx, args)
+		if count := consumeTimers(); count != 1 {
+			t.Errorf("Circles should recombine once the delay has passed, got %d consume timers", count)
+		}
+	})
+
+	t.Run("Decay once the grace period has passed", func(t *testing.T) {
+		withConfig(t, func(config *constants.Configuration) {
+			config.DecayGracePeriodSec = 10
+		})
+
+		clk := installManualClock(t)
+		ctx := createTestWorld(t, 1000)
+		if err := ctx.Database.InsertPlayer(createTestPlayer()); err != nil {
+			t.Fatalf("InsertPlayer failed: %v", err)
+		}
+		entity := insertTestEntity(t, ctx.Database, 500, 500, 100)
+		circle := tables.NewCircle(entity.EntityID, 1, types.Right(), 0, Now())
+		circle.SpawnedAt = Now()
+		if err := ctx.Database.InsertCircle(circle); err != nil {
+			t.Fatalf("InsertCircle failed: %v", err)
+		}
+		mass := func() uint32 {
+			current, _ := ctx.Database.GetEntity(entity.EntityID)
+			return current.Mass
+		}
+
+		clk.Advance(9 * time.Second)
+		ctx.Timestamp = Now()
+		CircleDecayReducer(ctx, []byte{})
+		if got := mass(); got != 100 {
+			t.Fatalf("Circle should not decay within its grace period, got mass %d", got)
+		}
+
+		clk.Advance(time.Second)
+		ctx.Timestamp = Now()
+		CircleDecayReducer(ctx, []byte{})
+		if got := mass(); got != 99 {
+			t.Errorf("Circle should decay once the grace period has passed, got mass %d", got)
+		}
+	})
+}
+
 // Test performance monitoring
 
 func TestPerformanceTimer(t *testing.T) {
@@ -356,6 +617,42 @@ func TestPerformanceTimer(t *testing.T) {
 			t.Error("Timer should measure at least 1ms")
 		}
 	})
+
+	t.Run("Only slow reducers logged", func(t *testing.T) {
+		config := withConfig(t, func(config *constants.Configuration) {
+			config.SlowReducerThreshold = 100 * time.Millisecond
+		})
+
+		var lines []string
+		SetLogger(hostLogger{write: func(level uint8, message string) {
+			lines = append(lines, message)
+		}})
+		defer SetLogger(nil)
+
+		clk := installManualClock(t)
+		reducer := func(name string, runtime time.Duration) {
+			timer := NewPerformanceTimer(name)
+			defer timer.Stop()
+			clk.Advance(runtime)
+		}
+		reducer("Fast", time.Millisecond)
+		reducer("Slow", 250*time.Millisecond)
+
+		if len(lines) != 1 || !strings.Contains(lines[0], "Slow reducer[Slow]") {
+			t.Errorf("Expected only the slow reducer to be logged, got %q", lines)
+		}
+
+		// Verbose logging still reports everything under the threshold
+		withConfig(t, func(verbose *constants.Configuration) {
+			*verbose = *config
+			verbose.EnablePerformanceLogging = true
+		})
+		lines = nil
+		reducer("Fast", time.Millisecond)
+		if len(lines) != 1 || !strings.Contains(lines[0], "Performance[Fast]") {
+			t.Errorf("Expected the fast reducer with performance logging on, got %q", lines)
+		}
+	})
 }
 
 // Test reducer metadata
@@ -521,71 +818,3037 @@ func TestBlackholioReducers(t *testing.T) {
 	})
 }
 
-// Benchmark tests
+// Test in-memory database backend
 
-func BenchmarkReducerInvocation(b *testing.B) {
-	reducer := NewReducer("benchmark", func(ctx *ReducerContext, args []byte) ReducerResult {
-		return SuccessResult{}
+func TestInMemoryDatabase(t *testing.T) {
+	t.Run("Entity auto-increment and copies", func(t *testing.T) {
+		db := &DatabaseContext{}
+
+		first := insertTestEntity(t, db, 10, 10, 15)
+		second := insertTestEntity(t, db, 20, 20, 15)
+		if first.EntityID == 0 || second.EntityID == 0 || first.EntityID == second.EntityID {
+			t.Fatalf("Entities should get distinct non-zero IDs: %d, %d", first.EntityID, second.EntityID)
+		}
+
+		fetched, err := db.GetEntity(first.EntityID)
+		if err != nil {
+			t.Fatalf("GetEntity failed: %v", err)
+		}
+
+		// Mutating a fetched row must not change the stored row until updated
+		fetched.Mass = 99
+		stored, _ := db.GetEntity(first.EntityID)
+		if stored.Mass != 15 {
+			t.Errorf("Stored mass changed without UpdateEntity: got %d", stored.Mass)
+		}
+
+		if err := db.UpdateEntity(fetched); err != nil {
+			t.Fatalf("UpdateEntity failed: %v", err)
+		}
+		stored, _ = db.GetEntity(first.EntityID)
+		if stored.Mass != 99 {
+			t.Errorf("Stored mass not updated: got %d", stored.Mass)
+		}
 	})
 
-	ctx := createTestContext()
-	args := []byte("{}")
+	t.Run("Player lifecycle", func(t *testing.T) {
+		db := &DatabaseContext{}
+		player := createTestPlayer()
+		player.PlayerID = 0
 
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		reducer.Invoke(ctx, args)
-	}
-}
+		if err := db.InsertPlayer(player); err != nil {
+			t.Fatalf("InsertPlayer failed: %v", err)
+		}
+		if player.PlayerID == 0 {
+			t.Error("InsertPlayer should assign a PlayerID")
+		}
+		if err := db.InsertPlayer(player); err == nil {
+			t.Error("Inserting a duplicate identity should fail")
+		}
 
-func BenchmarkJSONMarshaling(b *testing.B) {
-	args := map[string]interface{}{
-		"name":      "test",
-		"direction": types.NewDbVector2(1.0, 2.0),
-		"mass":      uint32(100),
-	}
+		count, _ := db.GetPlayerCount()
+		if count != 1 {
+			t.Errorf("Expected 1 player, got %d", count)
+		}
 
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		MarshalArgs(args)
-	}
-}
+		if err := db.DeletePlayer(player.Identity); err != nil {
+			t.Fatalf("DeletePlayer failed: %v", err)
+		}
+		if _, err := db.GetPlayer(player.Identity); err == nil {
+			t.Error("Deleted player should not be found")
+		}
+	})
 
-func BenchmarkRNGGeneration(b *testing.B) {
-	ctx := createTestContext()
-	rng := ctx.Rng()
+	t.Run("Circles by player", func(t *testing.T) {
+		db := &DatabaseContext{}
+		for i := 0; i < 3; i++ {
+			entity := insertTestEntity(t, db, 10, 10, 15)
+			playerID := uint32(1)
+			if i == 2 {
+				playerID = 2
+			}
+			circle := tables.NewCircle(entity.EntityID, playerID, types.Up(), 0, tables.Timestamp{})
+			if err := db.InsertCircle(circle); err != nil {
+				t.Fatalf("InsertCircle failed: %v", err)
+			}
+		}
+
+		circles, err := db.GetCirclesByPlayer(1)
+		if err != nil {
+			t.Fatalf("GetCirclesByPlayer failed: %v", err)
+		}
+		if len(circles) != 2 {
+			t.Errorf("Expected 2 circles for player 1, got %d", len(circles))
+		}
 
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		rng.Float32()
-	}
+		for playerID, expected := range map[uint32]uint32{1: 2, 2: 1, 3: 0} {
+			count, err := db.GetCircleCountByPlayer(playerID)
+			if err != nil {
+				t.Fatalf("GetCircleCountByPlayer failed: %v", err)
+			}
+			if count != expected {
+				t.Errorf("Expected %d circles for player %d, got %d", expected, playerID, count)
+			}
+		}
+
+		// The per-player index follows deletes and ownership changes
+		if err := db.DestroyEntity(circles[0].EntityID); err != nil {
+			t.Fatalf("DestroyEntity failed: %v", err)
+		}
+		moved := *circles[1]
+		moved.PlayerID = 3
+		if err := db.UpdateCircle(&moved); err != nil {
+			t.Fatalf("UpdateCircle failed: %v", err)
+		}
+		for playerID, expected := range map[uint32]uint32{1: 0, 2: 1, 3: 1} {
+			count, err := db.GetCircleCountByPlayer(playerID)
+			if err != nil {
+				t.Fatalf("GetCircleCountByPlayer failed: %v", err)
+			}
+			if count != expected {
+				t.Errorf("After changes expected %d circles for player %d, got %d", expected, playerID, count)
+			}
+		}
+	})
+
+	t.Run("Player entities", func(t *testing.T) {
+		db := &DatabaseContext{}
+		owned := map[uint32][]uint32{}
+		for i := 0; i < 5; i++ {
+			playerID := uint32(1 + i%2)
+			entity := insertTestEntity(t, db, float32(10*i), 10, uint32(15+i))
+			if err := db.InsertCircle(tables.NewCircle(entity.EntityID, playerID, types.Up(), 0, tables.Timestamp{})); err != nil {
+				t.Fatalf("InsertCircle failed: %v", err)
+			}
+			owned[playerID] = append(owned[playerID], entity.EntityID)
+		}
+		insertTestEntity(t, db, 50, 50, 3) // Unowned entity
+
+		for playerID, expected := range owned {
+			entities, err := db.GetPlayerEntities(playerID)
+			if err != nil {
+				t.Fatalf("GetPlayerEntities failed: %v", err)
+			}
+			circles, _ := db.GetCirclesByPlayer(playerID)
+			if len(entities) != len(expected) || len(circles) != len(expected) {
+				t.Fatalf("Player %d: expected %d entities, got %d (%d circles)", playerID, len(expected), len(entities), len(circles))
+			}
+			for i, entity := range entities {
+				if entity.EntityID != expected[i] || entity.EntityID != circles[i].EntityID {
+					t.Errorf("Player %d entity %d = %d, want %d", playerID, i, entity.EntityID, expected[i])
+				}
+			}
+		}
+
+		if entities, _ := db.GetPlayerEntities(99); len(entities) != 0 {
+			t.Errorf("Unknown player should have no entities, got %d", len(entities))
+		}
+	})
+
+	t.Run("Circle counts", func(t *testing.T) {
+		db := &DatabaseContext{}
+		for playerID, circles := range map[uint32]int{1: 2, 2: 4, 3: 1, 4: 3} {
+			for i := 0; i < circles; i++ {
+				entity := insertTestEntity(t, db, 10, 10, 15)
+				if err := db.InsertCircle(tables.NewCircle(entity.EntityID, playerID, types.Up(), 0, tables.Timestamp{})); err != nil {
+					t.Fatalf("InsertCircle failed: %v", err)
+				}
+			}
+		}
+
+		counts, err := db.GetCircleCounts()
+		if err != nil {
+			t.Fatalf("GetCircleCounts failed: %v", err)
+		}
+		for playerID, expected := range map[uint32]uint32{1: 2, 2: 4, 3: 1, 4: 3} {
+			if counts[playerID] != expected {
+				t.Errorf("Expected %d circles for player %d, got %d", expected, playerID, counts[playerID])
+			}
+		}
+
+		top := logic.TopByCircleCount(counts, 2)
+		if len(top) != 2 || top[0] != 2 || top[1] != 4 {
+			t.Errorf("Top 2 by circle count = %v, want [2 4]", top)
+		}
+	})
+
+	t.Run("Configs per arena", func(t *testing.T) {
+		db := &DatabaseContext{}
+		if err := db.InsertConfig(tables.NewConfig(tables.DefaultArenaID, 1000)); err != nil {
+			t.Fatalf("Failed to insert default config: %v", err)
+		}
+		if err := db.InsertConfig(tables.NewConfig(1, 3000)); err != nil {
+			t.Fatalf("Failed to insert second config: %v", err)
+		}
+
+		first, err := db.GetConfigByID(tables.DefaultArenaID)
+		if err != nil || first.WorldSize != 1000 {
+			t.Errorf("GetConfigByID(0) = %+v, %v; want world size 1000", first, err)
+		}
+		second, err := db.GetConfigByID(1)
+		if err != nil || second.WorldSize != 3000 {
+			t.Errorf("GetConfigByID(1) = %+v, %v; want world size 3000", second, err)
+		}
+		if config, _ := db.GetConfig(); config.ID != tables.DefaultArenaID {
+			t.Errorf("GetConfig should return the default arena, got id %d", config.ID)
+		}
+		if _, err := db.GetConfigByID(2); err == nil {
+			t.Error("GetConfigByID should fail for an unknown arena")
+		}
+	})
+
+	t.Run("Missing config", func(t *testing.T) {
+		db := &DatabaseContext{}
+		if _, err := db.GetConfig(); err == nil {
+			t.Error("GetConfig should fail when no config was inserted")
+		}
+	})
+
+	t.Run("DestroyEntity removes circle rows", func(t *testing.T) {
+		db := &DatabaseContext{}
+		entity := insertTestEntity(t, db, 10, 10, 15)
+		if err := db.InsertCircle(tables.NewCircle(entity.EntityID, 1, types.Up(), 0, tables.Timestamp{})); err != nil {
+			t.Fatalf("InsertCircle failed: %v", err)
+		}
+
+		if err := db.DestroyEntity(entity.EntityID); err != nil {
+			t.Fatalf("DestroyEntity failed: %v", err)
+		}
+		if _, err := db.GetEntity(entity.EntityID); err == nil {
+			t.Error("Destroyed entity row should be gone")
+		}
+		if _, err := db.GetCircle(entity.EntityID); err == nil {
+			t.Error("Destroyed circle row should be gone")
+		}
+		if circles, _ := db.GetCirclesByPlayer(1); len(circles) != 0 {
+			t.Errorf("Expected no circles for player 1, got %d", len(circles))
+		}
+		if err := db.DestroyEntity(entity.EntityID); err == nil {
+			t.Error("Destroying a missing entity should fail")
+		}
+	})
+
+	t.Run("DestroyEntity removes food rows", func(t *testing.T) {
+		db := &DatabaseContext{}
+		entity := insertTestEntity(t, db, 10, 10, 3)
+		if err := db.InsertFood(tables.NewFood(entity.EntityID)); err != nil {
+			t.Fatalf("InsertFood failed: %v", err)
+		}
+
+		if err := db.DestroyEntity(entity.EntityID); err != nil {
+			t.Fatalf("DestroyEntity failed: %v", err)
+		}
+		if count, _ := db.GetFoodCount(); count != 0 {
+			t.Errorf("Expected food count 0, got %d", count)
+		}
+		if isFood, _ := db.IsFood(entity.EntityID); isFood {
+			t.Error("Destroyed food should not be reported as food")
+		}
+	})
+
+	t.Run("ConsumeEntity destroys consumed circle", func(t *testing.T) {
+		ctx := createTestWorld(t, 1000)
+		consumer := insertTestEntity(t, ctx.Database, 10, 10, 100)
+		consumed := insertTestEntity(t, ctx.Database, 12, 10, 20)
+		for _, entity := range []*tables.Entity{consumer, consumed} {
+			if err := ctx.Database.InsertCircle(tables.NewCircle(entity.EntityID, entity.EntityID, types.Up(), 0, tables.Timestamp{})); err != nil {
+				t.Fatalf("InsertCircle failed: %v", err)
+			}
+		}
+
+		argsData, _ := MarshalArgs(ConsumeEntityArgs{ConsumerEntityID: consumer.EntityID, ConsumedEntityID: consumed.EntityID})
+		if result := ConsumeEntityReducer(ctx, argsData); !result.IsSuccess() {
+			t.Fatalf("ConsumeEntity failed: %s", result.Error())
+		}
+
+		if _, err := ctx.Database.GetCircle(consumed.EntityID); err == nil {
+			t.Error("Consumed circle row should be gone")
+		}
+		if _, err := ctx.Database.GetEntity(consumed.EntityID); err == nil {
+			t.Error("Consumed entity row should be gone")
+		}
+		updated, _ := ctx.Database.GetEntity(consumer.EntityID)
+		if updated == nil || updated.Mass != 120 {
+			t.Errorf("Consumer should have absorbed the mass: %+v", updated)
+		}
+	})
 }
 
-// Test constants integration
+func TestInMemoryScheduler(t *testing.T) {
+	t.Run("One-shot consume fires exactly once", func(t *testing.T) {
+		ctx := createTestWorld(t, 1000)
+		consumer := insertTestEntity(t, ctx.Database, 10, 10, 100)
+		consumed := insertTestEntity(t, ctx.Database, 12, 10, 20)
 
-func TestConstantsIntegration(t *testing.T) {
-	t.Run("Constants accessible", func(t *testing.T) {
+		fireAt := tables.NewTimestamp(5_000_000)
+		argsData, _ := MarshalArgs(ConsumeEntityArgs{ConsumerEntityID: consumer.EntityID, ConsumedEntityID: consumed.EntityID})
+		if err := ctx.Database.ScheduleReducer("ConsumeEntity", argsData, tables.NewScheduleAtTime(fireAt)); err != nil {
+			t.Fatalf("ScheduleReducer failed: %v", err)
+		}
+
+		if fired := ctx.Database.RunDueTimers(tables.NewTimestamp(4_999_999)); fired != 0 {
+			t.Errorf("Timer should not fire before its time, fired %d", fired)
+		}
+		if _, err := ctx.Database.GetEntity(consumed.EntityID); err != nil {
+			t.Error("Consumed entity should still exist before the timer fires")
+		}
+
+		if fired := ctx.Database.RunDueTimers(fireAt); fired != 1 {
+			t.Errorf("Timer should fire at its time, fired %d", fired)
+		}
+		if _, err := ctx.Database.GetEntity(consumed.EntityID); err == nil {
+			t.Error("Consumed entity should be destroyed after the timer fires")
+		}
+
+		if fired := ctx.Database.RunDueTimers(fireAt.Add(tables.NewTimeDuration(1_000_000))); fired != 0 {
+			t.Errorf("One-shot timer should not fire again, fired %d", fired)
+		}
+		updated, _ := ctx.Database.GetEntity(consumer.EntityID)
+		if updated == nil || updated.Mass != 120 {
+			t.Errorf("Consumer should have absorbed the mass exactly once: %+v", updated)
+		}
+	})
+
+	t.Run("Init is idempotent", func(t *testing.T) {
+		ctx := createTestContext()
+		for i := 0; i < 2; i++ {
+			if result := InitReducer(ctx, []byte{}); !result.IsSuccess() {
+				t.Fatalf("InitReducer call %d failed: %s", i+1, result.Error())
+			}
+		}
+
+		// Configs are keyed by arena, so the second Init succeeding means it didn't insert again
+		if config, err := ctx.Database.GetConfig(); err != nil || config.WorldSize != constants.DEFAULT_WORLD_SIZE {
+			t.Errorf("Expected the default config after two Inits: %+v, %v", config, err)
+		}
+		for _, name := range []string{"MoveAllPlayers", "SpawnFood", "CircleDecay", "CleanupStalePlayers"} {
+			if count, _ := ctx.Database.CountScheduledReducers(name); count != 1 {
+				t.Errorf("Expected exactly one %s timer, got %d", name, count)
+			}
+		}
+	})
+
+	t.Run("Init scheduled timers", func(t *testing.T) {
+		ctx := createTestContext()
+		if result := InitReducer(ctx, []byte{}); !result.IsSuccess() {
+			t.Fatalf("InitReducer failed: %s", result.Error())
+		}
+
+		timers, err := ctx.Database.GetScheduledTimers()
+		if err != nil {
+			t.Fatalf("GetScheduledTimers failed: %v", err)
+		}
 		config := constants.GetGlobalConfiguration()
+		expected := map[string]time.Duration{
+			"MoveAllPlayers":      config.MovePlayersInterval,
+			"SpawnFood":           config.SpawnFoodInterval,
+			"CircleDecay":         config.CircleDecayInterval,
+			"CleanupStalePlayers": constants.CLEANUP_STALE_PLAYERS_INTERVAL,
+		}
+		if len(timers) != len(expected) {
+			t.Fatalf("Expected %d timers after Init, got %d: %+v", len(expected), len(timers), timers)
+		}
+		for i, timer := range timers {
+			if i > 0 && timer.ScheduledID <= timers[i-1].ScheduledID {
+				t.Error("Timers should be ordered by scheduled ID")
+			}
+			interval, ok := expected[timer.Name]
+			if !ok {
+				t.Errorf("Unexpected timer %s", timer.Name)
+				continue
+			}
+			if !timer.Schedule.IsInterval() || timer.Schedule.GetInterval().ToDuration() != interval {
+				t.Errorf("Timer %s should repeat every %v, got %s", timer.Name, interval, timer.Schedule.String())
+			}
+			if timer.NextRun.Microseconds == 0 {
+				t.Errorf("Timer %s should report its next run", timer.Name)
+			}
+		}
+	})
 
-		if config.StartPlayerMass == 0 {
-			t.Error("Constants should be accessible")
+	t.Run("Init keeps an existing config", func(t *testing.T) {
+		ctx := createTestWorld(t, 5000)
+		if result := InitReducer(ctx, []byte{}); !result.IsSuccess() {
+			t.Fatalf("InitReducer failed: %s", result.Error())
 		}
+		if config, _ := ctx.Database.GetConfig(); config == nil || config.WorldSize != 5000 {
+			t.Errorf("Init should not overwrite an existing config: %+v", config)
+		}
+	})
 
-		if config.TargetFoodCount == 0 {
-			t.Error("Target food count should be set")
+	t.Run("Interval timers re-arm", func(t *testing.T) {
+		ctx := createTestContext()
+		if result := InitReducer(ctx, []byte{}); !result.IsSuccess() {
+			t.Fatalf("InitReducer failed: %s", result.Error())
+		}
+
+		// All three game timers are due once the longest interval has passed
+		start := tables.NewTimestampFromTime(time.Now().Add(constants.CIRCLE_DECAY_INTERVAL + time.Second))
+		if fired := ctx.Database.RunDueTimers(start); fired != 3 {
+			t.Errorf("Expected move, spawn and decay timers to fire, fired %d", fired)
+		}
+		if fired := ctx.Database.RunDueTimers(start); fired != 0 {
+			t.Errorf("Re-armed timers should not fire again immediately, fired %d", fired)
+		}
+
+		// Only the move timer is due one move interval later
+		next := start.Add(tables.NewTimeDurationFromDuration(constants.MOVE_PLAYERS_INTERVAL))
+		if fired := ctx.Database.RunDueTimers(next); fired != 1 {
+			t.Errorf("Expected only the move timer to fire, fired %d", fired)
 		}
 	})
 
-	t.Run("Mathematical functions", func(t *testing.T) {
-		mass := uint32(100)
-		radius := constants.MassToRadius(mass)
+	t.Run("Cancelled timer never fires", func(t *testing.T) {
+		ctx := createTestWorld(t, 1000)
+		consumer := insertTestEntity(t, ctx.Database, 10, 10, 100)
+		consumed := insertTestEntity(t, ctx.Database, 12, 10, 20)
 
-		if radius <= 0 {
-			t.Error("Radius should be positive")
+		fireAt := tables.NewTimestamp(5_000_000)
+		argsData, _ := MarshalArgs(ConsumeEntityArgs{ConsumerEntityID: consumer.EntityID, ConsumedEntityID: consumed.EntityID})
+		scheduledID, err := ctx.Database.ScheduleReducerWithID("ConsumeEntity", argsData, tables.NewScheduleAtTime(fireAt))
+		if err != nil {
+			t.Fatalf("ScheduleReducerWithID failed: %v", err)
+		}
+		if scheduledID == 0 {
+			t.Error("ScheduleReducerWithID should return a non-zero ID")
 		}
 
-		speed := constants.MassToMaxMoveSpeed(mass)
-		if speed <= 0 {
-			t.Error("Speed should be positive")
+		if err := ctx.Database.CancelScheduledReducer(scheduledID); err != nil {
+			t.Fatalf("CancelScheduledReducer failed: %v", err)
+		}
+		if fired := ctx.Database.RunDueTimers(fireAt); fired != 0 {
+			t.Errorf("Cancelled timer should not fire, fired %d", fired)
+		}
+		if _, err := ctx.Database.GetEntity(consumed.EntityID); err != nil {
+			t.Error("Consumed entity should survive a cancelled consume")
+		}
+
+		if err := ctx.Database.CancelScheduledReducer(scheduledID); err != nil {
+			t.Errorf("Cancelling an already cancelled timer should be benign: %v", err)
+		}
+		if err := ctx.Database.CancelScheduledReducer(9999); err != nil {
+			t.Errorf("Cancelling a nonexistent timer should be benign: %v", err)
+		}
+	})
+
+	t.Run("Invalid schedule", func(t *testing.T) {
+		db := &DatabaseContext{}
+		if err := db.ScheduleReducer("SpawnFood", nil, tables.ScheduleAt{}); err == nil {
+			t.Error("Scheduling without a time or interval should fail")
+		}
+	})
+}
+
+func TestInMemoryDatabaseConcurrency(t *testing.T) {
+	// Resolve the global configuration up front so the reducers only read it
+	constants.GetGlobalConfiguration()
+
+	ctx := createTestWorld(t, 1000)
+	db := ctx.Database
+
+	player := createTestPlayer()
+	player.PlayerID = 0
+	if err := db.InsertPlayer(player); err != nil {
+		t.Fatalf("InsertPlayer failed: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		entity := insertTestEntity(t, db, float32(100+i*50), 100, 30)
+		circle := tables.NewCircle(entity.EntityID, player.PlayerID, types.Right(), 1.0, ctx.Timestamp)
+		if err := db.InsertCircle(circle); err != nil {
+			t.Fatalf("InsertCircle failed: %v", err)
+		}
+	}
+
+	inputArgs, _ := MarshalArgs(UpdatePlayerInputArgs{Direction: types.NewDbVector2(0, 1)})
+
+	// Each goroutine gets its own reducer context (and RNG) but shares the database
+	newCtx := func() *ReducerContext {
+		return &ReducerContext{Sender: player.Identity, Timestamp: ctx.Timestamp, Database: db}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			MoveAllPlayersReducer(newCtx(), []byte{})
+		}()
+		go func() {
+			defer wg.Done()
+			SpawnFoodReducer(newCtx(), []byte{})
+		}()
+		go func() {
+			defer wg.Done()
+			UpdatePlayerInputReducer(newCtx(), inputArgs)
+		}()
+	}
+	wg.Wait()
+
+	foodCount, _ := db.GetFoodCount()
+	if foodCount == 0 {
+		t.Error("SpawnFood should have populated food")
+	}
+
+	circles, _ := db.GetCirclesByPlayer(player.PlayerID)
+	for _, circle := range circles {
+		if !circle.Direction.Equal(types.Up()) {
+			t.Errorf("Circle %d direction = %v, want %v", circle.EntityID, circle.Direction, types.Up())
+		}
+	}
+}
+
+func TestClassifyCollision(t *testing.T) {
+	db := &DatabaseContext{}
+
+	insertCircle := func(playerID uint32) *tables.Circle {
+		entity := insertTestEntity(t, db, 100, 100, 30)
+		circle := tables.NewCircle(entity.EntityID, playerID, types.Up(), 0, tables.Timestamp{})
+		if err := db.InsertCircle(circle); err != nil {
+			t.Fatalf("InsertCircle failed: %v", err)
+		}
+		return circle
+	}
+
+	mine := insertCircle(1)
+	sibling := insertCircle(1)
+	enemy := insertCircle(2)
+
+	foodEntity := insertTestEntity(t, db, 100, 100, 3)
+	if err := db.InsertFood(tables.NewFood(foodEntity.EntityID)); err != nil {
+		t.Fatalf("InsertFood failed: %v", err)
+	}
+
+	t.Run("IsFood", func(t *testing.T) {
+		if isFood, _ := db.IsFood(foodEntity.EntityID); !isFood {
+			t.Error("Food entity should be reported as food")
+		}
+		if isFood, _ := db.IsFood(enemy.EntityID); isFood {
+			t.Error("Circle entity should not be reported as food")
+		}
+	})
+
+	t.Run("GetPlayerIDForEntity", func(t *testing.T) {
+		if playerID, owned := db.GetPlayerIDForEntity(enemy.EntityID); !owned || playerID != 2 {
+			t.Errorf("Enemy circle should be owned by player 2, got %d (owned=%v)", playerID, owned)
+		}
+		if _, owned := db.GetPlayerIDForEntity(foodEntity.EntityID); owned {
+			t.Error("Food entity should not be owned by a player")
+		}
+		if _, owned := db.GetPlayerIDForEntity(9999); owned {
+			t.Error("Unknown entity should not be owned by a player")
+		}
+	})
+
+	entityFor := func(entityID uint32) *tables.Entity {
+		entity, err := db.GetEntity(entityID)
+		if err != nil {
+			return &tables.Entity{EntityID: entityID}
+		}
+		return entity
+	}
+
+	// Kind-tagged rows are classified without probing the food or circle tables
+	taggedFood := &tables.Entity{EntityID: 5000, Kind: tables.KindFood}
+	taggedEnemy := entityFor(enemy.EntityID)
+	taggedEnemy.Kind = tables.KindCircle
+	taggedOrphan := &tables.Entity{EntityID: 5001, Kind: tables.KindCircle}
+
+	tests := []struct {
+		name     string
+		other    *tables.Entity
+		expected CollisionKind
+	}{
+		{"Circle vs food", entityFor(foodEntity.EntityID), CollisionFood},
+		{"Circle vs enemy circle", entityFor(enemy.EntityID), CollisionEnemyCircle},
+		{"Circle vs own circle", entityFor(sibling.EntityID), CollisionOwnCircle},
+		{"Circle vs unknown entity", entityFor(9999), CollisionNone},
+		{"Circle vs food kind", taggedFood, CollisionFood},
+		{"Circle vs circle kind", taggedEnemy, CollisionEnemyCircle},
+		{"Circle vs circle kind without owner", taggedOrphan, CollisionNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, err := ClassifyCollision(db, mine, tt.other)
+			if err != nil {
+				t.Fatalf("ClassifyCollision failed: %v", err)
+			}
+			if kind != tt.expected {
+				t.Errorf("ClassifyCollision() = %s, want %s", kind, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEntityKindAtSpawn(t *testing.T) {
+	ctx := createTestWorld(t, 1000)
+
+	playerCtx := &ReducerContext{Sender: tables.NewIdentity([16]byte{1}), Timestamp: ctx.Timestamp, Database: ctx.Database}
+	if result := ConnectReducer(playerCtx, []byte{}); !result.IsSuccess() {
+		t.Fatalf("ConnectReducer failed: %s", result.Error())
+	}
+	argsData, _ := MarshalArgs(EnterGameArgs{Name: "Player"})
+	if result := EnterGameReducer(playerCtx, argsData); !result.IsSuccess() {
+		t.Fatalf("EnterGameReducer failed: %s", result.Error())
+	}
+	if result := SpawnFoodReducer(ctx, []byte{}); !result.IsSuccess() {
+		t.Fatalf("SpawnFoodReducer failed: %s", result.Error())
+	}
+
+	entities, err := ctx.Database.GetAllEntities()
+	if err != nil {
+		t.Fatalf("GetAllEntities failed: %v", err)
+	}
+	var circles, food int
+	for _, entity := range entities {
+		isFood, _ := ctx.Database.IsFood(entity.EntityID)
+		_, isCircle := ctx.Database.GetPlayerIDForEntity(entity.EntityID)
+		switch {
+		case isFood:
+			food++
+			if entity.Kind != tables.KindFood {
+				t.Errorf("Food entity %d has kind %s", entity.EntityID, entity.Kind)
+			}
+		case isCircle:
+			circles++
+			if entity.Kind != tables.KindCircle {
+				t.Errorf("Circle entity %d has kind %s", entity.EntityID, entity.Kind)
+			}
+		}
+	}
+	if circles == 0 || food == 0 {
+		t.Fatalf("Expected spawned circles and food, got %d circles and %d food", circles, food)
+	}
+}
+
+func TestDespawnStaleFood(t *testing.T) {
+	insertFood := func(t *testing.T, db *DatabaseContext, createdAt tables.Timestamp) *tables.Entity {
+		t.Helper()
+		entity := insertTestEntity(t, db, 100, 100, 3)
+		entity.Kind = tables.KindFood
+		entity.CreatedAt = createdAt
+		if err := db.UpdateEntity(entity); err != nil {
+			t.Fatalf("UpdateEntity failed: %v", err)
+		}
+		if err := db.InsertFood(tables.NewFood(entity.EntityID)); err != nil {
+			t.Fatalf("InsertFood failed: %v", err)
+		}
+		return entity
+	}
+	ago := func(now tables.Timestamp, d time.Duration) tables.Timestamp {
+		return tables.NewTimestamp(now.Microseconds - uint64(d.Microseconds()))
+	}
+
+	t.Run("Only old food despawns", func(t *testing.T) {
+		ctx := createTestWorld(t, 1000)
+		oldFood := insertFood(t, ctx.Database, ago(ctx.Timestamp, 2*time.Minute))
+		newFood := insertFood(t, ctx.Database, ago(ctx.Timestamp, 10*time.Second))
+		legacyFood := insertFood(t, ctx.Database, tables.Timestamp{})
+
+		oldCircle, _, _ := logic.SpawnCircleAt(1, 50, types.NewDbVector2(500, 500), ago(ctx.Timestamp, time.Hour))
+		if err := ctx.Database.InsertEntity(oldCircle); err != nil {
+			t.Fatalf("InsertEntity failed: %v", err)
+		}
+
+		despawned, err := logic.DespawnStaleFood(ctx.Database, time.Minute, ctx.Timestamp)
+		if err != nil {
+			t.Fatalf("DespawnStaleFood failed: %v", err)
+		}
+		if despawned != 1 {
+			t.Errorf("Expected 1 food despawned, got %d", despawned)
+		}
+		if _, err := ctx.Database.GetEntity(oldFood.EntityID); err == nil {
+			t.Error("Old food should be despawned")
+		}
+		for _, kept := range []*tables.Entity{newFood, legacyFood, oldCircle} {
+			if _, err := ctx.Database.GetEntity(kept.EntityID); err != nil {
+				t.Errorf("Entity %d should be kept: %v", kept.EntityID, err)
+			}
+		}
+		if count, _ := ctx.Database.GetFoodCount(); count != 2 {
+			t.Errorf("Expected 2 food left, got %d", count)
+		}
+	})
+
+	t.Run("SpawnFood replaces stale food", func(t *testing.T) {
+		withConfig(t, func(config *constants.Configuration) {
+			config.TargetFoodCount = 2
+			config.FoodPerPlayer = 0
+			config.FoodTTL = time.Minute
+		})
+
+		ctx := createTestWorld(t, 1000)
+		if err := ctx.Database.InsertPlayer(createTestPlayer()); err != nil {
+			t.Fatalf("InsertPlayer failed: %v", err)
+		}
+		oldFood := insertFood(t, ctx.Database, ago(ctx.Timestamp, 2*time.Minute))
+		newFood := insertFood(t, ctx.Database, ago(ctx.Timestamp, 10*time.Second))
+
+		if result := SpawnFoodReducer(ctx, []byte{}); !result.IsSuccess() {
+			t.Fatalf("SpawnFoodReducer failed: %s", result.Error())
+		}
+		if _, err := ctx.Database.GetEntity(oldFood.EntityID); err == nil {
+			t.Error("Old food should be despawned")
+		}
+		if _, err := ctx.Database.GetEntity(newFood.EntityID); err != nil {
+			t.Errorf("New food should be kept: %v", err)
+		}
+		if count, _ := ctx.Database.GetFoodCount(); count != 2 {
+			t.Errorf("Stale food should be replaced to keep 2 food, got %d", count)
+		}
+	})
+}
+
+func TestSamePlayerCirclesNeverConsume(t *testing.T) {
+	ctx := createTestWorld(t, 1000)
+	ctx.Timestamp = tables.NewTimestamp(1_000_000)
+
+	// Two heavily overlapping circles of player 1 with a large enough mass gap to consume
+	var entities []*tables.Entity
+	var circles []*tables.Circle
+	for _, mass := range []uint32{100, 20} {
+		entity := insertTestEntity(t, ctx.Database, 100, 100, mass)
+		circle := tables.NewCircle(entity.EntityID, 1, types.Up(), 0, tables.Timestamp{})
+		if err := ctx.Database.InsertCircle(circle); err != nil {
+			t.Fatalf("InsertCircle failed: %v", err)
+		}
+		entities = append(entities, entity)
+		circles = append(circles, circle)
+	}
+	entityMap := map[uint32]*tables.Entity{
+		entities[0].EntityID: entities[0],
+		entities[1].EntityID: entities[1],
+	}
+
+	if !logic.CanConsume(entities[0], entities[1], logic.DefaultOverlapMode) {
+		t.Fatal("Test circles should satisfy the consume check if they belonged to different players")
+	}
+
+	if checks := runCollisionPass(ctx, circles, entities, entityMap); checks != 0 {
+		t.Errorf("Same-player pairs should be skipped before any collision check, got %d checks", checks)
+	}
+	if fired := ctx.Database.RunDueTimers(ctx.Timestamp); fired != 0 {
+		t.Errorf("No consume should be scheduled between same-player circles, fired %d", fired)
+	}
+	for _, entity := range entities {
+		if _, err := ctx.Database.GetEntity(entity.EntityID); err != nil {
+			t.Errorf("Circle entity %d should survive: %v", entity.EntityID, err)
+		}
+	}
+}
+
+func TestPlayerSplitCircleCap(t *testing.T) {
+	withConfig(t, func(config *constants.Configuration) {
+		config.MaxCirclesPerPlayer = 3
+	})
+
+	ctx := createTestWorld(t, 1000)
+	if result := ConnectReducer(ctx, []byte{}); !result.IsSuccess() {
+		t.Fatalf("ConnectReducer failed: %s", result.Error())
+	}
+	player, _ := ctx.Database.GetPlayer(ctx.Sender)
+
+	// Two heavy circles that could each split, but only one split fits under the cap
+	for _, x := range []float32{200, 600} {
+		entity := insertTestEntity(t, ctx.Database, x, 500, 1000)
+		if err := ctx.Database.InsertCircle(tables.NewCircle(entity.EntityID, player.PlayerID, types.Up(), 0, tables.Timestamp{})); err != nil {
+			t.Fatalf("InsertCircle failed: %v", err)
+		}
+	}
+
+	countCircles := func() uint32 {
+		t.Helper()
+		count, err := ctx.Database.GetCircleCountByPlayer(player.PlayerID)
+		if err != nil {
+			t.Fatalf("GetCircleCountByPlayer failed: %v", err)
+		}
+		return count
+	}
+
+	if result := PlayerSplitReducer(ctx, []byte{}); !result.IsSuccess() {
+		t.Fatalf("PlayerSplitReducer failed: %s", result.Error())
+	}
+	if count := countCircles(); count != 3 {
+		t.Errorf("Split below the cap should stop at 3 circles, got %d", count)
+	}
+
+	if result := PlayerSplitReducer(ctx, []byte{}); !result.IsSuccess() {
+		t.Fatalf("PlayerSplitReducer at the cap should succeed as a no-op: %s", result.Error())
+	}
+	if count := countCircles(); count != 3 {
+		t.Errorf("Split at the cap should not add circles, got %d", count)
+	}
+}
+
+func TestPlayerSplitMassScaledCap(t *testing.T) {
+	// Two circles of 1000 mass split as far as the mass-scaled cap allows
+	splitCount := func(t *testing.T, massPerCircle uint32) uint32 {
+		withConfig(t, func(config *constants.Configuration) {
+			config.CircleCapModel = constants.CircleCapMassScaled
+			config.CircleCapMassPerCircle = massPerCircle
+		})
+
+		ctx := createTestWorld(t, 1000)
+		if result := ConnectReducer(ctx, []byte{}); !result.IsSuccess() {
+			t.Fatalf("ConnectReducer failed: %s", result.Error())
+		}
+		player, _ := ctx.Database.GetPlayer(ctx.Sender)
+		for _, x := range []float32{200, 600} {
+			entity := insertTestEntity(t, ctx.Database, x, 500, 1000)
+			if err := ctx.Database.InsertCircle(tables.NewCircle(entity.EntityID, player.PlayerID, types.Up(), 0, tables.Timestamp{})); err != nil {
+				t.Fatalf("InsertCircle failed: %v", err)
+			}
+		}
+
+		if result := PlayerSplitReducer(ctx, []byte{}); !result.IsSuccess() {
+			t.Fatalf("PlayerSplitReducer failed: %s", result.Error())
+		}
+		count, err := ctx.Database.GetCircleCountByPlayer(player.PlayerID)
+		if err != nil {
+			t.Fatalf("GetCircleCountByPlayer failed: %v", err)
+		}
+		return count
+	}
+
+	if count := splitCount(t, 1000); count != 2 {
+		t.Errorf("2000 total mass at 1000 per circle should not split past 2 circles, got %d", count)
+	}
+	if count := splitCount(t, 500); count != 4 {
+		t.Errorf("2000 total mass at 500 per circle should split to 4 circles, got %d", count)
+	}
+}
+
+func TestSpawnProtection(t *testing.T) {
+	withConfig(t, func(config *constants.Configuration) {
+		config.SpawnProtectionSec = 2
+	})
+
+	ctx := createTestWorld(t, 1000)
+	ctx.Timestamp = tables.NewTimestamp(10_000_000)
+
+	// A fresh spawn next to a much larger enemy circle
+	fresh := insertTestEntity(t, ctx.Database, 500, 500, constants.START_PLAYER_MASS)
+	freshCircle := tables.NewCircle(fresh.EntityID, 1, types.Up(), 0, ctx.Timestamp)
+	logic.ProtectSpawnedCircle(freshCircle, ctx.Timestamp)
+	hunter := insertTestEntity(t, ctx.Database, 500, 500, 200)
+	hunterCircle := tables.NewCircle(hunter.EntityID, 2, types.Up(), 0, tables.Timestamp{})
+	for _, circle := range []*tables.Circle{freshCircle, hunterCircle} {
+		if err := ctx.Database.InsertCircle(circle); err != nil {
+			t.Fatalf("InsertCircle failed: %v", err)
+		}
+	}
+
+	// The protected circle also can't eat food
+	food := insertTestEntity(t, ctx.Database, 500, 500, 2)
+	if err := ctx.Database.InsertFood(tables.NewFood(food.EntityID)); err != nil {
+		t.Fatalf("InsertFood failed: %v", err)
+	}
+
+	runPass := func(now tables.Timestamp) {
+		t.Helper()
+		passCtx := &ReducerContext{Timestamp: now, Database: ctx.Database}
+		circles, _ := ctx.Database.GetAllCircles()
+		entities, _ := ctx.Database.GetAllEntities()
+		entityMap := make(map[uint32]*tables.Entity)
+		for _, entity := range entities {
+			entityMap[entity.EntityID] = entity
+		}
+		runCollisionPass(passCtx, circles, entities, entityMap)
+		ctx.Database.RunDueTimers(now)
+	}
+
+	runPass(ctx.Timestamp.Add(tables.NewTimeDurationFromDuration(time.Second)))
+	if entity, err := ctx.Database.GetEntity(fresh.EntityID); err != nil {
+		t.Fatal("Protected circle should not be consumed during protection")
+	} else if entity.Mass != constants.START_PLAYER_MASS {
+		t.Errorf("Protected circle should not consume during protection, mass %d", entity.Mass)
+	}
+
+	runPass(ctx.Timestamp.Add(tables.NewTimeDurationFromDuration(3 * time.Second)))
+	if _, err := ctx.Database.GetEntity(fresh.EntityID); err == nil {
+		t.Error("Circle should become edible once protection expires")
+	}
+}
+
+func TestTeamMerge(t *testing.T) {
+	// run puts a 200 mass circle of team 5 on top of another player's circle and
+	// reports whether the other circle survives a collision pass
+	run := func(t *testing.T, mergeAllowed bool, otherTeam, otherMass uint32) (bool, uint32) {
+		t.Helper()
+		withConfig(t, func(config *constants.Configuration) {
+			config.TeamMergeAllowed = mergeAllowed
+		})
+
+		ctx := createTestWorld(t, 1000)
+		spawn := func(sender byte, team uint32, x float32, mass uint32) *tables.Entity {
+			player := tables.NewPlayer(tables.NewIdentity([16]byte{sender}), 0, "Player")
+			player.TeamID = team
+			if err := ctx.Database.InsertPlayer(player); err != nil {
+				t.Fatalf("InsertPlayer failed: %v", err)
+			}
+			entity := insertTestEntity(t, ctx.Database, x, 500, mass)
+			if err := ctx.Database.InsertCircle(tables.NewCircle(entity.EntityID, player.PlayerID, types.Up(), 0, tables.Timestamp{})); err != nil {
+				t.Fatalf("InsertCircle failed: %v", err)
+			}
+			return entity
+		}
+		big := spawn(1, 5, 500, 200)
+		other := spawn(2, otherTeam, 505, otherMass)
+
+		circles, _ := ctx.Database.GetAllCircles()
+		entities, _ := ctx.Database.GetAllEntities()
+		entityMap := make(map[uint32]*tables.Entity)
+		for _, entity := range entities {
+			entityMap[entity.EntityID] = entity
+		}
+		runCollisionPass(ctx, circles, entities, entityMap)
+		ctx.Database.RunDueTimers(ctx.Timestamp.Add(tables.NewTimeDurationFromDuration(time.Second)))
+
+		_, err := ctx.Database.GetEntity(other.EntityID)
+		bigAfter, _ := ctx.Database.GetEntity(big.EntityID)
+		return err == nil, bigAfter.Mass
+	}
+
+	t.Run("Enemy consumed", func(t *testing.T) {
+		if survived, mass := run(t, false, 6, 100); survived || mass != 300 {
+			t.Errorf("Enemy circle should be consumed normally (survived %v, mass %d)", survived, mass)
+		}
+	})
+
+	t.Run("Teammate protected when merging is off", func(t *testing.T) {
+		if survived, mass := run(t, false, 5, 100); !survived || mass != 200 {
+			t.Errorf("Teammate circle should not be eaten (survived %v, mass %d)", survived, mass)
+		}
+	})
+
+	t.Run("Teammate merged when allowed", func(t *testing.T) {
+		// 190 is too close in mass for an enemy consume, but teammates merge regardless
+		if survived, mass := run(t, true, 5, 190); survived || mass != 390 {
+			t.Errorf("Teammate circle should merge (survived %v, mass %d)", survived, mass)
+		}
+	})
+}
+
+func TestResolveCircleOverlaps(t *testing.T) {
+	setup := func(t *testing.T) (*ReducerContext, []*tables.Circle, []*tables.Entity, map[uint32]*tables.Entity) {
+		ctx := createTestWorld(t, 1000)
+		var entities []*tables.Entity
+		var circles []*tables.Circle
+		for i, x := range []float32{100, 112} {
+			entity := insertTestEntity(t, ctx.Database, x, 100, 100)
+			circle := tables.NewCircle(entity.EntityID, uint32(i+1), types.Up(), 0, tables.Timestamp{})
+			if err := ctx.Database.InsertCircle(circle); err != nil {
+				t.Fatalf("InsertCircle failed: %v", err)
+			}
+			entities = append(entities, entity)
+			circles = append(circles, circle)
+		}
+		entityMap := map[uint32]*tables.Entity{
+			entities[0].EntityID: entities[0],
+			entities[1].EntityID: entities[1],
+		}
+		return ctx, circles, entities, entityMap
+	}
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		ctx, circles, entities, entityMap := setup(t)
+		runCollisionPass(ctx, circles, entities, entityMap)
+
+		stored, _ := ctx.Database.GetEntity(entities[0].EntityID)
+		if stored.Position.X != 100 {
+			t.Errorf("Circles should not be repositioned without the flag, got X %f", stored.Position.X)
+		}
+	})
+
+	t.Run("Enabled pushes enemy circles apart", func(t *testing.T) {
+		withConfig(t, func(config *constants.Configuration) {
+			config.ResolveCircleOverlaps = true
+		})
+
+		ctx, circles, entities, entityMap := setup(t)
+		runCollisionPass(ctx, circles, entities, entityMap)
+
+		a, _ := ctx.Database.GetEntity(entities[0].EntityID)
+		b, _ := ctx.Database.GetEntity(entities[1].EntityID)
+		if distance := a.Position.Distance(b.Position); distance < 19.999 {
+			t.Errorf("Equal-mass circles should be pushed to touching distance 20, got %f", distance)
+		}
+		if fired := ctx.Database.RunDueTimers(ctx.Timestamp); fired != 0 {
+			t.Errorf("No consume should be scheduled between evenly matched circles, fired %d", fired)
+		}
+	})
+}
+
+func TestPhysicsTickRate(t *testing.T) {
+	run := func(t *testing.T, hz uint32, ticks int) (uint64, types.DbVector2) {
+		t.Helper()
+		withConfig(t, func(config *constants.Configuration) {
+			config.PhysicsTickHz = hz
+		})
+		physicsClockMu.Lock()
+		physicsClock = nil
+		physicsClockMu.Unlock()
+		ResetMetrics()
+
+		ctx := createTestWorld(t, 1000)
+		if err := ctx.Database.InsertPlayer(createTestPlayer()); err != nil {
+			t.Fatalf("InsertPlayer failed: %v", err)
+		}
+		entity := insertTestEntity(t, ctx.Database, 500, 500, 100)
+		if err := ctx.Database.InsertCircle(tables.NewCircle(entity.EntityID, 1, types.Right(), 1.0, tables.Timestamp{})); err != nil {
+			t.Fatalf("InsertCircle failed: %v", err)
+		}
+		for i := 0; i < ticks; i++ {
+			if result := MoveAllPlayersReducer(ctx, []byte{}); !result.IsSuccess() {
+				t.Fatalf("MoveAllPlayersReducer failed: %s", result.Error())
+			}
+		}
+		moved, _ := ctx.Database.GetEntity(entity.EntityID)
+		return GetCounter(MetricPhysicsSubsteps), moved.Position
+	}
+
+	baseSteps, basePosition := run(t, 0, 2)
+	if baseSteps != 2 {
+		t.Errorf("Default tick rate should run one substep per tick, got %d over 2 ticks", baseSteps)
+	}
+
+	fineSteps, finePosition := run(t, 60, 2)
+	if fineSteps != 6 {
+		t.Errorf("60 Hz should run three substeps per tick, got %d over 2 ticks", fineSteps)
+	}
+	if basePosition.Distance(finePosition) > 0.01 {
+		t.Errorf("Substepping should cover the same distance: %v vs %v", basePosition, finePosition)
+	}
+
+	if steps, _ := run(t, 30, 2); steps != 3 {
+		t.Errorf("30 Hz should carry the remainder and run 3 substeps over 2 ticks, got %d", steps)
+	}
+}
+
+func TestFoodMagnet(t *testing.T) {
+	withConfig(t, func(config *constants.Configuration) {
+		config.FoodMagnetMinMass = 400
+		config.FoodMagnetRadius = 50
+		config.FoodMagnetStrength = 20
+		config.MovePlayersInterval = 100 * time.Millisecond
+	})
+
+	ctx := createTestWorld(t, 1000)
+	if err := ctx.Database.InsertPlayer(createTestPlayer()); err != nil {
+		t.Fatalf("InsertPlayer failed: %v", err)
+	}
+	magnet := insertTestEntity(t, ctx.Database, 500, 500, 400)
+	if err := ctx.Database.InsertCircle(tables.NewCircle(magnet.EntityID, 1, types.Up(), 0, tables.Timestamp{})); err != nil {
+		t.Fatalf("InsertCircle failed: %v", err)
+	}
+
+	var food []*tables.Entity
+	for _, x := range []float32{540, 600} {
+		entity := insertTestEntity(t, ctx.Database, x, 500, 2)
+		if err := ctx.Database.InsertFood(tables.NewFood(entity.EntityID)); err != nil {
+			t.Fatalf("InsertFood failed: %v", err)
+		}
+		food = append(food, entity)
+	}
+
+	if result := MoveAllPlayersReducer(ctx, []byte{}); !result.IsSuccess() {
+		t.Fatalf("MoveAllPlayersReducer failed: %s", result.Error())
+	}
+
+	near, _ := ctx.Database.GetEntity(food[0].EntityID)
+	// Strength 20 at 40 of 50 units away pulls 20 * 0.2 = 4 units/s, over one 100ms tick
+	if drift := 540 - near.Position.X; math.Abs(float64(drift)-0.4) > 0.01 {
+		t.Errorf("Food within range should drift 0.4 units over the tick length, drifted %f", drift)
+	}
+	far, _ := ctx.Database.GetEntity(food[1].EntityID)
+	if far.Position.X != 600 {
+		t.Errorf("Food outside range should not move, now at X %f", far.Position.X)
+	}
+}
+
+func TestDeterministicConsumeScheduling(t *testing.T) {
+	t.Run("selectConsumePairs", func(t *testing.T) {
+		want := []consumePair{{1, 2}, {3, 5}}
+		for _, pairs := range [][]consumePair{
+			{{1, 2}, {1, 3}, {2, 3}, {3, 5}, {4, 5}},
+			{{4, 5}, {2, 3}, {3, 5}, {1, 3}, {1, 2}},
+		} {
+			got := selectConsumePairs(pairs)
+			if len(got) != len(want) {
+				t.Fatalf("Expected %v, got %v", want, got)
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Errorf("Expected %v, got %v", want, got)
+				}
+			}
+		}
+	})
+
+	t.Run("Chain A>B>C resolves the same way every time", func(t *testing.T) {
+		for _, order := range [][]int{{0, 1, 2}, {2, 1, 0}, {1, 2, 0}} {
+			ctx := createTestWorld(t, 1000)
+			ctx.Timestamp = tables.NewTimestamp(1_000_000)
+
+			// A can eat B and C, and B can eat C; all three are stacked on top of each other
+			var entities []*tables.Entity
+			var circles []*tables.Circle
+			for i, mass := range []uint32{400, 200, 100} {
+				entity := insertTestEntity(t, ctx.Database, 500, 500, mass)
+				circle := tables.NewCircle(entity.EntityID, uint32(i+1), types.Up(), 0, tables.Timestamp{})
+				if err := ctx.Database.InsertCircle(circle); err != nil {
+					t.Fatalf("InsertCircle failed: %v", err)
+				}
+				entities = append(entities, entity)
+				circles = append(circles, circle)
+			}
+			entityMap := make(map[uint32]*tables.Entity)
+			for _, entity := range entities {
+				entityMap[entity.EntityID] = entity
+			}
+
+			shuffledCircles := make([]*tables.Circle, len(order))
+			for i, idx := range order {
+				shuffledCircles[i] = circles[idx]
+			}
+			runCollisionPass(ctx, shuffledCircles, entities, entityMap)
+
+			if fired := ctx.Database.RunDueTimers(ctx.Timestamp); fired != 1 {
+				t.Errorf("Order %v: expected exactly one consume, fired %d", order, fired)
+			}
+			a, err := ctx.Database.GetEntity(entities[0].EntityID)
+			if err != nil || a.Mass != 600 {
+				t.Errorf("Order %v: A should have eaten B and reached mass 600: %+v, %v", order, a, err)
+			}
+			if _, err := ctx.Database.GetEntity(entities[1].EntityID); err == nil {
+				t.Errorf("Order %v: B should have been consumed", order)
+			}
+			if c, err := ctx.Database.GetEntity(entities[2].EntityID); err != nil || c.Mass != 100 {
+				t.Errorf("Order %v: C should survive this tick untouched: %+v, %v", order, c, err)
+			}
+		}
+	})
+}
+
+func TestCollisionBudget(t *testing.T) {
+	config := withConfig(t, func(config *constants.Configuration) {
+		config.MaxCollisionChecksPerTick = 10
+	})
+
+	// A pile-up of 8 circles from different players, all on top of each other
+	db := &DatabaseContext{}
+	var entities []*tables.Entity
+	var circles []*tables.Circle
+	for i := 0; i < 8; i++ {
+		entity := insertTestEntity(t, db, 100, 100, uint32(20+i*10))
+		circle := tables.NewCircle(entity.EntityID, uint32(i+1), types.Up(), 0, tables.Timestamp{})
+		if err := db.InsertCircle(circle); err != nil {
+			t.Fatalf("InsertCircle failed: %v", err)
+		}
+		entities = append(entities, entity)
+		circles = append(circles, circle)
+	}
+	entityMap := make(map[uint32]*tables.Entity)
+	for _, entity := range entities {
+		entityMap[entity.EntityID] = entity
+	}
+	ctx := createTestContext()
+	ctx.Database = db
+
+	t.Run("Budget respected", func(t *testing.T) {
+		ResetMetrics()
+
+		checks := runCollisionPass(ctx, circles, entities, entityMap)
+		if checks != 10 {
+			t.Errorf("Expected exactly 10 checks, got %d", checks)
+		}
+		if got := GetCounter(MetricCollisionChecks); got != 10 {
+			t.Errorf("Expected collision_checks counter 10, got %d", got)
+		}
+		if got := GetCounter(MetricCollisionBudgetOverflows); got != 1 {
+			t.Errorf("Expected 1 budget overflow, got %d", got)
+		}
+	})
+
+	t.Run("Largest circle checked first, backlog resumes next tick", func(t *testing.T) {
+		collisionBacklogMu.Lock()
+		backlog := collisionBacklog
+		collisionBacklogMu.Unlock()
+
+		largest := entities[len(entities)-1].EntityID
+		if backlog[largest] {
+			t.Error("Largest circle should have been fully checked in the first tick")
+		}
+		if len(backlog) != len(circles)-1 {
+			t.Errorf("Expected %d deferred circles, got %d", len(circles)-1, len(backlog))
+		}
+
+		runCollisionPass(ctx, circles, entities, entityMap)
+
+		collisionBacklogMu.Lock()
+		next := collisionBacklog
+		collisionBacklogMu.Unlock()
+		if !next[largest] {
+			t.Error("Previously checked circle should wait behind the backlog")
+		}
+		if GetCounter(MetricCollisionBudgetOverflows) != 2 {
+			t.Errorf("Expected overflow counter to increment again, got %d", GetCounter(MetricCollisionBudgetOverflows))
+		}
+	})
+
+	t.Run("Backlog lost on restart", func(t *testing.T) {
+		// A restarted module starts with an empty backlog
+		collisionBacklogMu.Lock()
+		collisionBacklog = make(map[uint32]bool)
+		collisionBacklogMu.Unlock()
+
+		if checks := runCollisionPass(ctx, circles, entities, entityMap); checks != 10 {
+			t.Errorf("Expected the budget of 10 checks after a restart, got %d", checks)
+		}
+		collisionBacklogMu.Lock()
+		backlog := collisionBacklog
+		collisionBacklogMu.Unlock()
+		if largest := entities[len(entities)-1].EntityID; backlog[largest] {
+			t.Error("Without a backlog the largest circle should be checked first again")
+		}
+		if len(backlog) != len(circles)-1 {
+			t.Errorf("Expected the backlog to be rebuilt with %d deferred circles, got %d", len(circles)-1, len(backlog))
+		}
+	})
+
+	t.Run("No overflow within budget", func(t *testing.T) {
+		config.MaxCollisionChecksPerTick = 1000
+		ResetMetrics()
+
+		checks := runCollisionPass(ctx, circles, entities, entityMap)
+		if checks != len(circles)*(len(entities)-1) {
+			t.Errorf("Expected every pair to be checked, got %d checks", checks)
+		}
+		if GetCounter(MetricCollisionBudgetOverflows) != 0 {
+			t.Error("Should not record an overflow when the budget suffices")
+		}
+	})
+}
+
+func TestInitialFoodBurst(t *testing.T) {
+	config := withConfig(t, func(config *constants.Configuration) {
+		config.InitialFoodBurst = 50
+	})
+
+	ctx := createTestWorld(t, 1000)
+	enterGame := func(identity tables.Identity) {
+		t.Helper()
+		playerCtx := &ReducerContext{Sender: identity, Timestamp: ctx.Timestamp, Database: ctx.Database}
+		if result := ConnectReducer(playerCtx, []byte{}); !result.IsSuccess() {
+			t.Fatalf("ConnectReducer failed: %s", result.Error())
+		}
+		argsData, _ := MarshalArgs(EnterGameArgs{Name: "Player"})
+		if result := EnterGameReducer(playerCtx, argsData); !result.IsSuccess() {
+			t.Fatalf("EnterGameReducer failed: %s", result.Error())
+		}
+	}
+
+	enterGame(tables.NewIdentity([16]byte{1}))
+	if count, _ := ctx.Database.GetFoodCount(); count != 50 {
+		t.Errorf("First player should trigger a burst of 50 food, got %d", count)
+	}
+
+	// Eat one food so the world isn't at the burst size, then join again
+	foodEntities, _ := ctx.Database.GetAllEntities()
+	for _, entity := range foodEntities {
+		if isFood, _ := ctx.Database.IsFood(entity.EntityID); isFood {
+			ctx.Database.DestroyEntity(entity.EntityID)
+			break
+		}
+	}
+
+	enterGame(tables.NewIdentity([16]byte{2}))
+	if count, _ := ctx.Database.GetFoodCount(); count != 49 {
+		t.Errorf("Subsequent joins should not re-burst, got %d food", count)
+	}
+
+	// A world emptied of food while players are in it isn't a fresh arena
+	entities, _ := ctx.Database.GetAllEntities()
+	for _, entity := range entities {
+		if isFood, _ := ctx.Database.IsFood(entity.EntityID); isFood {
+			ctx.Database.DestroyEntity(entity.EntityID)
+		}
+	}
+	enterGame(tables.NewIdentity([16]byte{3}))
+	if count, _ := ctx.Database.GetFoodCount(); count != 0 {
+		t.Errorf("Only the first player into the world should trigger a burst, got %d food", count)
+	}
+
+	// The burst never exceeds the food target
+	withConfig(t, func(capped *constants.Configuration) {
+		*capped = *config
+		capped.InitialFoodBurst = 500
+		capped.TargetFoodCount = 20
+	})
+	ctx = createTestWorld(t, 1000)
+	enterGame(tables.NewIdentity([16]byte{1}))
+	if count, _ := ctx.Database.GetFoodCount(); count != 20 {
+		t.Errorf("Burst should be capped at the food target of 20, got %d", count)
+	}
+}
+
+func TestFoodPerPlayer(t *testing.T) {
+	withConfig(t, func(config *constants.Configuration) {
+		config.TargetFoodCount = 10
+		config.FoodPerPlayer = 4
+	})
+
+	ctx := createTestWorld(t, 1000)
+	connectAndSpawn := func(players int) uint64 {
+		t.Helper()
+		for count, _ := ctx.Database.GetPlayerCount(); count < uint64(players); count++ {
+			identity := tables.NewIdentity([16]byte{byte(count + 1)})
+			if result := ConnectReducer(&ReducerContext{Sender: identity, Timestamp: ctx.Timestamp, Database: ctx.Database}, []byte{}); !result.IsSuccess() {
+				t.Fatalf("ConnectReducer failed: %s", result.Error())
+			}
+		}
+		if result := SpawnFoodReducer(ctx, []byte{}); !result.IsSuccess() {
+			t.Fatalf("SpawnFoodReducer failed: %s", result.Error())
+		}
+		count, _ := ctx.Database.GetFoodCount()
+		return count
+	}
+
+	if food := connectAndSpawn(1); food != 10 {
+		t.Errorf("A lone player should get the base target of 10 food, got %d", food)
+	}
+	if food := connectAndSpawn(5); food != 20 {
+		t.Errorf("Five players should raise the target to 20 food, got %d", food)
+	}
+}
+
+func TestInputWithoutCircles(t *testing.T) {
+	ResetMetrics()
+
+	db := &DatabaseContext{}
+	player := tables.NewPlayer(tables.NewIdentity([16]byte{1}), 0, "Ghost")
+	if err := db.InsertPlayer(player); err != nil {
+		t.Fatalf("InsertPlayer failed: %v", err)
+	}
+	ctx := &ReducerContext{Sender: player.Identity, Timestamp: Now(), Database: db}
+	inputArgs, _ := MarshalArgs(UpdatePlayerInputArgs{Direction: types.NewDbVector2(1, 0)})
+
+	t.Run("Ignored by default", func(t *testing.T) {
+		if result := UpdatePlayerInputReducer(ctx, inputArgs); !result.IsSuccess() {
+			t.Fatalf("Input without circles should be ignored, got %s", result.Error())
+		}
+		if got := GetCounter(MetricIgnoredInputs); got != 1 {
+			t.Errorf("Expected 1 ignored input, got %d", got)
+		}
+		if stored, _ := db.GetPlayer(player.Identity); stored.LastSeen != ctx.Timestamp {
+			t.Error("Ignored input should still count as the player being seen")
+		}
+	})
+
+	t.Run("Rejected when configured", func(t *testing.T) {
+		withConfig(t, func(config *constants.Configuration) {
+			config.RejectInputWithoutCircles = true
+		})
+
+		result := UpdatePlayerInputReducer(ctx, inputArgs)
+		if result.IsSuccess() {
+			t.Fatal("Input without circles should be rejected")
+		}
+		if !strings.Contains(result.Error(), ErrorCodeInvalidState) {
+			t.Errorf("Expected %s, got %s", ErrorCodeInvalidState, result.Error())
+		}
+		if got := GetCounter(MetricIgnoredInputs); got != 2 {
+			t.Errorf("Expected 2 ignored inputs, got %d", got)
+		}
+	})
+}
+
+func TestInputClockDrift(t *testing.T) {
+	withConfig(t, func(config *constants.Configuration) {
+		config.MaxInputClockDrift = 5 * time.Second
+	})
+	ResetMetrics()
+
+	db := &DatabaseContext{}
+	player := tables.NewPlayer(tables.NewIdentity([16]byte{1}), 0, "Player")
+	if err := db.InsertPlayer(player); err != nil {
+		t.Fatalf("InsertPlayer failed: %v", err)
+	}
+	entity := insertTestEntity(t, db, 100, 100, 100)
+	if err := db.InsertCircle(tables.NewCircle(entity.EntityID, player.PlayerID, types.Up(), 0, tables.Timestamp{})); err != nil {
+		t.Fatalf("InsertCircle failed: %v", err)
+	}
+
+	inputArgs, _ := MarshalArgs(UpdatePlayerInputArgs{Direction: types.NewDbVector2(1, 0)})
+	ctxAt := func(when time.Time) *ReducerContext {
+		return &ReducerContext{Sender: player.Identity, Timestamp: tables.NewTimestampFromTime(when), Database: db}
+	}
+
+	t.Run("Future timestamp is rejected", func(t *testing.T) {
+		future := time.Now().Add(24 * time.Hour)
+		if result := UpdatePlayerInputReducer(ctxAt(future), inputArgs); result.IsSuccess() {
+			t.Fatal("Input from the far future should be rejected")
+		}
+		if result := PlayerSplitReducer(ctxAt(future), []byte{}); result.IsSuccess() {
+			t.Error("Split from the far future should be rejected")
+		}
+
+		circle, _ := db.GetCircle(entity.EntityID)
+		if circle.Speed != 0 {
+			t.Errorf("Rejected input should not change the circle, got speed %f", circle.Speed)
+		}
+		stored, _ := db.GetPlayer(player.Identity)
+		if stored.LastSeen.Microseconds != player.LastSeen.Microseconds {
+			t.Error("Rejected input should not update last seen")
+		}
+		if got := GetCounter(MetricRejectedInputs); got != 2 {
+			t.Errorf("Expected 2 rejected inputs, got %d", got)
+		}
+	})
+
+	t.Run("Current timestamp is accepted", func(t *testing.T) {
+		if result := UpdatePlayerInputReducer(ctxAt(time.Now()), inputArgs); !result.IsSuccess() {
+			t.Fatalf("UpdatePlayerInputReducer failed: %s", result.Error())
+		}
+		circle, _ := db.GetCircle(entity.EntityID)
+		if circle.Speed != 1 {
+			t.Errorf("Accepted input should set speed 1, got %f", circle.Speed)
+		}
+	})
+}
+
+func TestDecayLeaderExemption(t *testing.T) {
+	setup := func(t *testing.T) (*ReducerContext, *tables.Entity, *tables.Entity) {
+		ctx := createTestWorld(t, 1000)
+		if err := ctx.Database.InsertPlayer(createTestPlayer()); err != nil {
+			t.Fatalf("InsertPlayer failed: %v", err)
+		}
+		leader := insertTestEntity(t, ctx.Database, 100, 100, 1000)
+		small := insertTestEntity(t, ctx.Database, 500, 500, 100)
+		for playerID, entity := range map[uint32]*tables.Entity{1: leader, 2: small} {
+			if err := ctx.Database.InsertCircle(tables.NewCircle(entity.EntityID, playerID, types.Up(), 0, tables.Timestamp{})); err != nil {
+				t.Fatalf("InsertCircle failed: %v", err)
+			}
+		}
+		return ctx, leader, small
+	}
+	decay := func(t *testing.T, ctx *ReducerContext, leader, small *tables.Entity) (uint32, uint32) {
+		if result := CircleDecayReducer(ctx, []byte{}); !result.IsSuccess() {
+			t.Fatalf("CircleDecayReducer failed: %s", result.Error())
+		}
+		leaderAfter, _ := ctx.Database.GetEntity(leader.EntityID)
+		smallAfter, _ := ctx.Database.GetEntity(small.EntityID)
+		return leaderAfter.Mass, smallAfter.Mass
+	}
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		ctx, leader, small := setup(t)
+		leaderMass, smallMass := decay(t, ctx, leader, small)
+		if leaderMass >= leader.Mass || smallMass >= small.Mass {
+			t.Errorf("Both circles should decay by default, got %d and %d", leaderMass, smallMass)
+		}
+	})
+
+	t.Run("Small circle exempt", func(t *testing.T) {
+		withConfig(t, func(config *constants.Configuration) {
+			config.DecayExemptLeaderFraction = 0.5
+		})
+
+		ctx, leader, small := setup(t)
+		leaderMass, smallMass := decay(t, ctx, leader, small)
+		if leaderMass >= leader.Mass {
+			t.Errorf("Leader should decay, mass stayed at %d", leaderMass)
+		}
+		if smallMass != small.Mass {
+			t.Errorf("Small circle should be exempt from decay, mass went from %d to %d", small.Mass, smallMass)
+		}
+	})
+}
+
+func TestExportPrometheus(t *testing.T) {
+	ResetMetrics()
+	defer ResetMetrics()
+
+	ctx := createTestWorld(t, 1000)
+	if err := ctx.Database.InsertPlayer(createTestPlayer()); err != nil {
+		t.Fatalf("InsertPlayer failed: %v", err)
+	}
+	insertTestEntity(t, ctx.Database, 100, 100, 30)
+	insertTestEntity(t, ctx.Database, 200, 200, 20)
+
+	status, _ := globalRegistry.GetByName("Status")
+	for i := 0; i < 3; i++ {
+		if result := status.Invoke(ctx, []byte{}); !result.IsSuccess() {
+			t.Fatalf("Status failed: %s", result.Error())
+		}
+	}
+	IncrementCounter(MetricPlayerDeaths, 2)
+	if result := ExportMetricsReducer(ctx, []byte{}); !result.IsSuccess() {
+		t.Fatalf("ExportMetricsReducer failed: %s", result.Error())
+	}
+	output := string(ExportPrometheus())
+
+	for _, want := range []string{
+		`blackholio_reducer_calls_total{reducer="Status"} 3`,
+		`blackholio_reducer_duration_seconds{reducer="Status",quantile="0.99"} `,
+		`blackholio_reducer_duration_seconds_count{reducer="Status"} 3`,
+		"# TYPE blackholio_reducer_duration_seconds summary",
+		"blackholio_player_deaths_total 2",
+		"blackholio_players 1",
+		"blackholio_entities 2",
+		"blackholio_total_mass 50",
+		"# TYPE blackholio_food gauge",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Export should contain %q, got:\n%s", want, output)
+		}
+	}
+
+	// Every line is a comment or a "name{labels} value" sample
+	for _, line := range strings.Split(strings.TrimSuffix(output, "\n"), "\n") {
+		if strings.HasPrefix(line, "# HELP ") || strings.HasPrefix(line, "# TYPE ") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, " ")
+		if !ok || !strings.HasPrefix(name, "blackholio_") || strings.ContainsAny(value, " \t") {
+			t.Errorf("Malformed sample line %q", line)
+			continue
+		}
+		if strings.Contains(name, "{") && !strings.HasSuffix(name, "}") {
+			t.Errorf("Unterminated labels in %q", line)
+		}
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			t.Errorf("Sample value in %q is not a number: %v", line, err)
+		}
+	}
+}
+
+func TestDurationQuantile(t *testing.T) {
+	sorted := []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	for q, want := range map[float64]time.Duration{0.5: 5, 0.9: 9, 0.99: 10} {
+		if got := durationQuantile(sorted, q); got != want {
+			t.Errorf("Quantile %v: expected %v, got %v", q, want, got)
+		}
+	}
+	if got := durationQuantile(nil, 0.5); got != 0 {
+		t.Errorf("Quantile of no samples should be 0, got %v", got)
+	}
+}
+
+func TestCampingPenalty(t *testing.T) {
+	withConfig(t, func(config *constants.Configuration) {
+		config.CampingThresholdSec = 5
+		config.CampingDecayMultiplier = 3
+	})
+
+	ctx := createTestWorld(t, 1000)
+	if err := ctx.Database.InsertPlayer(createTestPlayer()); err != nil {
+		t.Fatalf("InsertPlayer failed: %v", err)
+	}
+	// Each circle has its own player so split physics doesn't pull them together
+	insertCircle := func(playerID uint32, x, y float32, speed float32, spawnedAt tables.Timestamp) *tables.Entity {
+		entity := insertTestEntity(t, ctx.Database, x, y, 1000)
+		circle := tables.NewCircle(entity.EntityID, playerID, types.Right(), speed, spawnedAt)
+		circle.SpawnedAt = spawnedAt
+		circle.LastMovedAt = spawnedAt
+		if err := ctx.Database.InsertCircle(circle); err != nil {
+			t.Fatalf("InsertCircle failed: %v", err)
+		}
+		return entity
+	}
+	// Both spawned long before the threshold; only one of them keeps moving
+	stationary := insertCircle(1, 200, 200, 0, tables.Timestamp{})
+	moving := insertCircle(2, 200, 700, 1, tables.Timestamp{})
+	// Still for less than the threshold since it spawned
+	fresh := insertCircle(3, 700, 200, 0, ctx.Timestamp)
+
+	for _, reducer := range []func(*ReducerContext, []byte) ReducerResult{MoveAllPlayersReducer, CircleDecayReducer} {
+		if result := reducer(ctx, []byte{}); !result.IsSuccess() {
+			t.Fatalf("Reducer failed: %s", result.Error())
+		}
+	}
+
+	// Movement is recorded on the circle row
+	if circle, _ := ctx.Database.GetCircle(moving.EntityID); circle.LastMovedAt != ctx.Timestamp {
+		t.Errorf("Moving circle should be stamped with the tick time, got %v", circle.LastMovedAt)
+	}
+	if circle, _ := ctx.Database.GetCircle(stationary.EntityID); circle.LastMovedAt != (tables.Timestamp{}) {
+		t.Errorf("Stationary circle should keep its last movement time, got %v", circle.LastMovedAt)
+	}
+
+	normal := logic.CalculateDecayedMass(1000)
+	camping := logic.CalculateCampingDecayedMass(1000)
+	if camping >= normal {
+		t.Fatalf("Camping decay should take more than normal decay, got %d vs %d", camping, normal)
+	}
+	for name, tc := range map[string]struct {
+		entity *tables.Entity
+		want   uint32
+	}{
+		"stationary": {stationary, camping},
+		"moving":     {moving, normal},
+		"fresh":      {fresh, normal},
+	} {
+		after, err := ctx.Database.GetEntity(tc.entity.EntityID)
+		if err != nil {
+			t.Fatalf("GetEntity failed: %v", err)
+		}
+		if after.Mass != tc.want {
+			t.Errorf("%s circle: expected mass %d, got %d", name, tc.want, after.Mass)
+		}
+	}
+}
+
+func TestStartPlayerMassOverride(t *testing.T) {
+	withConfig(t, func(config *constants.Configuration) {
+		config.StartPlayerMassOverride = 400
+	})
+
+	ctx := createTestWorld(t, 1000)
+	if result := ConnectReducer(ctx, []byte{}); !result.IsSuccess() {
+		t.Fatalf("ConnectReducer failed: %s", result.Error())
+	}
+	data, _ := MarshalArgs(EnterGameArgs{Name: "BigBang"})
+	if result := EnterGameReducer(ctx, data); !result.IsSuccess() {
+		t.Fatalf("EnterGameReducer failed: %s", result.Error())
+	}
+	if result := RespawnReducer(ctx, []byte{}); !result.IsSuccess() {
+		t.Fatalf("RespawnReducer failed: %s", result.Error())
+	}
+
+	player, _ := ctx.Database.GetPlayer(ctx.Sender)
+	circles, _ := ctx.Database.GetCirclesByPlayer(player.PlayerID)
+	if len(circles) != 2 {
+		t.Fatalf("Expected circles from EnterGame and Respawn, got %d", len(circles))
+	}
+	for _, circle := range circles {
+		entity, err := ctx.Database.GetEntity(circle.EntityID)
+		if err != nil {
+			t.Fatalf("GetEntity failed: %v", err)
+		}
+		if entity.Mass != 400 {
+			t.Errorf("Circle %d spawned with mass %d, want the override 400", circle.EntityID, entity.Mass)
+		}
+	}
+}
+
+func TestEnterGameColor(t *testing.T) {
+	world := createTestWorld(t, 1000)
+	enter := func(sender byte, args EnterGameArgs) (*tables.Player, *tables.Circle) {
+		ctx := &ReducerContext{Sender: tables.NewIdentity([16]byte{sender}), Timestamp: world.Timestamp, Database: world.Database}
+		if result := ConnectReducer(ctx, []byte{}); !result.IsSuccess() {
+			t.Fatalf("ConnectReducer failed: %s", result.Error())
+		}
+		data, _ := MarshalArgs(args)
+		if result := EnterGameReducer(ctx, data); !result.IsSuccess() {
+			t.Fatalf("EnterGameReducer failed: %s", result.Error())
+		}
+		player, _ := ctx.Database.GetPlayer(ctx.Sender)
+		circles, _ := ctx.Database.GetCirclesByPlayer(player.PlayerID)
+		if len(circles) != 1 {
+			t.Fatalf("Expected 1 spawned circle, got %d", len(circles))
+		}
+		return player, circles[0]
+	}
+
+	t.Run("Supplied color", func(t *testing.T) {
+		_, circle := enter(1, EnterGameArgs{Name: "Painter", Color: 0x11223300})
+		if circle.Color != 0x112233ff {
+			t.Errorf("Circle color = %08x, want the supplied color at full alpha 112233ff", circle.Color)
+		}
+	})
+
+	t.Run("Default color", func(t *testing.T) {
+		player, circle := enter(2, EnterGameArgs{Name: "Plain"})
+		if expected := logic.PlayerColor(player.PlayerID); circle.Color != expected {
+			t.Errorf("Circle color = %08x, want the default %08x", circle.Color, expected)
+		}
+	})
+}
+
+func TestRequireUniqueNames(t *testing.T) {
+	setup := func(t *testing.T, unique bool) (func(sender byte) *ReducerContext, func(ctx *ReducerContext, name string) ReducerResult) {
+		withConfig(t, func(config *constants.Configuration) {
+			config.RequireUniqueNames = unique
+		})
+
+		world := createTestWorld(t, 1000)
+		connect := func(sender byte) *ReducerContext {
+			ctx := &ReducerContext{Sender: tables.NewIdentity([16]byte{sender}), Timestamp: world.Timestamp, Database: world.Database}
+			if result := ConnectReducer(ctx, []byte{}); !result.IsSuccess() {
+				t.Fatalf("ConnectReducer failed: %s", result.Error())
+			}
+			return ctx
+		}
+		enter := func(ctx *ReducerContext, name string) ReducerResult {
+			args, _ := MarshalArgs(EnterGameArgs{Name: name})
+			return EnterGameReducer(ctx, args)
+		}
+		return connect, enter
+	}
+
+	t.Run("Duplicate name rejected", func(t *testing.T) {
+		connect, enter := setup(t, true)
+		alice, other := connect(1), connect(2)
+
+		if result := enter(alice, "Alice"); !result.IsSuccess() {
+			t.Fatalf("First player should take the name: %s", result.Error())
+		}
+		result := enter(other, "aLiCe")
+		if result.IsSuccess() {
+			t.Fatal("A name in use by another player should be rejected regardless of case")
+		}
+		if !strings.Contains(result.Error(), ErrorCodeInvalidState) {
+			t.Errorf("Expected %s, got %s", ErrorCodeInvalidState, result.Error())
+		}
+		if result := enter(other, "Bob"); !result.IsSuccess() {
+			t.Errorf("An unused name should be accepted: %s", result.Error())
+		}
+		if result := enter(alice, "alice"); !result.IsSuccess() {
+			t.Errorf("A player should be able to keep their own name: %s", result.Error())
+		}
+
+		// Once the owner leaves, the name is free again
+		if result := DisconnectReducer(alice, []byte{}); !result.IsSuccess() {
+			t.Fatalf("DisconnectReducer failed: %s", result.Error())
+		}
+		if result := enter(other, "Alice"); !result.IsSuccess() {
+			t.Errorf("A name freed by a disconnect should be accepted: %s", result.Error())
+		}
+	})
+
+	t.Run("Duplicates allowed when disabled", func(t *testing.T) {
+		connect, enter := setup(t, false)
+		if result := enter(connect(1), "Alice"); !result.IsSuccess() {
+			t.Fatalf("EnterGame failed: %s", result.Error())
+		}
+		if result := enter(connect(2), "Alice"); !result.IsSuccess() {
+			t.Errorf("Duplicate names should be allowed without RequireUniqueNames: %s", result.Error())
+		}
+	})
+}
+
+func TestPlayerDeath(t *testing.T) {
+	withConfig(t, func(config *constants.Configuration) {
+		config.MarkDeadPlayers = true
+	})
+	ResetMetrics()
+
+	ctx := createTestWorld(t, 1000)
+	addPlayer := func(seed byte, circles int) (*tables.Player, []*tables.Entity) {
+		player := tables.NewPlayer(tables.NewIdentity([16]byte{seed}), 0, "Player")
+		if err := ctx.Database.InsertPlayer(player); err != nil {
+			t.Fatalf("InsertPlayer failed: %v", err)
+		}
+		player, _ = ctx.Database.GetPlayer(player.Identity)
+		var entities []*tables.Entity
+		for i := 0; i < circles; i++ {
+			entity := insertTestEntity(t, ctx.Database, 100, 100, 20)
+			if err := ctx.Database.InsertCircle(tables.NewCircle(entity.EntityID, player.PlayerID, types.Up(), 0, tables.Timestamp{})); err != nil {
+				t.Fatalf("InsertCircle failed: %v", err)
+			}
+			entities = append(entities, entity)
+		}
+		return player, entities
+	}
+	consume := func(consumer, consumed *tables.Entity) {
+		t.Helper()
+		args, _ := MarshalArgs(ConsumeEntityArgs{ConsumerEntityID: consumer.EntityID, ConsumedEntityID: consumed.EntityID})
+		if result := ConsumeEntityReducer(ctx, args); !result.IsSuccess() {
+			t.Fatalf("ConsumeEntityReducer failed: %s", result.Error())
+		}
+	}
+
+	_, hunter := addPlayer(1, 1)
+	prey, preyCircles := addPlayer(2, 2)
+
+	consume(hunter[0], preyCircles[0])
+	if stored, _ := ctx.Database.GetPlayer(prey.Identity); stored.Deaths != 0 || stored.IsDead {
+		t.Errorf("Losing one of two circles should not be a death: %+v", stored)
+	}
+
+	consume(hunter[0], preyCircles[1])
+	stored, _ := ctx.Database.GetPlayer(prey.Identity)
+	if stored.Deaths != 1 || !stored.IsDead {
+		t.Errorf("Losing the last circle should record a death: %+v", stored)
+	}
+	if got := GetCounter(MetricPlayerDeaths); got != 1 {
+		t.Errorf("Expected 1 player death, got %d", got)
+	}
+
+	respawnCtx := &ReducerContext{Sender: prey.Identity, Timestamp: ctx.Timestamp, Database: ctx.Database}
+	if result := RespawnReducer(respawnCtx, []byte{}); !result.IsSuccess() {
+		t.Fatalf("RespawnReducer failed: %s", result.Error())
+	}
+	if stored, _ := ctx.Database.GetPlayer(prey.Identity); stored.IsDead || stored.Deaths != 1 {
+		t.Errorf("Respawn should clear IsDead but keep the death count: %+v", stored)
+	}
+}
+
+func TestSeedWorld(t *testing.T) {
+	db := &DatabaseContext{}
+	opts := logic.SeedOptions{
+		Players:          5,
+		CirclesPerPlayer: 3,
+		Food:             40,
+		WorldSize:        1000,
+		Timestamp:        tables.NewTimestamp(1_000_000),
+	}
+
+	result, err := logic.SeedWorld(db, opts, logic.NewSeededRNG(7))
+	if err != nil {
+		t.Fatalf("SeedWorld failed: %v", err)
+	}
+	if len(result.PlayerIDs) != 5 || len(result.CircleEntityIDs) != 15 || len(result.FoodEntityIDs) != 40 {
+		t.Fatalf("Unexpected seed counts: %d players, %d circles, %d food",
+			len(result.PlayerIDs), len(result.CircleEntityIDs), len(result.FoodEntityIDs))
+	}
+
+	if count, _ := db.GetPlayerCount(); count != 5 {
+		t.Errorf("Expected 5 players in the database, got %d", count)
+	}
+	if count, _ := db.GetFoodCount(); count != 40 {
+		t.Errorf("Expected 40 food in the database, got %d", count)
+	}
+	if entities, _ := db.GetAllEntities(); len(entities) != 55 {
+		t.Errorf("Expected 55 entities in the database, got %d", len(entities))
+	}
+
+	circles, _ := db.GetAllCircles()
+	if len(circles) != 15 {
+		t.Fatalf("Expected 15 circles in the database, got %d", len(circles))
+	}
+	counts, _ := db.GetCircleCounts()
+	for _, playerID := range result.PlayerIDs {
+		if counts[playerID] != 3 {
+			t.Errorf("Player %d should own 3 circles, got %d", playerID, counts[playerID])
+		}
+	}
+	for _, circle := range circles {
+		entity, err := db.GetEntity(circle.EntityID)
+		if err != nil {
+			t.Errorf("Circle %d has no backing entity: %v", circle.EntityID, err)
+			continue
+		}
+		if entity.Kind != tables.KindCircle {
+			t.Errorf("Circle entity %d has kind %s", entity.EntityID, entity.Kind)
+		}
+	}
+	for _, entityID := range result.FoodEntityIDs {
+		if isFood, _ := db.IsFood(entityID); !isFood {
+			t.Errorf("Seeded food %d is missing from the food table", entityID)
+		}
+	}
+}
+
+func TestSplitMassOverflow(t *testing.T) {
+	config := withConfig(t, func(config *constants.Configuration) {
+		config.MaxCircleMass = 1000
+		config.SplitMassOverflow = true
+		config.MaxCirclesPerPlayer = 2
+	})
+
+	setup := func(t *testing.T, circles int) (*ReducerContext, *tables.Entity) {
+		ctx := createTestWorld(t, 2000)
+		var consumer *tables.Entity
+		for i := 0; i < circles; i++ {
+			entity := insertTestEntity(t, ctx.Database, float32(500+i*100), 500, 900)
+			if err := ctx.Database.InsertCircle(tables.NewCircle(entity.EntityID, 1, types.Right(), 0, tables.Timestamp{})); err != nil {
+				t.Fatalf("InsertCircle failed: %v", err)
+			}
+			if consumer == nil {
+				consumer = entity
+			}
+		}
+		return ctx, consumer
+	}
+	consume := func(t *testing.T, ctx *ReducerContext, consumer *tables.Entity) {
+		food := insertTestEntity(t, ctx.Database, 500, 500, 250)
+		args, _ := MarshalArgs(ConsumeEntityArgs{ConsumerEntityID: consumer.EntityID, ConsumedEntityID: food.EntityID})
+		if result := ConsumeEntityReducer(ctx, args); !result.IsSuccess() {
+			t.Fatalf("ConsumeEntityReducer failed: %s", result.Error())
+		}
+	}
+
+	t.Run("Overflow spawns a circle", func(t *testing.T) {
+		ctx, consumer := setup(t, 1)
+		consume(t, ctx, consumer)
+
+		circles, _ := ctx.Database.GetCirclesByPlayer(1)
+		if len(circles) != 2 {
+			t.Fatalf("Expected the overflow to spawn a second circle, got %d circles", len(circles))
+		}
+		masses := map[uint32]bool{}
+		for _, circle := range circles {
+			entity, _ := ctx.Database.GetEntity(circle.EntityID)
+			masses[entity.Mass] = true
+		}
+		if !masses[1000] || !masses[150] {
+			t.Errorf("Expected masses 1000 and 150, got %v", masses)
+		}
+		if count, _ := ctx.Database.CountScheduledReducers("CircleRecombine"); count != 1 {
+			t.Errorf("The overflow circle should be scheduled to recombine, got %d recombine timers", count)
+		}
+	})
+
+	t.Run("Overflow past MaxCircleMass is discarded", func(t *testing.T) {
+		ctx, consumer := setup(t, 1)
+		if discarded := spawnOverflowCircle(ctx, consumer, 1300); discarded != 300 {
+			t.Errorf("Expected 300 mass above MaxCircleMass to be discarded, got %d", discarded)
+		}
+		if count, _ := ctx.Database.GetCircleCountByPlayer(1); count != 2 {
+			t.Errorf("Expected the overflow to spawn a second circle, got %d circles", count)
+		}
+	})
+
+	t.Run("Overflow discarded at the mass-scaled cap", func(t *testing.T) {
+		withConfig(t, func(scaled *constants.Configuration) {
+			*scaled = *config
+			scaled.CircleCapModel = constants.CircleCapMassScaled
+			scaled.CircleCapMassPerCircle = 1000
+		})
+
+		// 1150 total mass only allows one circle at 1000 per circle
+		ctx, consumer := setup(t, 1)
+		consume(t, ctx, consumer)
+		if count, _ := ctx.Database.GetCircleCountByPlayer(1); count != 1 {
+			t.Errorf("No circle should spawn past the mass-scaled cap, got %d circles", count)
+		}
+	})
+
+	t.Run("Overflow discarded at the circle cap", func(t *testing.T) {
+		ctx, consumer := setup(t, 2)
+		consume(t, ctx, consumer)
+
+		if count, _ := ctx.Database.GetCircleCountByPlayer(1); count != 2 {
+			t.Errorf("No circle should spawn at the cap, got %d circles", count)
+		}
+		if count, _ := ctx.Database.CountScheduledReducers("CircleRecombine"); count != 0 {
+			t.Errorf("No recombine should be scheduled without a new circle, got %d", count)
+		}
+		if entity, _ := ctx.Database.GetEntity(consumer.EntityID); entity.Mass != 1000 {
+			t.Errorf("Consumer should be clamped to 1000, got %d", entity.Mass)
+		}
+	})
+}
+
+func TestFoodMassMultiplier(t *testing.T) {
+	withConfig(t, func(config *constants.Configuration) {
+		config.FoodMassMultiplier = 3
+	})
+
+	ctx := createTestWorld(t, 1000)
+	insertCircle := func(x, y float32, mass uint32) *tables.Entity {
+		entity := insertTestEntity(t, ctx.Database, x, y, mass)
+		if err := ctx.Database.InsertCircle(tables.NewCircle(entity.EntityID, 1, types.Right(), 0, tables.Timestamp{})); err != nil {
+			t.Fatalf("InsertCircle failed: %v", err)
+		}
+		return entity
+	}
+	consume := func(consumer, consumed *tables.Entity) uint32 {
+		args, _ := MarshalArgs(ConsumeEntityArgs{ConsumerEntityID: consumer.EntityID, ConsumedEntityID: consumed.EntityID})
+		if result := ConsumeEntityReducer(ctx, args); !result.IsSuccess() {
+			t.Fatalf("ConsumeEntityReducer failed: %s", result.Error())
+		}
+		updated, _ := ctx.Database.GetEntity(consumer.EntityID)
+		return updated.Mass
+	}
+
+	circle := insertCircle(100, 100, 100)
+	food := insertTestEntity(t, ctx.Database, 110, 100, 4)
+	if err := ctx.Database.InsertFood(tables.NewFood(food.EntityID)); err != nil {
+		t.Fatalf("InsertFood failed: %v", err)
+	}
+	if mass := consume(circle, food); mass != 112 {
+		t.Errorf("Mass after eating 4 food at 3x = %d, want 112", mass)
+	}
+
+	if mass := consume(circle, insertCircle(120, 100, 50)); mass != 162 {
+		t.Errorf("Mass after recombining a 50 mass circle = %d, want 162", mass)
+	}
+}
+
+func TestDestroyEntities(t *testing.T) {
+	ctx := createTestWorld(t, 1000)
+	if err := ctx.Database.InsertPlayer(createTestPlayer()); err != nil {
+		t.Fatalf("InsertPlayer failed: %v", err)
+	}
+	player, err := ctx.Database.GetPlayer(ctx.Sender)
+	if err != nil {
+		t.Fatalf("GetPlayer failed: %v", err)
+	}
+	spawnCircles := func(count int) []uint32 {
+		ids := make([]uint32, count)
+		for i := range ids {
+			entity := insertTestEntity(t, ctx.Database, float32(100+i*50), 100, 50)
+			if err := ctx.Database.InsertCircle(tables.NewCircle(entity.EntityID, player.PlayerID, types.Right(), 0, tables.Timestamp{})); err != nil {
+				t.Fatalf("InsertCircle failed: %v", err)
+			}
+			ids[i] = entity.EntityID
+		}
+		return ids
+	}
+	assertDestroyed := func(t *testing.T, ids []uint32) {
+		t.Helper()
+		if circles, _ := ctx.Database.GetCirclesByPlayer(player.PlayerID); len(circles) != 0 {
+			t.Errorf("Expected no circles left, got %d", len(circles))
+		}
+		for _, id := range ids {
+			if _, err := ctx.Database.GetEntity(id); err == nil {
+				t.Errorf("Entity %d should have been destroyed", id)
+			}
+			if _, err := ctx.Database.GetCircle(id); err == nil {
+				t.Errorf("Circle %d should have been destroyed", id)
+			}
+		}
+	}
+
+	food := insertTestEntity(t, ctx.Database, 900, 900, 3)
+	if err := ctx.Database.InsertFood(tables.NewFood(food.EntityID)); err != nil {
+		t.Fatalf("InsertFood failed: %v", err)
+	}
+
+	t.Run("Batch", func(t *testing.T) {
+		ids := spawnCircles(8)
+		if err := ctx.Database.DestroyEntities(ids); err != nil {
+			t.Fatalf("DestroyEntities failed: %v", err)
+		}
+		assertDestroyed(t, ids)
+		if _, err := ctx.Database.GetEntity(food.EntityID); err != nil {
+			t.Error("Entities outside the batch should survive")
+		}
+	})
+
+	t.Run("Missing ids", func(t *testing.T) {
+		ids := spawnCircles(2)
+		if err := ctx.Database.DestroyEntities(append(ids, 999999)); err == nil {
+			t.Error("DestroyEntities should report missing ids")
+		}
+		assertDestroyed(t, ids)
+	})
+
+	t.Run("Suicide", func(t *testing.T) {
+		ids := spawnCircles(8)
+		if result := SuicideReducer(ctx, []byte{}); !result.IsSuccess() {
+			t.Fatalf("SuicideReducer failed: %s", result.Error())
+		}
+		assertDestroyed(t, ids)
+	})
+
+	t.Run("Disconnect", func(t *testing.T) {
+		ids := spawnCircles(8)
+		if result := DisconnectReducer(ctx, []byte{}); !result.IsSuccess() {
+			t.Fatalf("DisconnectReducer failed: %s", result.Error())
+		}
+		assertDestroyed(t, ids)
+	})
+}
+
+func TestRecombineAtCenterOfMass(t *testing.T) {
+	withConfig(t, func(config *constants.Configuration) {
+		config.RecombineAtCenterOfMass = true
+	})
+
+	ctx := createTestWorld(t, 1000)
+	insertCircle := func(x, y float32, mass, playerID uint32) *tables.Entity {
+		entity := insertTestEntity(t, ctx.Database, x, y, mass)
+		if err := ctx.Database.InsertCircle(tables.NewCircle(entity.EntityID, playerID, types.Right(), 0, tables.Timestamp{})); err != nil {
+			t.Fatalf("InsertCircle failed: %v", err)
+		}
+		return entity
+	}
+	consume := func(consumer, consumed *tables.Entity) *tables.Entity {
+		args, _ := MarshalArgs(ConsumeEntityArgs{ConsumerEntityID: consumer.EntityID, ConsumedEntityID: consumed.EntityID})
+		if result := ConsumeEntityReducer(ctx, args); !result.IsSuccess() {
+			t.Fatalf("ConsumeEntityReducer failed: %s", result.Error())
+		}
+		updated, _ := ctx.Database.GetEntity(consumer.EntityID)
+		return updated
+	}
+
+	t.Run("Recombine moves to the weighted midpoint", func(t *testing.T) {
+		survivor := insertCircle(100, 100, 300, 1)
+		merged := consume(survivor, insertCircle(500, 300, 100, 1))
+		if expected := types.NewDbVector2(200, 150); !merged.Position.Equal(expected) {
+			t.Errorf("Merged circle at %s, want %s", merged.Position.String(), expected.String())
+		}
+		if merged.Mass != 400 {
+			t.Errorf("Merged mass = %d, want 400", merged.Mass)
+		}
+	})
+
+	t.Run("Eating leaves the eater in place", func(t *testing.T) {
+		eater := insertCircle(700, 700, 300, 2)
+		food := insertTestEntity(t, ctx.Database, 710, 700, 4)
+		if err := ctx.Database.InsertFood(tables.NewFood(food.EntityID)); err != nil {
+			t.Fatalf("InsertFood failed: %v", err)
+		}
+		if after := consume(eater, food); !after.Position.Equal(eater.Position) {
+			t.Errorf("Eating food moved the eater to %s", after.Position.String())
+		}
+
+		if after := consume(eater, insertCircle(720, 700, 100, 3)); !after.Position.Equal(eater.Position) {
+			t.Errorf("Eating an enemy circle moved the eater to %s", after.Position.String())
+		}
+	})
+}
+
+func TestRecombineForcedMerge(t *testing.T) {
+	config := withConfig(t, func(config *constants.Configuration) {
+		config.RecombineMaxDistance = 10
+		config.MaxRecombineAttempts = 3
+	})
+	ResetMetrics()
+
+	const playerID = 7
+	ctx := createTestWorld(t, 2000)
+	player := tables.NewPlayer(tables.NewIdentity([16]byte{7}), playerID, "Stranded")
+	if err := ctx.Database.InsertPlayer(player); err != nil {
+		t.Fatalf("InsertPlayer failed: %v", err)
+	}
+	large := insertTestEntity(t, ctx.Database, 200, 200, 400)
+	small := insertTestEntity(t, ctx.Database, 1500, 1500, 100)
+	for _, entity := range []*tables.Entity{large, small} {
+		if err := ctx.Database.InsertCircle(tables.NewCircle(entity.EntityID, playerID, types.Right(), 0, tables.Timestamp{})); err != nil {
+			t.Fatalf("InsertCircle failed: %v", err)
+		}
+	}
+
+	countTimers := func(name string) int {
+		t.Helper()
+		timers, err := ctx.Database.GetScheduledTimers()
+		if err != nil {
+			t.Fatalf("GetScheduledTimers failed: %v", err)
+		}
+		count := 0
+		for _, timer := range timers {
+			if timer.Name == name {
+				count++
+			}
+		}
+		return count
+	}
+
+	// The first attempt comes from a split, the rest from its retry chain
+	args, _ := MarshalArgs(CircleRecombineArgs{PlayerID: playerID})
+	if result := CircleRecombineReducer(ctx, args); !result.IsSuccess() {
+		t.Fatalf("CircleRecombineReducer failed: %s", result.Error())
+	}
+	for attempt := 1; attempt <= 3; attempt++ {
+		if count := countTimers("ConsumeEntity"); count != 0 {
+			t.Fatalf("Far circles should not merge on attempt %d, got %d consume timers", attempt, count)
+		}
+		if count := countTimers("CircleRecombine"); count != 1 {
+			t.Errorf("Attempt %d should leave exactly one retry pending, got %d", attempt, count)
+		}
+		if row, _ := ctx.Database.GetPlayerByPlayerID(playerID); row.RecombineAttempts != uint32(attempt) {
+			t.Errorf("Attempt %d should be counted on the player row, got %d", attempt, row.RecombineAttempts)
+		}
+
+		ctx.Timestamp = ctx.Timestamp.Add(tables.NewTimeDurationFromDuration(config.MovePlayersInterval))
+		if fired := ctx.Database.RunDueTimers(ctx.Timestamp); fired != 1 {
+			t.Fatalf("Expected the pending retry to fire, %d timers fired", fired)
+		}
+	}
+
+	if count := countTimers("ConsumeEntity"); count != 1 {
+		t.Fatalf("Expected a forced merge once the cap was exceeded, got %d consume timers", count)
+	}
+	if count := countTimers("CircleRecombine"); count != 0 {
+		t.Errorf("Nothing is left to retry after the forced merge, got %d retries", count)
+	}
+	if row, _ := ctx.Database.GetPlayerByPlayerID(playerID); row.RecombineAttempts != 0 {
+		t.Errorf("Forced merge should reset the attempt count, got %d", row.RecombineAttempts)
+	}
+	if got := GetCounter(MetricForcedRecombines); got != 1 {
+		t.Errorf("Expected 1 forced recombine, got %d", got)
+	}
+	teleported, _ := ctx.Database.GetEntity(small.EntityID)
+	if teleported.Position != large.Position {
+		t.Errorf("Smaller circle should be teleported onto the larger, got %s", teleported.Position.String())
+	}
+
+	consumeArgs, _ := MarshalArgs(ConsumeEntityArgs{ConsumerEntityID: large.EntityID, ConsumedEntityID: small.EntityID})
+	if result := ConsumeEntityReducer(ctx, consumeArgs); !result.IsSuccess() {
+		t.Fatalf("ConsumeEntityReducer failed: %s", result.Error())
+	}
+	if count, _ := ctx.Database.GetCircleCountByPlayer(playerID); count != 1 {
+		t.Errorf("Expected the circles to merge into one, got %d", count)
+	}
+	if merged, _ := ctx.Database.GetEntity(large.EntityID); merged.Mass != 500 {
+		t.Errorf("Merged circle should hold both masses, got %d", merged.Mass)
+	}
+}
+
+func TestRecombineSingleRetryPerPlayer(t *testing.T) {
+	config := withConfig(t, func(config *constants.Configuration) {
+		config.RecombineMaxDistance = 10
+		config.MaxRecombineAttempts = 0 // Never force, so retries would go on forever
+	})
+
+	ctx := createTestWorld(t, 2000)
+	player := createTestPlayer()
+	if err := ctx.Database.InsertPlayer(player); err != nil {
+		t.Fatalf("InsertPlayer failed: %v", err)
+	}
+	for _, x := range []float32{200, 1500} {
+		entity := insertTestEntity(t, ctx.Database, x, 500, 100)
+		if err := ctx.Database.InsertCircle(tables.NewCircle(entity.EntityID, player.PlayerID, types.Right(), 0, tables.Timestamp{})); err != nil {
+			t.Fatalf("InsertCircle failed: %v", err)
+		}
+	}
+
+	// Several splits each schedule a recombine for the same player
+	args, _ := MarshalArgs(CircleRecombineArgs{PlayerID: player.PlayerID})
+	for i := 0; i < 4; i++ {
+		if result := CircleRecombineReducer(ctx, args); !result.IsSuccess() {
+			t.Fatalf("CircleRecombineReducer failed: %s", result.Error())
+		}
+	}
+	for tick := 0; tick < 5; tick++ {
+		if count, _ := ctx.Database.CountScheduledReducers("CircleRecombine"); count != 1 {
+			t.Fatalf("Tick %d: expected one pending retry for the player, got %d", tick, count)
+		}
+		ctx.Timestamp = ctx.Timestamp.Add(tables.NewTimeDurationFromDuration(config.MovePlayersInterval))
+		ctx.Database.RunDueTimers(ctx.Timestamp)
+	}
+}
+
+func TestReplayableWorldSeed(t *testing.T) {
+	runWorld := func(seed uint64) []types.DbVector2 {
+		t.Helper()
+		db := &DatabaseContext{}
+		config := tables.NewConfig(tables.DefaultArenaID, 1000)
+		config.RngSeed = seed
+		if err := db.InsertConfig(config); err != nil {
+			t.Fatalf("InsertConfig failed: %v", err)
+		}
+
+		// Every reducer call gets a fresh context, as it would from the host
+		call := func(reducer func(*ReducerContext, []byte) ReducerResult, sender byte, micros uint64, args []byte) {
+			t.Helper()
+			ctx := &ReducerContext{Sender: tables.NewIdentity([16]byte{sender}), Timestamp: tables.NewTimestamp(micros), Database: db}
+			if result := reducer(ctx, args); !result.IsSuccess() {
+				t.Fatalf("Reducer failed: %s", result.Error())
+			}
+		}
+		enterArgs, _ := MarshalArgs(EnterGameArgs{Name: "Player"})
+		for sender := byte(1); sender <= 2; sender++ {
+			call(ConnectReducer, sender, 1_000_000, []byte{})
+			call(EnterGameReducer, sender, 1_000_000, enterArgs)
+		}
+		for i := uint64(0); i < 3; i++ {
+			call(SpawnFoodReducer, 0, 2_000_000+i*500_000, []byte{})
+		}
+
+		entities, _ := db.GetAllEntities()
+		positions := make([]types.DbVector2, 0, len(entities))
+		for _, entity := range entities {
+			positions = append(positions, entity.Position)
+		}
+		return positions
+	}
+
+	first := runWorld(12345)
+	second := runWorld(12345)
+	if len(first) == 0 || len(first) != len(second) {
+		t.Fatalf("Worlds with the same seed should have the same entity count: %d vs %d", len(first), len(second))
+	}
+	for i := range first {
+		if !first[i].Equal(second[i]) {
+			t.Fatalf("Entity %d differs between replays: %v vs %v", i, first[i], second[i])
+		}
+	}
+
+	other := runWorld(54321)
+	same := len(other) == len(first)
+	for i := 0; same && i < len(first); i++ {
+		same = first[i].Equal(other[i])
+	}
+	if same {
+		t.Error("A different seed should produce a different layout")
+	}
+
+	t.Run("Init stores a seed", func(t *testing.T) {
+		ctx := createTestContext()
+		if result := InitReducer(ctx, []byte{}); !result.IsSuccess() {
+			t.Fatalf("InitReducer failed: %s", result.Error())
+		}
+		ctx.Rng()
+		config, _ := ctx.Database.GetConfig()
+		if config.RngSeed == 0 || config.RngCounter != 1 {
+			t.Errorf("Expected a stored seed and one derived RNG, got %+v", config)
+		}
+	})
+}
+
+func TestCleanupStalePlayers(t *testing.T) {
+	ctx := createTestWorld(t, 1000)
+	ttl := constants.GetGlobalConfiguration().StalePlayerTTL
+	connectedAt := tables.NewTimestamp(1_000_000)
+
+	stale := tables.NewIdentity([16]byte{1})
+	active := tables.NewIdentity([16]byte{2})
+	for _, identity := range []tables.Identity{stale, active} {
+		connectCtx := &ReducerContext{Sender: identity, Timestamp: connectedAt, Database: ctx.Database}
+		if result := ConnectReducer(connectCtx, []byte{}); !result.IsSuccess() {
+			t.Fatalf("ConnectReducer failed: %s", result.Error())
+		}
+	}
+
+	player, _ := ctx.Database.GetPlayer(stale)
+	if player == nil || player.LastSeen != connectedAt {
+		t.Fatalf("Connect should set LastSeen: %+v", player)
+	}
+
+	// Only the active player sends input before the TTL expires
+	cleanupAt := connectedAt.Add(tables.NewTimeDurationFromDuration(ttl + time.Second))
+	inputCtx := &ReducerContext{Sender: active, Timestamp: cleanupAt, Database: ctx.Database}
+	argsData, _ := MarshalArgs(UpdatePlayerInputArgs{Direction: types.NewDbVector2(1, 0)})
+	if result := UpdatePlayerInputReducer(inputCtx, argsData); !result.IsSuccess() {
+		t.Fatalf("UpdatePlayerInputReducer failed: %s", result.Error())
+	}
+
+	cleanupCtx := &ReducerContext{Timestamp: cleanupAt, Database: ctx.Database}
+	if result := CleanupStalePlayersReducer(cleanupCtx, []byte{}); !result.IsSuccess() {
+		t.Fatalf("CleanupStalePlayersReducer failed: %s", result.Error())
+	}
+
+	if _, err := ctx.Database.GetPlayer(stale); err == nil {
+		t.Error("Stale player should be removed from the player table")
+	}
+	if _, err := ctx.Database.GetLoggedOutPlayer(stale); err != nil {
+		t.Errorf("Stale player should be moved to logged_out_player: %v", err)
+	}
+	if _, err := ctx.Database.GetPlayer(active); err != nil {
+		t.Errorf("Active player should not be cleaned up: %v", err)
+	}
+}
+
+func TestMaxConcurrentPlayers(t *testing.T) {
+	withConfig(t, func(config *constants.Configuration) {
+		config.MaxConcurrentPlayers = 2
+	})
+
+	ctx := createTestWorld(t, 1000)
+	connect := func(identity tables.Identity) ReducerResult {
+		return ConnectReducer(&ReducerContext{Sender: identity, Timestamp: ctx.Timestamp, Database: ctx.Database}, []byte{})
+	}
+
+	returning := tables.NewIdentity([16]byte{1})
+	for _, identity := range []tables.Identity{returning, tables.NewIdentity([16]byte{2})} {
+		if result := connect(identity); !result.IsSuccess() {
+			t.Fatalf("ConnectReducer failed below the cap: %s", result.Error())
+		}
+	}
+
+	// The returning player logs out and a new player takes the free slot
+	disconnectCtx := &ReducerContext{Sender: returning, Timestamp: ctx.Timestamp, Database: ctx.Database}
+	if result := DisconnectReducer(disconnectCtx, []byte{}); !result.IsSuccess() {
+		t.Fatalf("DisconnectReducer failed: %s", result.Error())
+	}
+	if result := connect(tables.NewIdentity([16]byte{3})); !result.IsSuccess() {
+		t.Fatalf("ConnectReducer should fill the freed slot: %s", result.Error())
+	}
+
+	result := connect(tables.NewIdentity([16]byte{4}))
+	if result.IsSuccess() {
+		t.Fatal("ConnectReducer should refuse a new identity at the cap")
+	}
+	if !strings.Contains(result.Error(), ErrorCodeServerFull) {
+		t.Errorf("Expected %s error, got %s", ErrorCodeServerFull, result.Error())
+	}
+
+	if result := connect(returning); !result.IsSuccess() {
+		t.Errorf("Returning player should be admitted at the cap: %s", result.Error())
+	}
+	if count, _ := ctx.Database.GetPlayerCount(); count != 3 {
+		t.Errorf("Expected 3 active players after the returning player reconnects, got %d", count)
+	}
+}
+
+func TestConnectRestoresPlayer(t *testing.T) {
+	ctx := createTestWorld(t, 1000)
+	identity := tables.NewIdentity([16]byte{9})
+	reducerCtx := func(seconds int64) *ReducerContext {
+		at := ctx.Timestamp.Add(tables.NewTimeDurationFromDuration(time.Duration(seconds) * time.Second))
+		return &ReducerContext{Sender: identity, Timestamp: at, Database: ctx.Database}
+	}
+
+	t.Run("New player gets fresh stats", func(t *testing.T) {
+		if result := ConnectReducer(reducerCtx(0), []byte{}); !result.IsSuccess() {
+			t.Fatalf("ConnectReducer failed: %s", result.Error())
+		}
+		player, err := ctx.Database.GetPlayer(identity)
+		if err != nil {
+			t.Fatalf("GetPlayer failed: %v", err)
+		}
+		if player.Name != "" || player.Deaths != 0 || player.IsDead || player.TeamID != 0 {
+			t.Errorf("New player should start with zeroed stats, got %+v", player)
+		}
+	})
+
+	player, _ := ctx.Database.GetPlayer(identity)
+	player.Name = "returner"
+	player.Deaths = 3
+	player.TeamID = 2
+	if err := ctx.Database.UpdatePlayer(player); err != nil {
+		t.Fatalf("UpdatePlayer failed: %v", err)
+	}
+
+	t.Run("Reconnecting player keeps name and stats", func(t *testing.T) {
+		if result := DisconnectReducer(reducerCtx(1), []byte{}); !result.IsSuccess() {
+			t.Fatalf("DisconnectReducer failed: %s", result.Error())
+		}
+		if result := ConnectReducer(reducerCtx(2), []byte{}); !result.IsSuccess() {
+			t.Fatalf("ConnectReducer failed: %s", result.Error())
+		}
+
+		restored, err := ctx.Database.GetPlayer(identity)
+		if err != nil {
+			t.Fatalf("GetPlayer failed: %v", err)
+		}
+		if restored.PlayerID != player.PlayerID || restored.Name != "returner" || restored.Deaths != 3 || restored.TeamID != 2 {
+			t.Errorf("Reconnected player should keep id, name and stats, got %+v", restored)
+		}
+		if restored.LastSeen != reducerCtx(2).Timestamp {
+			t.Errorf("LastSeen should be refreshed on reconnect, got %s", restored.LastSeen.String())
+		}
+		if _, err := ctx.Database.GetLoggedOutPlayer(identity); err == nil {
+			t.Error("Reconnected player should leave the logged out table")
+		}
+	})
+
+	t.Run("Reconnecting while still active", func(t *testing.T) {
+		if result := ConnectReducer(reducerCtx(3), []byte{}); !result.IsSuccess() {
+			t.Fatalf("ConnectReducer should accept an identity that is still active: %s", result.Error())
+		}
+
+		restored, _ := ctx.Database.GetPlayer(identity)
+		if restored.Name != "returner" || restored.Deaths != 3 {
+			t.Errorf("Active player should keep name and stats, got %+v", restored)
+		}
+		if count, _ := ctx.Database.GetPlayerCount(); count != 1 {
+			t.Errorf("Expected 1 active player, got %d", count)
+		}
+	})
+}
+
+func TestReconnectGrace(t *testing.T) {
+	withConfig(t, func(config *constants.Configuration) {
+		config.MaxConcurrentPlayers = 1
+		config.ReconnectGraceSec = 5
+	})
+
+	returning := tables.NewIdentity([16]byte{1})
+	newcomer := tables.NewIdentity([16]byte{2})
+
+	// fillCap has the returning player disconnect at the start of a fresh world and a
+	// newcomer take the only slot, returning a connect helper at the given delay
+	fillCap := func(t *testing.T) func(identity tables.Identity, delay time.Duration) ReducerResult {
+		ctx := createTestWorld(t, 1000)
+		connect := func(identity tables.Identity, delay time.Duration) ReducerResult {
+			at := ctx.Timestamp.Add(tables.NewTimeDurationFromDuration(delay))
+			return ConnectReducer(&ReducerContext{Sender: identity, Timestamp: at, Database: ctx.Database}, []byte{})
+		}
+		if result := connect(returning, 0); !result.IsSuccess() {
+			t.Fatalf("ConnectReducer failed: %s", result.Error())
+		}
+		disconnectCtx := &ReducerContext{Sender: returning, Timestamp: ctx.Timestamp, Database: ctx.Database}
+		if result := DisconnectReducer(disconnectCtx, []byte{}); !result.IsSuccess() {
+			t.Fatalf("DisconnectReducer failed: %s", result.Error())
+		}
+		if result := connect(newcomer, 0); !result.IsSuccess() {
+			t.Fatalf("ConnectReducer should fill the freed slot: %s", result.Error())
+		}
+		return connect
+	}
+
+	t.Run("Within grace", func(t *testing.T) {
+		connect := fillCap(t)
+		if result := connect(returning, 3*time.Second); !result.IsSuccess() {
+			t.Errorf("Player reconnecting within the grace window should be admitted at the cap: %s", result.Error())
+		}
+	})
+
+	t.Run("After grace", func(t *testing.T) {
+		connect := fillCap(t)
+		result := connect(returning, 10*time.Second)
+		if result.IsSuccess() {
+			t.Fatal("Player reconnecting after the grace window should count against the cap")
+		}
+		if !strings.Contains(result.Error(), ErrorCodeServerFull) {
+			t.Errorf("Expected %s error, got %s", ErrorCodeServerFull, result.Error())
+		}
+	})
+}
+
+// Test admin reducers
+
+func TestSetWorldSizeReducer(t *testing.T) {
+	t.Run("Rejects non-admin", func(t *testing.T) {
+		ctx := createTestWorld(t, 1000)
+		argsData, _ := MarshalArgs(SetWorldSizeArgs{WorldSize: 500})
+
+		result := SetWorldSizeReducer(ctx, argsData)
+		if result.IsSuccess() {
+			t.Error("SetWorldSize should be rejected for non-admin callers")
+		}
+
+		config, _ := ctx.Database.GetConfig()
+		if config.WorldSize != 1000 {
+			t.Errorf("World size should be unchanged, got %d", config.WorldSize)
+		}
+	})
+
+	t.Run("Init caller becomes admin", func(t *testing.T) {
+		owner := createTestContext()
+		if result := InitReducer(owner, []byte{}); !result.IsSuccess() {
+			t.Fatalf("InitReducer failed: %s", result.Error())
+		}
+		other := &ReducerContext{Sender: tables.NewIdentity([16]byte{99}), Timestamp: owner.Timestamp, Database: owner.Database}
+
+		// A re-publish by someone else keeps the original admin
+		if result := InitReducer(other, []byte{}); !result.IsSuccess() {
+			t.Fatalf("InitReducer failed: %s", result.Error())
+		}
+		argsData, _ := MarshalArgs(SetWorldSizeArgs{WorldSize: 500})
+		if SetWorldSizeReducer(other, argsData).IsSuccess() {
+			t.Error("SetWorldSize should be rejected for callers other than the Init caller")
+		}
+		if result := SetWorldSizeReducer(owner, argsData); !result.IsSuccess() {
+			t.Fatalf("SetWorldSize should succeed for the Init caller: %s", result.Error())
+		}
+		if config, _ := owner.Database.GetConfig(); config.WorldSize != 500 || config.AdminIdentity != owner.Sender {
+			t.Errorf("Config = %+v, want world size 500 administered by %s", *config, owner.Sender.String())
+		}
+	})
+
+	t.Run("Valid resize", func(t *testing.T) {
+		ctx := createAdminWorld(t, 1000)
+
+		inside := insertTestEntity(t, ctx.Database, 100, 100, 25)
+		outside := insertTestEntity(t, ctx.Database, 900, 700, 25)
+
+		argsData, _ := MarshalArgs(SetWorldSizeArgs{WorldSize: 500})
+		result := SetWorldSizeReducer(ctx, argsData)
+		if !result.IsSuccess() {
+			t.Fatalf("SetWorldSize should succeed: %s", result.Error())
+		}
+
+		config, _ := ctx.Database.GetConfig()
+		if config.WorldSize != 500 {
+			t.Errorf("World size = %d, want 500", config.WorldSize)
+		}
+
+		unchanged, _ := ctx.Database.GetEntity(inside.EntityID)
+		if !unchanged.Position.Equal(inside.Position) {
+			t.Errorf("Entity inside new bounds should not move: got %v", unchanged.Position)
+		}
+
+		reclamped, _ := ctx.Database.GetEntity(outside.EntityID)
+		radius := constants.MassToRadius(25)
+		expected := types.NewDbVector2(500-radius, 500-radius)
+		if !reclamped.Position.Equal(expected) {
+			t.Errorf("Entity outside new bounds should be reclamped to %v, got %v", expected, reclamped.Position)
+		}
+	})
+
+	t.Run("Rescale mode", func(t *testing.T) {
+		ctx := createAdminWorld(t, 1000)
+
+		entity := insertTestEntity(t, ctx.Database, 200, 600, 25)
+
+		argsData, _ := MarshalArgs(SetWorldSizeArgs{WorldSize: 500, Rescale: true})
+		if result := SetWorldSizeReducer(ctx, argsData); !result.IsSuccess() {
+			t.Fatalf("SetWorldSize should succeed: %s", result.Error())
+		}
+
+		rescaled, _ := ctx.Database.GetEntity(entity.EntityID)
+		if expected := types.NewDbVector2(100, 300); !rescaled.Position.Equal(expected) {
+			t.Errorf("Entity should be rescaled to %v, got %v", expected, rescaled.Position)
+		}
+	})
+
+	t.Run("Out-of-range size rejected", func(t *testing.T) {
+		ctx := createAdminWorld(t, 1000)
+
+		for _, size := range []uint64{constants.MIN_WORLD_SIZE - 1, constants.MAX_WORLD_SIZE + 1} {
+			argsData, _ := MarshalArgs(SetWorldSizeArgs{WorldSize: size})
+			result := SetWorldSizeReducer(ctx, argsData)
+			if result.IsSuccess() {
+				t.Errorf("SetWorldSize(%d) should be rejected", size)
+			}
+		}
+
+		config, _ := ctx.Database.GetConfig()
+		if config.WorldSize != 1000 {
+			t.Errorf("World size should be unchanged after rejection, got %d", config.WorldSize)
+		}
+	})
+
+	t.Run("Resize second arena", func(t *testing.T) {
+		ctx := createAdminWorld(t, 1000)
+
+		if err := ctx.Database.InsertConfig(tables.NewConfig(1, 2000)); err != nil {
+			t.Fatalf("Failed to insert second arena config: %v", err)
+		}
+		entity := insertTestEntity(t, ctx.Database, 900, 900, 25)
+
+		argsData, _ := MarshalArgs(SetWorldSizeArgs{WorldSize: 500, ArenaID: 1})
+		result := SetWorldSizeReducer(ctx, argsData)
+		if !result.IsSuccess() {
+			t.Fatalf("SetWorldSize should succeed for the second arena: %s", result.Error())
+		}
+
+		second, _ := ctx.Database.GetConfigByID(1)
+		if second.WorldSize != 500 {
+			t.Errorf("Second arena world size = %d, want 500", second.WorldSize)
+		}
+		first, _ := ctx.Database.GetConfig()
+		if first.WorldSize != 1000 {
+			t.Errorf("Default arena world size should be unchanged, got %d", first.WorldSize)
+		}
+		unchanged, _ := ctx.Database.GetEntity(entity.EntityID)
+		if !unchanged.Position.Equal(entity.Position) {
+			t.Errorf("Default arena entities should not be reclamped, got %v", unchanged.Position)
+		}
+
+		argsData, _ = MarshalArgs(SetWorldSizeArgs{WorldSize: 500, ArenaID: 7})
+		if SetWorldSizeReducer(ctx, argsData).IsSuccess() {
+			t.Error("SetWorldSize should fail for an unknown arena")
+		}
+	})
+}
+
+func TestModuleStatus(t *testing.T) {
+	clk := installManualClock(t)
+	ctx := createTestContext()
+
+	if result := InitReducer(ctx, []byte{}); !result.IsSuccess() {
+		t.Fatalf("InitReducer failed: %s", result.Error())
+	}
+	if err := ctx.Database.InsertPlayer(createTestPlayer()); err != nil {
+		t.Fatalf("InsertPlayer failed: %v", err)
+	}
+	insertTestEntity(t, ctx.Database, 100, 100, 50)
+	for i := 0; i < 3; i++ {
+		food := insertTestEntity(t, ctx.Database, float32(200+i*10), 200, 3)
+		if err := ctx.Database.InsertFood(tables.NewFood(food.EntityID)); err != nil {
+			t.Fatalf("InsertFood failed: %v", err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if result := MoveAllPlayersReducer(ctx, []byte{}); !result.IsSuccess() {
+			t.Fatalf("MoveAllPlayersReducer failed: %s", result.Error())
+		}
+	}
+
+	clk.Advance(90 * time.Second)
+	ctx.Timestamp = Now()
+	status, err := GetModuleStatus(ctx)
+	if err != nil {
+		t.Fatalf("GetModuleStatus failed: %v", err)
+	}
+	expected := logic.Status{Initialized: true, UptimeSec: 90, Players: 1, Entities: 4, Food: 3, Tick: 2}
+	if *status != expected {
+		t.Errorf("Status = %+v, want %+v", *status, expected)
+	}
+
+	t.Run("Paused while idle", func(t *testing.T) {
+		withConfig(t, func(config *constants.Configuration) {
+			config.SkipIdleTicks = true
+		})
+
+		status, _ := GetModuleStatus(ctx)
+		if status.Paused {
+			t.Error("A game with players should not report paused")
+		}
+		idle := &ReducerContext{Sender: ctx.Sender, Timestamp: ctx.Timestamp, Database: &DatabaseContext{}}
+		status, _ = GetModuleStatus(idle)
+		if !status.Paused {
+			t.Error("An idle game skipping its ticks should report paused")
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		data, err := json.Marshal(status)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		for _, field := range []string{`"uptime_sec":90`, `"players":1`, `"entities":4`, `"food":3`, `"tick":2`, `"paused":false`} {
+			if !strings.Contains(string(data), field) {
+				t.Errorf("Status JSON %s is missing %s", data, field)
+			}
+		}
+		if result := StatusReducer(ctx, []byte{}); !result.IsSuccess() {
+			t.Errorf("StatusReducer failed: %s", result.Error())
+		}
+	})
+}
+
+func TestSetConfigReducer(t *testing.T) {
+	withConfig(t, func(*constants.Configuration) {}) // SetConfig replaces the global configuration
+
+	t.Run("Rejects non-admin", func(t *testing.T) {
+		ctx := createTestWorld(t, 1000)
+		if result := SetConfigReducer(ctx, []byte(`{"target_food_count": 5}`)); result.IsSuccess() {
+			t.Error("SetConfig should be rejected for non-admin callers")
+		}
+		if got := constants.GetGlobalConfiguration().TargetFoodCount; got != constants.TARGET_FOOD_COUNT {
+			t.Errorf("TargetFoodCount should be unchanged, got %d", got)
+		}
+	})
+
+	t.Run("Target food count takes effect", func(t *testing.T) {
+		withConfig(t, func(*constants.Configuration) {})
+		ctx := createAdminWorld(t, 1000)
+		if err := ctx.Database.InsertPlayer(createTestPlayer()); err != nil {
+			t.Fatalf("InsertPlayer failed: %v", err)
+		}
+
+		if result := SetConfigReducer(ctx, []byte(`{"target_food_count": 5}`)); !result.IsSuccess() {
+			t.Fatalf("SetConfig should succeed: %s", result.Error())
+		}
+		config := constants.GetGlobalConfiguration()
+		if config.TargetFoodCount != 5 {
+			t.Errorf("TargetFoodCount = %d, want 5", config.TargetFoodCount)
+		}
+		if config.FoodMassMax != constants.FOOD_MASS_MAX {
+			t.Errorf("Fields missing from the update should keep their value, got FoodMassMax %d", config.FoodMassMax)
+		}
+
+		if result := SpawnFoodReducer(ctx, []byte{}); !result.IsSuccess() {
+			t.Fatalf("SpawnFoodReducer failed: %s", result.Error())
+		}
+		if count, _ := ctx.Database.GetFoodCount(); count != 5 {
+			t.Errorf("Expected food to fill the new target of 5, got %d", count)
+		}
+	})
+
+	t.Run("Refuses invalid values", func(t *testing.T) {
+		ctx := createAdminWorld(t, 1000)
+
+		for _, args := range []string{
+			`{"target_food_count": 0}`,
+			`{"food_mass_min": 10, "food_mass_max": 5}`,
+			`{"target_food_cnt": 5}`,
+			`not json`,
+		} {
+			if result := SetConfigReducer(ctx, []byte(args)); result.IsSuccess() {
+				t.Errorf("SetConfig should refuse %s", args)
+			}
+		}
+		config := constants.GetGlobalConfiguration()
+		if config.TargetFoodCount != constants.TARGET_FOOD_COUNT || config.FoodMassMin != constants.FOOD_MASS_MIN {
+			t.Errorf("Refused updates should leave the configuration unchanged, got %+v", config)
+		}
+	})
+
+	t.Run("World size updates the config table", func(t *testing.T) {
+		withConfig(t, func(*constants.Configuration) {})
+		ctx := createAdminWorld(t, 1000)
+		outside := insertTestEntity(t, ctx.Database, 900, 900, 25)
+
+		if result := SetConfigReducer(ctx, []byte(`{"default_world_size": 500}`)); !result.IsSuccess() {
+			t.Fatalf("SetConfig should succeed: %s", result.Error())
+		}
+		if config, _ := ctx.Database.GetConfig(); config.WorldSize != 500 {
+			t.Errorf("World size = %d, want 500", config.WorldSize)
+		}
+		if entity, _ := ctx.Database.GetEntity(outside.EntityID); entity.Position.X > 500 || entity.Position.Y > 500 {
+			t.Errorf("Entity should be clamped inside the new world, got %s", entity.Position.String())
+		}
+	})
+
+	t.Run("Rejected world size change leaves the arena alone", func(t *testing.T) {
+		ctx := createAdminWorld(t, 1000)
+		outside := insertTestEntity(t, ctx.Database, 900, 900, 25)
+
+		if result := SetConfigReducer(ctx, []byte(`{"default_world_size": 500, "target_food_count": 0}`)); result.IsSuccess() {
+			t.Fatal("SetConfig should refuse an invalid merge")
+		}
+		if config, _ := ctx.Database.GetConfig(); config.WorldSize != 1000 {
+			t.Errorf("World size = %d, want 1000", config.WorldSize)
+		}
+		if entity, _ := ctx.Database.GetEntity(outside.EntityID); entity.Position.X != 900 || entity.Position.Y != 900 {
+			t.Errorf("Entity should not move, got %s", entity.Position.String())
+		}
+		if got := constants.GetGlobalConfiguration().DefaultWorldSize; got != constants.DEFAULT_WORLD_SIZE {
+			t.Errorf("DefaultWorldSize = %d, want %d", got, constants.DEFAULT_WORLD_SIZE)
+		}
+	})
+}
+
+// Benchmark tests
+
+func BenchmarkReducerInvocation(b *testing.B) {
+	reducer := NewReducer("benchmark", func(ctx *ReducerContext, args []byte) ReducerResult {
+		return SuccessResult{}
+	})
+
+	ctx := createTestContext()
+	args := []byte("{}")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		reducer.Invoke(ctx, args)
+	}
+}
+
+func BenchmarkJSONMarshaling(b *testing.B) {
+	args := map[string]interface{}{
+		"name":      "test",
+		"direction": types.NewDbVector2(1.0, 2.0),
+		"mass":      uint32(100),
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		MarshalArgs(args)
+	}
+}
+
+func BenchmarkRNGGeneration(b *testing.B) {
+	ctx := createTestContext()
+	rng := ctx.Rng()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rng.Float32()
+	}
+}
+
+// Test constants integration
+
+func TestConstantsIntegration(t *testing.T) {
+	t.Run("Constants accessible", func(t *testing.T) {
+		config := constants.GetGlobalConfiguration()
+
+		if config.StartPlayerMass == 0 {
+			t.Error("Constants should be accessible")
+		}
+
+		if config.TargetFoodCount == 0 {
+			t.Error("Target food count should be set")
+		}
+	})
+
+	t.Run("Mathematical functions", func(t *testing.T) {
+		mass := uint32(100)
+		radius := constants.MassToRadius(mass)
+
+		if radius <= 0 {
+			t.Error("Radius should be positive")
+		}
+
+		speed := constants.MassToMaxMoveSpeed(mass)
+		if speed <= 0 {
+			t.Error("Speed should be positive")
+		}
+	})
+}
+
+func TestGetEntitiesSortedByMass(t *testing.T) {
+	db := &DatabaseContext{}
+	masses := []uint32{30, 10, 20, 10, 30}
+	for i, mass := range masses {
+		insertTestEntity(t, db, float32(100+i*10), 100, mass)
+	}
+	// Entities get ids 1-5 in insertion order, so ties are (2, 4) at mass 10 and (1, 5) at mass 30
+	idsOf := func(entities []*tables.Entity) []uint32 {
+		ids := make([]uint32, len(entities))
+		for i, entity := range entities {
+			ids[i] = entity.EntityID
+		}
+		return ids
+	}
+
+	t.Run("Ascending", func(t *testing.T) {
+		entities, err := db.GetEntitiesSortedByMass(true)
+		if err != nil {
+			t.Fatalf("GetEntitiesSortedByMass failed: %v", err)
+		}
+		if got, want := idsOf(entities), []uint32{2, 4, 3, 1, 5}; !reflect.DeepEqual(got, want) {
+			t.Errorf("Ascending order = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Descending keeps ties in id order", func(t *testing.T) {
+		entities, err := db.GetEntitiesSortedByMass(false)
+		if err != nil {
+			t.Fatalf("GetEntitiesSortedByMass failed: %v", err)
+		}
+		if got, want := idsOf(entities), []uint32{1, 5, 3, 2, 4}; !reflect.DeepEqual(got, want) {
+			t.Errorf("Descending order = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Writes refresh the order", func(t *testing.T) {
+		entity, err := db.GetEntity(3)
+		if err != nil {
+			t.Fatalf("GetEntity failed: %v", err)
+		}
+		entity.Mass = 5
+		if err := db.UpdateEntity(entity); err != nil {
+			t.Fatalf("UpdateEntity failed: %v", err)
+		}
+		if err := db.DeleteEntity(5); err != nil {
+			t.Fatalf("DeleteEntity failed: %v", err)
+		}
+
+		entities, _ := db.GetEntitiesSortedByMass(true)
+		if got, want := idsOf(entities), []uint32{3, 2, 4, 1}; !reflect.DeepEqual(got, want) {
+			t.Errorf("Order after writes = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Returns copies", func(t *testing.T) {
+		entities, _ := db.GetEntitiesSortedByMass(true)
+		entities[0].Mass = 1000
+
+		again, _ := db.GetEntitiesSortedByMass(true)
+		if again[0].Mass == 1000 {
+			t.Error("Mutating a returned entity should not change the store")
+		}
+	})
+}
+
+func TestNearestEntity(t *testing.T) {
+	db := &DatabaseContext{}
+	insert := func(x, y float32, kind tables.EntityKind) *tables.Entity {
+		entity := tables.NewEntity(0, types.NewDbVector2(x, y), 10)
+		entity.Kind = kind
+		if err := db.InsertEntity(entity); err != nil {
+			t.Fatalf("InsertEntity failed: %v", err)
+		}
+		return entity
+	}
+	isFood := func(entity *tables.Entity) bool { return entity.Kind == tables.KindFood }
+
+	circle := insert(505, 500, tables.KindCircle)
+	// Both foods are 20 away; the lower id sits in a neighbouring cell, searched after
+	// the cell holding the point, so the tie can't be settled by search order
+	tiedLow := insert(480, 500, tables.KindFood)
+	tiedHigh := insert(500, 520, tables.KindFood)
+	far := insert(900, 900, tables.KindFood)
+	point := types.NewDbVector2(500, 500)
+
+	t.Run("Unfiltered", func(t *testing.T) {
+		nearest, distance, err := db.NearestEntity(point, nil)
+		if err != nil {
+			t.Fatalf("NearestEntity failed: %v", err)
+		}
+		if nearest.EntityID != circle.EntityID || math.Abs(float64(distance-5)) > 0.001 {
+			t.Errorf("Nearest = %d at %f, want circle %d at 5", nearest.EntityID, distance, circle.EntityID)
+		}
+	})
+
+	t.Run("Nearest food skips circles and breaks ties by id", func(t *testing.T) {
+		nearest, distance, err := db.NearestEntity(point, isFood)
+		if err != nil {
+			t.Fatalf("NearestEntity failed: %v", err)
+		}
+		if nearest.EntityID != tiedLow.EntityID || math.Abs(float64(distance-20)) > 0.001 {
+			t.Errorf("Nearest food = %d at %f, want %d at 20 (not %d)", nearest.EntityID, distance, tiedLow.EntityID, tiedHigh.EntityID)
+		}
+	})
+
+	t.Run("Searches distant cells", func(t *testing.T) {
+		nearest, _, err := db.NearestEntity(types.NewDbVector2(50, 50), func(entity *tables.Entity) bool {
+			return entity.EntityID == far.EntityID
+		})
+		if err != nil || nearest.EntityID != far.EntityID {
+			t.Errorf("Expected the only matching entity %d far away, got %v, %v", far.EntityID, nearest, err)
+		}
+	})
+
+	t.Run("Sees entity writes", func(t *testing.T) {
+		moved := *far
+		moved.Position = types.NewDbVector2(501, 500)
+		if err := db.UpdateEntity(&moved); err != nil {
+			t.Fatalf("UpdateEntity failed: %v", err)
+		}
+		nearest, _, err := db.NearestEntity(point, isFood)
+		if err != nil || nearest.EntityID != far.EntityID {
+			t.Errorf("Expected the moved food %d, got %v, %v", far.EntityID, nearest, err)
+		}
+	})
+
+	t.Run("No match", func(t *testing.T) {
+		if _, _, err := db.NearestEntity(point, func(*tables.Entity) bool { return false }); err == nil {
+			t.Error("Expected an error when no entity passes the filter")
+		}
+	})
+}
+
+func TestIdleTicks(t *testing.T) {
+	setup := func(t *testing.T) (*ReducerContext, *tables.Entity, *tables.Entity) {
+		ctx := createTestWorld(t, 1000)
+		// A circle left behind without a connected player, next to some food
+		circleEntity := insertTestEntity(t, ctx.Database, 500, 500, 100)
+		if err := ctx.Database.InsertCircle(tables.NewCircle(circleEntity.EntityID, 1, types.Right(), 1.0, tables.Timestamp{})); err != nil {
+			t.Fatalf("InsertCircle failed: %v", err)
+		}
+		food := insertTestEntity(t, ctx.Database, 502, 500, 2)
+		if err := ctx.Database.InsertFood(tables.NewFood(food.EntityID)); err != nil {
+			t.Fatalf("InsertFood failed: %v", err)
+		}
+		return ctx, circleEntity, food
+	}
+	tick := func(t *testing.T, ctx *ReducerContext) {
+		for _, reducer := range []func(*ReducerContext, []byte) ReducerResult{MoveAllPlayersReducer, CircleDecayReducer} {
+			if result := reducer(ctx, []byte{}); !result.IsSuccess() {
+				t.Fatalf("Reducer failed: %s", result.Error())
+			}
+		}
+	}
+
+	t.Run("Skipped without players", func(t *testing.T) {
+		ctx, circleEntity, food := setup(t)
+		tick(t, ctx)
+
+		if after, _ := ctx.Database.GetEntity(circleEntity.EntityID); after.Position != circleEntity.Position || after.Mass != circleEntity.Mass {
+			t.Errorf("Circle should be untouched on an idle server, got %+v", after)
+		}
+		if after, err := ctx.Database.GetEntity(food.EntityID); err != nil || after.Position != food.Position {
+			t.Errorf("Food should be untouched on an idle server, got %+v, %v", after, err)
+		}
+		if count, _ := ctx.Database.CountScheduledReducers("ConsumeEntity"); count != 0 {
+			t.Errorf("No consumes should be scheduled on an idle server, got %d", count)
+		}
+	})
+
+	t.Run("Runs when disabled", func(t *testing.T) {
+		withConfig(t, func(config *constants.Configuration) {
+			config.SkipIdleTicks = false
+		})
+
+		ctx, circleEntity, _ := setup(t)
+		tick(t, ctx)
+
+		if after, _ := ctx.Database.GetEntity(circleEntity.EntityID); after.Position == circleEntity.Position || after.Mass >= circleEntity.Mass {
+			t.Errorf("Circle should move and decay with idle skipping disabled, got %+v", after)
 		}
 	})
 }
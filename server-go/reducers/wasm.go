@@ -5,9 +5,10 @@ package reducers
 import (
 	"encoding/json"
 	"fmt"
-	"time"
+	"unsafe"
 
 	"github.com/clockworklabs/Blackholio/server-go/tables"
+	"github.com/clockworklabs/Blackholio/server-go/types"
 )
 
 // Simplified WASM implementation for Go 1.23 compatibility
@@ -28,7 +29,7 @@ func callReducer(reducerId uint32) int16 {
 	// Create mock context for compilation
 	ctx := &ReducerContext{
 		Sender:    tables.Identity{},
-		Timestamp: tables.NewTimestampFromTime(time.Now()),
+		Timestamp: Now(),
 		Database:  &DatabaseContext{handle: 0},
 	}
 
@@ -56,6 +57,37 @@ func getModuleInfo() int16 {
 	return 0
 }
 
+//go:wasmexport __module_status__
+func moduleStatus() int16 {
+	ctx := &ReducerContext{Timestamp: Now(), Database: &DatabaseContext{handle: 0}}
+	status, err := GetModuleStatus(ctx)
+	if err != nil {
+		fmt.Printf("[WASM] Failed to get module status: %v\n", err)
+		return 1
+	}
+
+	statusBytes, err := json.Marshal(status)
+	if err != nil {
+		fmt.Printf("[WASM] Failed to marshal module status: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("[WASM] Module status: %s\n", string(statusBytes))
+	return 0
+}
+
+//go:wasmexport __export_metrics__
+func exportMetrics() int16 {
+	ctx := &ReducerContext{Timestamp: Now(), Database: &DatabaseContext{handle: 0}}
+	if err := recordGameGauges(ctx); err != nil {
+		fmt.Printf("[WASM] Failed to record game gauges: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("[WASM] Metrics:\n%s", ExportPrometheus())
+	return 0
+}
+
 //go:wasmexport __describe_module_def__
 func describeModuleDef() int16 {
 	moduleDef := map[string]interface{}{
@@ -77,11 +109,24 @@ func describeModuleDef() int16 {
 
 // Simple database operations (mocked for WASM compilation)
 
+// memoryStore is unused in WASM builds, where tables live in SpacetimeDB
+type memoryStore struct{}
+
 func (db *DatabaseContext) InsertConfig(config *tables.Config) error {
 	fmt.Printf("[WASM] Mock InsertConfig: %+v\n", config)
 	return nil
 }
 
+func (db *DatabaseContext) UpdateConfig(config *tables.Config) error {
+	fmt.Printf("[WASM] Mock UpdateConfig: %+v\n", config)
+	return nil
+}
+
+func (db *DatabaseContext) AdvanceRngCounter() (uint64, uint64, error) {
+	fmt.Printf("[WASM] Mock AdvanceRngCounter\n")
+	return 0, 0, nil
+}
+
 func (db *DatabaseContext) GetLoggedOutPlayer(identity tables.Identity) (*tables.Player, error) {
 	fmt.Printf("[WASM] Mock GetLoggedOutPlayer: %s\n", identity.String())
 	return nil, fmt.Errorf("mock: player not found")
@@ -102,11 +147,36 @@ func (db *DatabaseContext) GetPlayer(identity tables.Identity) (*tables.Player,
 	return &tables.Player{Identity: identity, PlayerID: 1, Name: "MockPlayer"}, nil
 }
 
+func (db *DatabaseContext) IsPlayerNameTaken(name string, except tables.Identity) (bool, error) {
+	fmt.Printf("[WASM] Mock IsPlayerNameTaken: %s\n", name)
+	return false, nil
+}
+
+func (db *DatabaseContext) GetPlayerByPlayerID(playerID uint32) (*tables.Player, error) {
+	fmt.Printf("[WASM] Mock GetPlayerByPlayerID: %d\n", playerID)
+	return &tables.Player{PlayerID: playerID, Name: "MockPlayer"}, nil
+}
+
 func (db *DatabaseContext) GetCirclesByPlayer(playerID uint32) ([]*tables.Circle, error) {
 	fmt.Printf("[WASM] Mock GetCirclesByPlayer: %d\n", playerID)
 	return []*tables.Circle{}, nil
 }
 
+func (db *DatabaseContext) GetPlayerEntities(playerID uint32) ([]*tables.Entity, error) {
+	fmt.Printf("[WASM] Mock GetPlayerEntities: %d\n", playerID)
+	return []*tables.Entity{}, nil
+}
+
+func (db *DatabaseContext) GetCircleCountByPlayer(playerID uint32) (uint32, error) {
+	fmt.Printf("[WASM] Mock GetCircleCountByPlayer: %d\n", playerID)
+	return 0, nil
+}
+
+func (db *DatabaseContext) GetCircleCounts() (map[uint32]uint32, error) {
+	fmt.Printf("[WASM] Mock GetCircleCounts\n")
+	return map[uint32]uint32{}, nil
+}
+
 func (db *DatabaseContext) UpdatePlayer(player *tables.Player) error {
 	fmt.Printf("[WASM] Mock UpdatePlayer: %+v\n", player)
 	return nil
@@ -127,6 +197,11 @@ func (db *DatabaseContext) GetEntity(entityID uint32) (*tables.Entity, error) {
 	return nil, fmt.Errorf("mock: entity not found")
 }
 
+func (db *DatabaseContext) GetLargestEntity() (*tables.Entity, error) {
+	fmt.Printf("[WASM] Mock GetLargestEntity\n")
+	return nil, fmt.Errorf("mock: no entities")
+}
+
 func (db *DatabaseContext) UpdateEntity(entity *tables.Entity) error {
 	fmt.Printf("[WASM] Mock UpdateEntity: %+v\n", entity)
 	return nil
@@ -142,6 +217,21 @@ func (db *DatabaseContext) GetAllEntities() ([]*tables.Entity, error) {
 	return []*tables.Entity{}, nil
 }
 
+func (db *DatabaseContext) GetEntitiesSortedByMass(ascending bool) ([]*tables.Entity, error) {
+	fmt.Printf("[WASM] Mock GetEntitiesSortedByMass: %v\n", ascending)
+	return []*tables.Entity{}, nil
+}
+
+func (db *DatabaseContext) NearestEntity(point types.DbVector2, filter func(*tables.Entity) bool) (*tables.Entity, float32, error) {
+	fmt.Printf("[WASM] Mock NearestEntity: %v\n", point)
+	return nil, 0, fmt.Errorf("no matching entity")
+}
+
+func (db *DatabaseContext) GetAllFood() ([]*tables.Food, error) {
+	fmt.Printf("[WASM] Mock GetAllFood\n")
+	return []*tables.Food{}, nil
+}
+
 func (db *DatabaseContext) GetAllPlayers() ([]*tables.Player, error) {
 	fmt.Printf("[WASM] Mock GetAllPlayers\n")
 	return []*tables.Player{}, nil
@@ -152,11 +242,21 @@ func (db *DatabaseContext) GetCircle(entityID uint32) (*tables.Circle, error) {
 	return nil, fmt.Errorf("mock: circle not found")
 }
 
+func (db *DatabaseContext) GetPlayerIDForEntity(entityID uint32) (uint32, bool) {
+	fmt.Printf("[WASM] Mock GetPlayerIDForEntity: %d\n", entityID)
+	return 0, false
+}
+
 func (db *DatabaseContext) GetPlayerCount() (uint64, error) {
 	fmt.Printf("[WASM] Mock GetPlayerCount\n")
 	return 0, nil
 }
 
+func (db *DatabaseContext) GetEntityCount() (uint64, error) {
+	fmt.Printf("[WASM] Mock GetEntityCount\n")
+	return 0, nil
+}
+
 func (db *DatabaseContext) GetFoodCount() (uint64, error) {
 	fmt.Printf("[WASM] Mock GetFoodCount\n")
 	return 0, nil
@@ -167,6 +267,11 @@ func (db *DatabaseContext) InsertFood(food *tables.Food) error {
 	return nil
 }
 
+func (db *DatabaseContext) IsFood(entityID uint32) (bool, error) {
+	fmt.Printf("[WASM] Mock IsFood: %d\n", entityID)
+	return false, nil
+}
+
 func (db *DatabaseContext) InsertLoggedOutPlayer(player *tables.Player) error {
 	fmt.Printf("[WASM] Mock InsertLoggedOutPlayer: %+v\n", player)
 	return nil
@@ -182,6 +287,31 @@ func (db *DatabaseContext) ScheduleReducer(name string, args []byte, schedule ta
 	return nil
 }
 
+func (db *DatabaseContext) ScheduleReducerWithID(name string, args []byte, schedule tables.ScheduleAt) (uint64, error) {
+	fmt.Printf("[WASM] Mock ScheduleReducerWithID: %s\n", name)
+	return 0, nil
+}
+
+func (db *DatabaseContext) CancelScheduledReducer(scheduledID uint64) error {
+	fmt.Printf("[WASM] Mock CancelScheduledReducer: %d\n", scheduledID)
+	return nil
+}
+
+func (db *DatabaseContext) CountScheduledReducers(name string) (int, error) {
+	fmt.Printf("[WASM] Mock CountScheduledReducers: %s\n", name)
+	return 0, nil
+}
+
+func (db *DatabaseContext) GetScheduledTimers() ([]ScheduledTimerInfo, error) {
+	fmt.Printf("[WASM] Mock GetScheduledTimers\n")
+	return []ScheduledTimerInfo{}, nil
+}
+
+// RunDueTimers is a no-op in WASM builds, where SpacetimeDB invokes scheduled reducers itself
+func (db *DatabaseContext) RunDueTimers(now tables.Timestamp) int {
+	return 0
+}
+
 func (db *DatabaseContext) InsertEntity(entity *tables.Entity) error {
 	fmt.Printf("[WASM] Mock InsertEntity: %+v\n", entity)
 	return nil
@@ -192,11 +322,42 @@ func (db *DatabaseContext) DeleteEntity(entityID uint32) error {
 	return nil
 }
 
+func (db *DatabaseContext) DestroyEntity(entityID uint32) error {
+	fmt.Printf("[WASM] Mock DestroyEntity: %d\n", entityID)
+	return nil
+}
+
+func (db *DatabaseContext) DestroyEntities(entityIDs []uint32) error {
+	fmt.Printf("[WASM] Mock DestroyEntities: %v\n", entityIDs)
+	return nil
+}
+
 func (db *DatabaseContext) GetConfig() (*tables.Config, error) {
 	fmt.Printf("[WASM] Mock GetConfig\n")
 	return &tables.Config{ID: 0, WorldSize: 1000}, nil
 }
 
+func (db *DatabaseContext) GetConfigByID(id uint32) (*tables.Config, error) {
+	fmt.Printf("[WASM] Mock GetConfigByID: %d\n", id)
+	return &tables.Config{ID: id, WorldSize: 1000}, nil
+}
+
+// consoleLog is the host's console_log import, which writes a line to the module log
+//
+//go:wasmimport spacetime_10.0 console_log
+func consoleLog(level uint32, target unsafe.Pointer, targetLen uint32, filename unsafe.Pointer, filenameLen uint32,
+	lineNumber uint32, message unsafe.Pointer, messageLen uint32)
+
+// logTarget names this module as the source of its console log lines
+const logTarget = "blackholio"
+
+// hostConsoleLog writes a message to the SpacetimeDB console at the given level
+func hostConsoleLog(level uint8, message string) {
+	consoleLog(uint32(level), unsafe.Pointer(unsafe.StringData(logTarget)), uint32(len(logTarget)), nil, 0,
+		0, unsafe.Pointer(unsafe.StringData(message)), uint32(len(message)))
+}
+
 func init() {
+	SetLogger(hostLogger{write: hostConsoleLog})
 	fmt.Println("[WASM] Simplified WASM implementation initialized")
 }
@@ -0,0 +1,83 @@
+package tables
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DDL Export
+// Renders TableDefinitions as readable CREATE TABLE statements for documentation
+// and for seeding external stores. The output is generated purely from the table
+// metadata and is not meant to be executed against any particular SQL dialect.
+
+// ExportDDL returns CREATE TABLE and CREATE INDEX statements for every table in TableDefinitions,
+// ordered by table name
+func ExportDDL() string {
+	names := make([]string, 0, len(TableDefinitions))
+	for name := range TableDefinitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for i, name := range names {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(TableDDL(TableDefinitions[name]))
+	}
+	return sb.String()
+}
+
+// TableDDL returns the CREATE TABLE statement for a single table followed by its indexes
+func TableDDL(table TableInfo) string {
+	var sb strings.Builder
+
+	visibility := "private"
+	if table.PublicRead {
+		visibility = "public"
+	}
+	fmt.Fprintf(&sb, "-- %s table\n", visibility)
+	fmt.Fprintf(&sb, "CREATE TABLE %s (\n", table.Name)
+	for i, col := range table.Columns {
+		fmt.Fprintf(&sb, "    %s", columnDDL(col))
+		if i < len(table.Columns)-1 {
+			sb.WriteString(",")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(");\n")
+
+	for _, idx := range table.Indexes {
+		unique := ""
+		if idx.Unique {
+			unique = "UNIQUE "
+		}
+		fmt.Fprintf(&sb, "CREATE %sINDEX %s ON %s USING %s (%s);\n",
+			unique, idx.Name, table.Name, idx.Type, strings.Join(idx.Columns, ", "))
+	}
+
+	return sb.String()
+}
+
+// columnDDL renders a column definition with its constraints
+func columnDDL(col Column) string {
+	parts := []string{col.Name, col.Type}
+	if col.PrimaryKey {
+		parts = append(parts, "PRIMARY KEY")
+	}
+	if col.AutoInc {
+		parts = append(parts, "AUTO_INCREMENT")
+	}
+	if col.Unique {
+		parts = append(parts, "UNIQUE")
+	}
+	if col.NotNull {
+		parts = append(parts, "NOT NULL")
+	}
+	if col.DefaultValue != "" {
+		parts = append(parts, "DEFAULT "+col.DefaultValue)
+	}
+	return strings.Join(parts, " ")
+}
@@ -19,6 +19,9 @@ func CreateBlackholioSchema() []*schema.TableInfo {
 	configTable.Columns = []schema.Column{
 		schema.NewPrimaryKeyColumn("id", schema.TypeU32),
 		schema.NewColumn("world_size", schema.TypeU64),
+		schema.NewColumn("rng_seed", schema.TypeU64),
+		schema.NewColumn("rng_counter", schema.TypeU64),
+		schema.NewColumn("admin_identity", schema.TypeIdentity),
 	}
 	tables = append(tables, configTable)
 
@@ -29,6 +32,8 @@ func CreateBlackholioSchema() []*schema.TableInfo {
 		schema.NewAutoIncColumn("entity_id", schema.TypeU32),
 		schema.NewColumn("position", "DbVector2"), // Custom type
 		schema.NewColumn("mass", schema.TypeU32),
+		schema.NewColumn("kind", schema.TypeU8),
+		schema.NewColumn("created_at", schema.TypeTimestamp),
 	}
 	tables = append(tables, entityTable)
 
@@ -41,6 +46,10 @@ func CreateBlackholioSchema() []*schema.TableInfo {
 		schema.NewColumn("direction", "DbVector2"), // Custom type
 		schema.NewColumn("speed", schema.TypeF32),
 		schema.NewColumn("last_split_time", schema.TypeTimestamp),
+		schema.NewColumn("spawned_at", schema.TypeTimestamp),
+		schema.NewColumn("protected_until", schema.TypeTimestamp),
+		schema.NewColumn("color", schema.TypeU32),
+		schema.NewColumn("last_moved_at", schema.TypeTimestamp),
 	}
 	circleTable.Indexes = []schema.Index{
 		schema.NewBTreeIndex("idx_player_id", []string{"player_id"}),
@@ -60,6 +69,12 @@ func CreateBlackholioSchema() []*schema.TableInfo {
 			NotNull: true,
 		},
 		schema.NewColumn("name", schema.TypeString),
+		schema.NewColumn("last_seen", schema.TypeTimestamp),
+		schema.NewColumn("deaths", schema.TypeU32),
+		schema.NewColumn("is_dead", schema.TypeBool),
+		schema.NewColumn("team_id", schema.TypeU32),
+		schema.NewColumn("recombine_attempts", schema.TypeU32),
+		schema.NewColumn("recombine_retry_at", schema.TypeTimestamp),
 	}
 	tables = append(tables, playerTable)
 
@@ -76,6 +91,12 @@ func CreateBlackholioSchema() []*schema.TableInfo {
 			NotNull: true,
 		},
 		schema.NewColumn("name", schema.TypeString),
+		schema.NewColumn("last_seen", schema.TypeTimestamp),
+		schema.NewColumn("deaths", schema.TypeU32),
+		schema.NewColumn("is_dead", schema.TypeBool),
+		schema.NewColumn("team_id", schema.TypeU32),
+		schema.NewColumn("recombine_attempts", schema.TypeU32),
+		schema.NewColumn("recombine_retry_at", schema.TypeTimestamp),
 	}
 	tables = append(tables, loggedOutPlayerTable)
 
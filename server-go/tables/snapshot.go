@@ -0,0 +1,93 @@
+package tables
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Game State Snapshots
+// A snapshot captures the contents of the game tables so a world can be saved
+// and restored later. The format is versioned so that snapshots written by older
+// builds can be migrated forward as the table layout evolves.
+
+// CurrentSnapshotSchemaVersion is the schema version written by NewGameSnapshot
+const CurrentSnapshotSchemaVersion = 1
+
+// SupportedSnapshotSchemaVersions lists the schema versions RestoreGameState accepts
+var SupportedSnapshotSchemaVersions = []int{CurrentSnapshotSchemaVersion}
+
+// GameSnapshot represents the serialized state of all game tables
+type GameSnapshot struct {
+	SchemaVersion    int       `json:"schema_version"`
+	Timestamp        Timestamp `json:"timestamp"`
+	Config           *Config   `json:"config,omitempty"`
+	Entities         []*Entity `json:"entities"`
+	Circles          []*Circle `json:"circles"`
+	Players          []*Player `json:"players"`
+	LoggedOutPlayers []*Player `json:"logged_out_players"`
+	Food             []*Food   `json:"food"`
+}
+
+// NewGameSnapshot creates an empty snapshot at the current schema version
+func NewGameSnapshot(timestamp Timestamp) *GameSnapshot {
+	return &GameSnapshot{
+		SchemaVersion:    CurrentSnapshotSchemaVersion,
+		Timestamp:        timestamp,
+		Entities:         []*Entity{},
+		Circles:          []*Circle{},
+		Players:          []*Player{},
+		LoggedOutPlayers: []*Player{},
+		Food:             []*Food{},
+	}
+}
+
+// Marshal serializes the snapshot to JSON
+func (s *GameSnapshot) Marshal() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+// RestoreGameState decodes a serialized snapshot, migrating it to the current
+// schema version if necessary. Snapshots with an unsupported version are rejected.
+func RestoreGameState(data []byte) (*GameSnapshot, error) {
+	var snapshot GameSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal game snapshot: %w", err)
+	}
+
+	if snapshot.SchemaVersion == CurrentSnapshotSchemaVersion {
+		return &snapshot, nil
+	}
+
+	if !IsSupportedSnapshotSchemaVersion(snapshot.SchemaVersion) {
+		return nil, fmt.Errorf("unsupported snapshot schema version %d (supported versions: %v)",
+			snapshot.SchemaVersion, SupportedSnapshotSchemaVersions)
+	}
+
+	return MigrateSnapshot(&snapshot)
+}
+
+// MigrateSnapshot upgrades a snapshot from an older schema version to the current one.
+// No older versions exist yet; new migration steps should be added here as the
+// snapshot layout changes.
+func MigrateSnapshot(old *GameSnapshot) (*GameSnapshot, error) {
+	if old == nil {
+		return nil, fmt.Errorf("cannot migrate nil snapshot")
+	}
+
+	if old.SchemaVersion == CurrentSnapshotSchemaVersion {
+		return old, nil
+	}
+
+	return nil, fmt.Errorf("no migration path from snapshot schema version %d to %d (supported versions: %v)",
+		old.SchemaVersion, CurrentSnapshotSchemaVersion, SupportedSnapshotSchemaVersions)
+}
+
+// IsSupportedSnapshotSchemaVersion returns true if RestoreGameState accepts the given version
+func IsSupportedSnapshotSchemaVersion(version int) bool {
+	for _, supported := range SupportedSnapshotSchemaVersions {
+		if supported == version {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package tables
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/clockworklabs/Blackholio/server-go/types"
+)
+
+func TestGameSnapshot(t *testing.T) {
+	t.Run("NewGameSnapshot", func(t *testing.T) {
+		snapshot := NewGameSnapshot(NewTimestamp(1000))
+		if snapshot.SchemaVersion != CurrentSnapshotSchemaVersion {
+			t.Errorf("Expected schema version %d, got %d", CurrentSnapshotSchemaVersion, snapshot.SchemaVersion)
+		}
+		if snapshot.Timestamp.Microseconds != 1000 {
+			t.Errorf("Expected timestamp 1000, got %d", snapshot.Timestamp.Microseconds)
+		}
+	})
+
+	t.Run("RestoreCurrentVersion", func(t *testing.T) {
+		snapshot := NewGameSnapshot(NewTimestamp(1000))
+		snapshot.Config = NewConfig(0, 1000)
+		snapshot.Entities = append(snapshot.Entities, NewEntity(1, types.NewDbVector2(10, 20), 15))
+		snapshot.Food = append(snapshot.Food, NewFood(1))
+
+		data, err := snapshot.Marshal()
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+
+		restored, err := RestoreGameState(data)
+		if err != nil {
+			t.Fatalf("Restoring current version should succeed: %v", err)
+		}
+
+		if restored.SchemaVersion != CurrentSnapshotSchemaVersion {
+			t.Errorf("Expected schema version %d, got %d", CurrentSnapshotSchemaVersion, restored.SchemaVersion)
+		}
+		if restored.Config == nil || restored.Config.WorldSize != 1000 {
+			t.Errorf("Config not restored: %+v", restored.Config)
+		}
+		if len(restored.Entities) != 1 || restored.Entities[0].Mass != 15 {
+			t.Errorf("Entities not restored: %+v", restored.Entities)
+		}
+		if len(restored.Food) != 1 || restored.Food[0].EntityID != 1 {
+			t.Errorf("Food not restored: %+v", restored.Food)
+		}
+	})
+
+	t.Run("RejectFutureVersion", func(t *testing.T) {
+		snapshot := NewGameSnapshot(NewTimestamp(1000))
+		snapshot.SchemaVersion = 9999
+
+		data, err := json.Marshal(snapshot)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+
+		_, err = RestoreGameState(data)
+		if err == nil {
+			t.Fatal("Restoring a future schema version should fail")
+		}
+		if !strings.Contains(err.Error(), "9999") || !strings.Contains(err.Error(), "supported versions") {
+			t.Errorf("Error should name the version and list supported versions: %v", err)
+		}
+	})
+
+	t.Run("RejectMissingVersion", func(t *testing.T) {
+		if _, err := RestoreGameState([]byte(`{"entities":[]}`)); err == nil {
+			t.Error("Restoring a snapshot without a schema version should fail")
+		}
+	})
+
+	t.Run("RejectInvalidJSON", func(t *testing.T) {
+		if _, err := RestoreGameState([]byte("not json")); err == nil {
+			t.Error("Restoring invalid JSON should fail")
+		}
+	})
+
+	t.Run("MigrateSnapshot", func(t *testing.T) {
+		current := NewGameSnapshot(NewTimestamp(1000))
+		migrated, err := MigrateSnapshot(current)
+		if err != nil {
+			t.Errorf("Migrating a current snapshot should be a no-op: %v", err)
+		}
+		if migrated != current {
+			t.Error("Migrating a current snapshot should return it unchanged")
+		}
+
+		if _, err := MigrateSnapshot(nil); err == nil {
+			t.Error("Migrating a nil snapshot should fail")
+		}
+
+		old := &GameSnapshot{SchemaVersion: 0}
+		if _, err := MigrateSnapshot(old); err == nil {
+			t.Error("Migrating an unknown old version should fail until a migration exists")
+		}
+	})
+}
@@ -1,8 +1,10 @@
 package tables
 
 import (
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/clockworklabs/Blackholio/server-go/types"
@@ -16,33 +18,97 @@ import (
 type Config struct {
 	ID        uint32 `json:"id" spacetimedb:"primary_key" bsatn:"0"`
 	WorldSize uint64 `json:"world_size" bsatn:"1"`
+
+	// RngSeed seeds every reducer's RNG (0 = seed from the reducer timestamp instead);
+	// RngCounter counts the RNGs derived from it so far so each call gets its own stream
+	RngSeed    uint64 `json:"rng_seed" bsatn:"2"`
+	RngCounter uint64 `json:"rng_counter" bsatn:"3"`
+
+	// AdminIdentity may call the admin-gated reducers; Init records its caller here
+	AdminIdentity Identity `json:"admin_identity" bsatn:"4"`
 }
 
+// DefaultArenaID is the Config id of the original single arena.
+// Each additional arena is described by its own Config row; entities and players
+// are not yet partitioned by arena and always belong to the default arena.
+const DefaultArenaID uint32 = 0
+
 // Entity represents a game entity (player circles, food, etc.)
 // Matches: Rust Entity struct and C# Entity struct
 type Entity struct {
 	EntityID uint32          `json:"entity_id" spacetimedb:"primary_key,auto_inc" bsatn:"0"`
 	Position types.DbVector2 `json:"position" bsatn:"1"`
 	Mass     uint32          `json:"mass" bsatn:"2"`
+	Kind     EntityKind      `json:"kind" bsatn:"3"`
+
+	// CreatedAt is when the entity spawned (zero for rows written before it existed)
+	CreatedAt Timestamp `json:"created_at" bsatn:"4"`
+}
+
+// EntityKind records what an entity row represents, so callers don't have to
+// probe the circle and food tables to find out
+type EntityKind uint8
+
+const (
+	// KindUnknown is the zero value, used by rows written before Kind existed
+	KindUnknown EntityKind = iota
+
+	// KindCircle marks an entity backed by a row in the circle table
+	KindCircle
+
+	// KindFood marks an entity backed by a row in the food table
+	KindFood
+)
+
+// String returns the lowercase name of the entity kind
+func (k EntityKind) String() string {
+	switch k {
+	case KindCircle:
+		return "circle"
+	case KindFood:
+		return "food"
+	default:
+		return "unknown"
+	}
 }
 
 // Circle represents a player circle entity
 // Matches: Rust Circle struct and C# Circle struct
 type Circle struct {
-	EntityID      uint32          `json:"entity_id" spacetimedb:"primary_key" bsatn:"0"`
-	PlayerID      uint32          `json:"player_id" spacetimedb:"index:btree" bsatn:"1"`
-	Direction     types.DbVector2 `json:"direction" bsatn:"2"`
-	Speed         float32         `json:"speed" bsatn:"3"`
-	LastSplitTime Timestamp       `json:"last_split_time" bsatn:"4"`
+	EntityID       uint32          `json:"entity_id" spacetimedb:"primary_key" bsatn:"0"`
+	PlayerID       uint32          `json:"player_id" spacetimedb:"index:btree" bsatn:"1"`
+	Direction      types.DbVector2 `json:"direction" bsatn:"2"`
+	Speed          float32         `json:"speed" bsatn:"3"`
+	LastSplitTime  Timestamp       `json:"last_split_time" bsatn:"4"`
+	SpawnedAt      Timestamp       `json:"spawned_at" bsatn:"5"`
+	ProtectedUntil Timestamp       `json:"protected_until" bsatn:"6"`
+
+	// Color is the circle's RGBA color packed as 0xRRGGBBAA
+	Color uint32 `json:"color" bsatn:"7"`
+
+	// LastMovedAt is when the circle last changed position, for the camping penalty
+	LastMovedAt Timestamp `json:"last_moved_at" bsatn:"8"`
 }
 
 // Player represents a player in the game
 // Matches: Rust Player struct and C# Player struct
 // Note: This struct is used for both "player" and "logged_out_player" tables
 type Player struct {
-	Identity Identity `json:"identity" spacetimedb:"primary_key" bsatn:"0"`
-	PlayerID uint32   `json:"player_id" spacetimedb:"unique,auto_inc" bsatn:"1"`
-	Name     string   `json:"name" bsatn:"2"`
+	Identity Identity  `json:"identity" spacetimedb:"primary_key" bsatn:"0"`
+	PlayerID uint32    `json:"player_id" spacetimedb:"unique,auto_inc" bsatn:"1"`
+	Name     string    `json:"name" bsatn:"2"`
+	LastSeen Timestamp `json:"last_seen" bsatn:"3"`
+	Deaths   uint32    `json:"deaths" bsatn:"4"`
+	IsDead   bool      `json:"is_dead" bsatn:"5"`
+
+	// TeamID groups players into a team; 0 means the player is on no team
+	TeamID uint32 `json:"team_id" bsatn:"6"`
+
+	// RecombineAttempts counts recombines in a row that left circles too far apart to merge
+	RecombineAttempts uint32 `json:"recombine_attempts" bsatn:"7"`
+
+	// RecombineRetryAt is when the pending recombine retry is due; a time in the past means none is pending
+	RecombineRetryAt Timestamp `json:"recombine_retry_at" bsatn:"8"`
 }
 
 // Food represents a food entity in the game
@@ -318,6 +384,9 @@ var TableDefinitions = map[string]TableInfo{
 		Columns: []Column{
 			{Name: "id", Type: "uint32", PrimaryKey: true},
 			{Name: "world_size", Type: "uint64"},
+			{Name: "rng_seed", Type: "uint64"},
+			{Name: "rng_counter", Type: "uint64"},
+			{Name: "admin_identity", Type: "Identity"},
 		},
 	},
 	"entity": {
@@ -327,6 +396,8 @@ var TableDefinitions = map[string]TableInfo{
 			{Name: "entity_id", Type: "uint32", PrimaryKey: true, AutoInc: true},
 			{Name: "position", Type: "DbVector2"},
 			{Name: "mass", Type: "uint32"},
+			{Name: "kind", Type: "uint8"},
+			{Name: "created_at", Type: "Timestamp"},
 		},
 	},
 	"circle": {
@@ -338,6 +409,10 @@ var TableDefinitions = map[string]TableInfo{
 			{Name: "direction", Type: "DbVector2"},
 			{Name: "speed", Type: "float32"},
 			{Name: "last_split_time", Type: "Timestamp"},
+			{Name: "spawned_at", Type: "Timestamp"},
+			{Name: "protected_until", Type: "Timestamp"},
+			{Name: "color", Type: "uint32"},
+			{Name: "last_moved_at", Type: "Timestamp"},
 		},
 		Indexes: []Index{
 			{Name: "player_id", Type: "btree", Columns: []string{"player_id"}},
@@ -350,6 +425,12 @@ var TableDefinitions = map[string]TableInfo{
 			{Name: "identity", Type: "Identity", PrimaryKey: true},
 			{Name: "player_id", Type: "uint32", Unique: true, AutoInc: true},
 			{Name: "name", Type: "string"},
+			{Name: "last_seen", Type: "Timestamp"},
+			{Name: "deaths", Type: "uint32"},
+			{Name: "is_dead", Type: "bool"},
+			{Name: "team_id", Type: "uint32"},
+			{Name: "recombine_attempts", Type: "uint32"},
+			{Name: "recombine_retry_at", Type: "Timestamp"},
 		},
 	},
 	"logged_out_player": {
@@ -359,6 +440,12 @@ var TableDefinitions = map[string]TableInfo{
 			{Name: "identity", Type: "Identity", PrimaryKey: true},
 			{Name: "player_id", Type: "uint32", Unique: true, AutoInc: true},
 			{Name: "name", Type: "string"},
+			{Name: "last_seen", Type: "Timestamp"},
+			{Name: "deaths", Type: "uint32"},
+			{Name: "is_dead", Type: "bool"},
+			{Name: "team_id", Type: "uint32"},
+			{Name: "recombine_attempts", Type: "uint32"},
+			{Name: "recombine_retry_at", Type: "Timestamp"},
 		},
 	},
 	"food": {
@@ -439,6 +526,118 @@ func (i *Identity) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// BSATN Serialization
+// Rows are BSATN product types: each field is encoded in bsatn tag order with no
+// padding or length prefix, primitives as little-endian and nested products inline.
+// The leading fields of Entity and Circle match the Rust server's rows and encode the
+// same way; the columns only the Go server has follow them, so whole rows differ.
+
+const (
+	timestampBSATNSize = 8
+	vectorBSATNSize    = 8
+	entityBSATNSize    = 4 + vectorBSATNSize + 4 + 1 + timestampBSATNSize
+	circleBSATNSize    = 4 + 4 + vectorBSATNSize + 4 + 3*timestampBSATNSize + 4 + timestampBSATNSize
+)
+
+// MarshalBSATN encodes the timestamp as its microseconds since the Unix epoch
+func (t Timestamp) MarshalBSATN() ([]byte, error) {
+	return binary.LittleEndian.AppendUint64(make([]byte, 0, timestampBSATNSize), t.Microseconds), nil
+}
+
+// UnmarshalBSATN decodes a timestamp from SpacetimeDB's BSATN format
+func (t *Timestamp) UnmarshalBSATN(data []byte) error {
+	if len(data) != timestampBSATNSize {
+		return fmt.Errorf("invalid BSATN length for Timestamp: expected %d bytes, got %d", timestampBSATNSize, len(data))
+	}
+	t.Microseconds = binary.LittleEndian.Uint64(data)
+	return nil
+}
+
+// MarshalBSATN encodes the entity row in SpacetimeDB's BSATN format
+func (e *Entity) MarshalBSATN() ([]byte, error) {
+	position, err := e.Position.MarshalBSATN()
+	if err != nil {
+		return nil, err
+	}
+	createdAt, err := e.CreatedAt.MarshalBSATN()
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, 0, entityBSATNSize)
+	data = binary.LittleEndian.AppendUint32(data, e.EntityID)
+	data = append(data, position...)
+	data = binary.LittleEndian.AppendUint32(data, e.Mass)
+	data = append(data, byte(e.Kind))
+	data = append(data, createdAt...)
+	return data, nil
+}
+
+// UnmarshalBSATN decodes an entity row from SpacetimeDB's BSATN format
+func (e *Entity) UnmarshalBSATN(data []byte) error {
+	if len(data) != entityBSATNSize {
+		return fmt.Errorf("invalid BSATN length for Entity: expected %d bytes, got %d", entityBSATNSize, len(data))
+	}
+
+	e.EntityID = binary.LittleEndian.Uint32(data[0:4])
+	if err := e.Position.UnmarshalBSATN(data[4:12]); err != nil {
+		return err
+	}
+	e.Mass = binary.LittleEndian.Uint32(data[12:16])
+	e.Kind = EntityKind(data[16])
+	return e.CreatedAt.UnmarshalBSATN(data[17:25])
+}
+
+// MarshalBSATN encodes the circle row in SpacetimeDB's BSATN format
+func (c *Circle) MarshalBSATN() ([]byte, error) {
+	direction, err := c.Direction.MarshalBSATN()
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, 0, circleBSATNSize)
+	data = binary.LittleEndian.AppendUint32(data, c.EntityID)
+	data = binary.LittleEndian.AppendUint32(data, c.PlayerID)
+	data = append(data, direction...)
+	data = binary.LittleEndian.AppendUint32(data, math.Float32bits(c.Speed))
+	for _, timestamp := range []Timestamp{c.LastSplitTime, c.SpawnedAt, c.ProtectedUntil} {
+		encoded, err := timestamp.MarshalBSATN()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, encoded...)
+	}
+	data = binary.LittleEndian.AppendUint32(data, c.Color)
+	lastMoved, err := c.LastMovedAt.MarshalBSATN()
+	if err != nil {
+		return nil, err
+	}
+	data = append(data, lastMoved...)
+	return data, nil
+}
+
+// UnmarshalBSATN decodes a circle row from SpacetimeDB's BSATN format
+func (c *Circle) UnmarshalBSATN(data []byte) error {
+	if len(data) != circleBSATNSize {
+		return fmt.Errorf("invalid BSATN length for Circle: expected %d bytes, got %d", circleBSATNSize, len(data))
+	}
+
+	c.EntityID = binary.LittleEndian.Uint32(data[0:4])
+	c.PlayerID = binary.LittleEndian.Uint32(data[4:8])
+	if err := c.Direction.UnmarshalBSATN(data[8:16]); err != nil {
+		return err
+	}
+	c.Speed = math.Float32frombits(binary.LittleEndian.Uint32(data[16:20]))
+	for i, timestamp := range []*Timestamp{&c.LastSplitTime, &c.SpawnedAt, &c.ProtectedUntil} {
+		offset := 20 + i*timestampBSATNSize
+		if err := timestamp.UnmarshalBSATN(data[offset : offset+timestampBSATNSize]); err != nil {
+			return err
+		}
+	}
+	c.Color = binary.LittleEndian.Uint32(data[44:48])
+	return c.LastMovedAt.UnmarshalBSATN(data[48:56])
+}
+
 // Validation Methods
 
 // Validate validates a Config instance
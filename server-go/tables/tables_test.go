@@ -1,8 +1,10 @@
 package tables
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"math"
+	"strings"
 	"testing"
 	"time"
 
@@ -110,6 +112,32 @@ func TestEntity(t *testing.T) {
 			t.Errorf("Round-trip failed: got %+v, want %+v", decoded, original)
 		}
 	})
+
+	t.Run("Kind", func(t *testing.T) {
+		original := NewEntity(7, types.NewDbVector2(1, 2), 3)
+		original.Kind = KindFood
+
+		data, err := json.Marshal(original)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		var decoded Entity
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unmarshal failed: %v", err)
+		}
+		if decoded.Kind != KindFood {
+			t.Errorf("Kind round-trip failed: got %s, want food", decoded.Kind)
+		}
+
+		// Rows serialized before Kind existed decode as unknown
+		var legacy Entity
+		if err := json.Unmarshal([]byte(`{"entity_id":1,"position":{"x":0,"y":0},"mass":10}`), &legacy); err != nil {
+			t.Fatalf("Unmarshal failed: %v", err)
+		}
+		if legacy.Kind != KindUnknown {
+			t.Errorf("Legacy entity kind = %s, want unknown", legacy.Kind)
+		}
+	})
 }
 
 func TestCircle(t *testing.T) {
@@ -506,6 +534,103 @@ func TestTimerTables(t *testing.T) {
 	})
 }
 
+func TestBSATN(t *testing.T) {
+	// Fixtures for the full Go rows: fields in bsatn tag order, little-endian
+	// primitives, nested DbVector2 and Timestamp inline
+	entity := Entity{EntityID: 42, Position: types.NewDbVector2(1, 0), Mass: 100, Kind: KindFood, CreatedAt: NewTimestamp(1_000_000)}
+	const entityHex = "2a000000" + "0000803f00000000" + "64000000" + "02" + "40420f0000000000"
+
+	circle := Circle{
+		EntityID:      7,
+		PlayerID:      3,
+		Direction:     types.NewDbVector2(0, 1),
+		Speed:         10,
+		LastSplitTime: NewTimestamp(1_000_000),
+		SpawnedAt:     NewTimestamp(2_000_000),
+		Color:         0x3366ccff,
+		LastMovedAt:   NewTimestamp(3_000_000),
+	}
+	const circleHex = "07000000" + "03000000" + "000000000000803f" + "00002041" +
+		"40420f0000000000" + "80841e0000000000" + "0000000000000000" + "ffcc6633" + "c0c62d0000000000"
+
+	t.Run("Entity fixture", func(t *testing.T) {
+		data, err := entity.MarshalBSATN()
+		if err != nil {
+			t.Fatalf("MarshalBSATN failed: %v", err)
+		}
+		if got := hex.EncodeToString(data); got != entityHex {
+			t.Errorf("MarshalBSATN() = %s, want %s", got, entityHex)
+		}
+
+		var decoded Entity
+		if err := decoded.UnmarshalBSATN(data); err != nil {
+			t.Fatalf("UnmarshalBSATN failed: %v", err)
+		}
+		if decoded != entity {
+			t.Errorf("Round trip = %+v, want %+v", decoded, entity)
+		}
+	})
+
+	t.Run("Circle fixture", func(t *testing.T) {
+		data, err := circle.MarshalBSATN()
+		if err != nil {
+			t.Fatalf("MarshalBSATN failed: %v", err)
+		}
+		if got := hex.EncodeToString(data); got != circleHex {
+			t.Errorf("MarshalBSATN() = %s, want %s", got, circleHex)
+		}
+
+		var decoded Circle
+		if err := decoded.UnmarshalBSATN(data); err != nil {
+			t.Fatalf("UnmarshalBSATN failed: %v", err)
+		}
+		if decoded != circle {
+			t.Errorf("Round trip = %+v, want %+v", decoded, circle)
+		}
+	})
+
+	t.Run("Rust layout prefix", func(t *testing.T) {
+		// Rows encoded from server-rust/src/lib.rs, which only has these fields:
+		// Entity { entity_id: u32, position: DbVector2, mass: u32 }
+		// Circle { entity_id: u32, player_id: u32, direction: DbVector2, speed: f32, last_split_time: Timestamp }
+		const rustEntityHex = "2a000000" + "0000803f00000000" + "64000000"
+		const rustCircleHex = "07000000" + "03000000" + "000000000000803f" + "00002041" + "40420f0000000000"
+
+		entityData, _ := entity.MarshalBSATN()
+		if got := hex.EncodeToString(entityData[:len(rustEntityHex)/2]); got != rustEntityHex {
+			t.Errorf("Entity fields shared with Rust encode as %s, want %s", got, rustEntityHex)
+		}
+		circleData, _ := circle.MarshalBSATN()
+		if got := hex.EncodeToString(circleData[:len(rustCircleHex)/2]); got != rustCircleHex {
+			t.Errorf("Circle fields shared with Rust encode as %s, want %s", got, rustCircleHex)
+		}
+	})
+
+	t.Run("Timestamp round trip", func(t *testing.T) {
+		original := NewTimestampFromTime(time.Now())
+		data, _ := original.MarshalBSATN()
+		var decoded Timestamp
+		if err := decoded.UnmarshalBSATN(data); err != nil || decoded != original {
+			t.Errorf("Round trip = %v (%v), want %v", decoded, err, original)
+		}
+	})
+
+	t.Run("Invalid length", func(t *testing.T) {
+		var e Entity
+		if err := e.UnmarshalBSATN(make([]byte, 24)); err == nil {
+			t.Error("Entity UnmarshalBSATN should fail for short input")
+		}
+		var c Circle
+		if err := c.UnmarshalBSATN(make([]byte, 49)); err == nil {
+			t.Error("Circle UnmarshalBSATN should fail for long input")
+		}
+		var ts Timestamp
+		if err := ts.UnmarshalBSATN([]byte{1}); err == nil {
+			t.Error("Timestamp UnmarshalBSATN should fail for short input")
+		}
+	})
+}
+
 func TestTableDefinitions(t *testing.T) {
 	t.Run("ConfigTable", func(t *testing.T) {
 		def, exists := TableDefinitions["config"]
@@ -519,8 +644,8 @@ func TestTableDefinitions(t *testing.T) {
 		if !def.PublicRead {
 			t.Error("Config table should be public")
 		}
-		if len(def.Columns) != 2 {
-			t.Errorf("Expected 2 columns, got %d", len(def.Columns))
+		if len(def.Columns) != 5 {
+			t.Errorf("Expected 5 columns, got %d", len(def.Columns))
 		}
 
 		// Check primary key
@@ -572,6 +697,44 @@ func TestTableDefinitions(t *testing.T) {
 	})
 }
 
+func TestExportDDL(t *testing.T) {
+	ddl := ExportDDL()
+
+	t.Run("CircleTable", func(t *testing.T) {
+		circleDDL := TableDDL(TableDefinitions["circle"])
+		if !strings.Contains(circleDDL, "CREATE TABLE circle (") {
+			t.Errorf("Circle DDL should create the circle table:\n%s", circleDDL)
+		}
+		if !strings.Contains(circleDDL, "entity_id uint32 PRIMARY KEY") {
+			t.Errorf("Circle DDL should declare entity_id as primary key:\n%s", circleDDL)
+		}
+		if !strings.Contains(circleDDL, "CREATE INDEX player_id ON circle USING btree (player_id);") {
+			t.Errorf("Circle DDL should include the player_id btree index:\n%s", circleDDL)
+		}
+		if !strings.Contains(ddl, circleDDL) {
+			t.Error("ExportDDL should include the circle table")
+		}
+	})
+
+	t.Run("ColumnConstraints", func(t *testing.T) {
+		if !strings.Contains(ddl, "player_id uint32 AUTO_INCREMENT UNIQUE") {
+			t.Error("Player DDL should mark player_id as auto-increment and unique")
+		}
+		if !strings.Contains(ddl, "-- private table\nCREATE TABLE logged_out_player") {
+			t.Error("logged_out_player should be marked private")
+		}
+	})
+
+	t.Run("AllTablesIncluded", func(t *testing.T) {
+		if got := strings.Count(ddl, "CREATE TABLE "); got != len(TableDefinitions) {
+			t.Errorf("Expected %d CREATE TABLE statements, got %d", len(TableDefinitions), got)
+		}
+		if ExportDDL() != ddl {
+			t.Error("ExportDDL output should be deterministic")
+		}
+	})
+}
+
 // Benchmark tests for performance
 func BenchmarkEntityCreation(b *testing.B) {
 	position := types.NewDbVector2(10.0, 20.0)
@@ -1,6 +1,8 @@
 package types
 
 import (
+	"bytes"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"math"
@@ -60,19 +62,81 @@ func (v DbVector2) Normalized() DbVector2 {
 	return v.Div(mag)
 }
 
+// NormalizedWithMagnitude returns the unit vector in the same direction as this vector
+// together with the vector's original magnitude, computing the square root only once.
+// If the vector is zero, returns a zero vector and a magnitude of zero.
+func (v DbVector2) NormalizedWithMagnitude() (DbVector2, float32) {
+	mag := v.Magnitude()
+	if mag == 0 {
+		return Zero(), 0
+	}
+	return v.Div(mag), mag
+}
+
+// Safe arithmetic makes Add, Sub, Mul and Div replace non-finite results with a zero
+// vector and report the operation, to help track down where NaNs enter the physics.
+// It is off by default, leaving the arithmetic fast path a single flag check.
+var (
+	safeArithmetic    bool
+	nonFiniteReporter = printNonFinite
+)
+
+func printNonFinite(message string) { fmt.Printf("[WARN] %s\n", message) }
+
+// SetSafeArithmetic turns safe arithmetic on or off.
+func SetSafeArithmetic(enabled bool) {
+	safeArithmetic = enabled
+}
+
+// SafeArithmetic reports whether safe arithmetic is on.
+func SafeArithmetic() bool {
+	return safeArithmetic
+}
+
+// SetNonFiniteReporter sets where safe arithmetic reports sanitized results;
+// nil restores printing to standard output.
+func SetNonFiniteReporter(report func(message string)) {
+	if report == nil {
+		report = printNonFinite
+	}
+	nonFiniteReporter = report
+}
+
+// sanitize returns result, or a zero vector after reporting op and its operands
+// if result is not finite.
+func sanitize(op string, result DbVector2, operands ...any) DbVector2 {
+	if result.IsFinite() {
+		return result
+	}
+	nonFiniteReporter(fmt.Sprintf("DbVector2.%s produced non-finite %s from %v, using zero", op, result, operands))
+	return Zero()
+}
+
 // Add returns the sum of this vector and another vector.
 func (v DbVector2) Add(other DbVector2) DbVector2 {
-	return DbVector2{X: v.X + other.X, Y: v.Y + other.Y}
+	result := DbVector2{X: v.X + other.X, Y: v.Y + other.Y}
+	if safeArithmetic {
+		return sanitize("Add", result, v, other)
+	}
+	return result
 }
 
 // Sub returns the difference of this vector and another vector.
 func (v DbVector2) Sub(other DbVector2) DbVector2 {
-	return DbVector2{X: v.X - other.X, Y: v.Y - other.Y}
+	result := DbVector2{X: v.X - other.X, Y: v.Y - other.Y}
+	if safeArithmetic {
+		return sanitize("Sub", result, v, other)
+	}
+	return result
 }
 
 // Mul returns this vector multiplied by a scalar.
 func (v DbVector2) Mul(scalar float32) DbVector2 {
-	return DbVector2{X: v.X * scalar, Y: v.Y * scalar}
+	result := DbVector2{X: v.X * scalar, Y: v.Y * scalar}
+	if safeArithmetic {
+		return sanitize("Mul", result, v, scalar)
+	}
+	return result
 }
 
 // Div returns this vector divided by a scalar.
@@ -81,7 +145,11 @@ func (v DbVector2) Div(scalar float32) DbVector2 {
 	if scalar == 0 {
 		return Zero()
 	}
-	return DbVector2{X: v.X / scalar, Y: v.Y / scalar}
+	result := DbVector2{X: v.X / scalar, Y: v.Y / scalar}
+	if safeArithmetic {
+		return sanitize("Div", result, v, scalar)
+	}
+	return result
 }
 
 // Dot returns the dot product of this vector and another vector.
@@ -109,6 +177,24 @@ func (v DbVector2) Angle() float32 {
 	return float32(math.Atan2(float64(v.Y), float64(v.X)))
 }
 
+// AnglePositive returns the angle of this vector in radians in the range [0, 2π).
+// Angles just below 2π that would round up to it in float32 are kept below it.
+func (v DbVector2) AnglePositive() float32 {
+	angle := math.Atan2(float64(v.Y), float64(v.X))
+	if angle < 0 {
+		angle += 2 * math.Pi
+	}
+	if angle == 0 {
+		return 0 // Also turns -0 into 0
+	}
+
+	result := float32(angle)
+	if limit := float32(2 * math.Pi); result >= limit {
+		return math.Nextafter32(limit, 0)
+	}
+	return result
+}
+
 // AngleTo returns the angle between this vector and another vector in radians.
 func (v DbVector2) AngleTo(other DbVector2) float32 {
 	dot := v.Normalized().Dot(other.Normalized())
@@ -155,6 +241,16 @@ func (v DbVector2) IsValid() bool {
 		!math.IsNaN(float64(v.Y)) && !math.IsInf(float64(v.Y), 0)
 }
 
+// IsFinite is an alias for IsValid.
+func (v DbVector2) IsFinite() bool {
+	return v.IsValid()
+}
+
+// IsUnit returns true if the magnitude of the vector is within epsilon of 1.
+func (v DbVector2) IsUnit(epsilon float32) bool {
+	return math.Abs(float64(v.Magnitude())-1) <= float64(epsilon)
+}
+
 // Clamp clamps each component of this vector to the given bounds.
 func (v DbVector2) Clamp(min, max DbVector2) DbVector2 {
 	return DbVector2{
@@ -163,6 +259,32 @@ func (v DbVector2) Clamp(min, max DbVector2) DbVector2 {
 	}
 }
 
+// WithX returns a copy of this vector with the X component replaced.
+func (v DbVector2) WithX(x float32) DbVector2 {
+	return DbVector2{X: x, Y: v.Y}
+}
+
+// WithY returns a copy of this vector with the Y component replaced.
+func (v DbVector2) WithY(y float32) DbVector2 {
+	return DbVector2{X: v.X, Y: y}
+}
+
+// MinComponent returns the smaller of the X and Y components.
+func (v DbVector2) MinComponent() float32 {
+	if v.Y < v.X {
+		return v.Y
+	}
+	return v.X
+}
+
+// MaxComponent returns the larger of the X and Y components.
+func (v DbVector2) MaxComponent() float32 {
+	if v.Y > v.X {
+		return v.Y
+	}
+	return v.X
+}
+
 // ClampMagnitude clamps the magnitude of this vector to the given maximum.
 func (v DbVector2) ClampMagnitude(maxMagnitude float32) DbVector2 {
 	if maxMagnitude < 0 {
@@ -175,6 +297,84 @@ func (v DbVector2) ClampMagnitude(maxMagnitude float32) DbVector2 {
 	return v
 }
 
+// ClampMagnitudeRange scales this vector so its magnitude lies within [minMagnitude, maxMagnitude],
+// keeping its direction. A zero vector has no direction and stays zero; a minimum above
+// the maximum is lowered to it.
+func (v DbVector2) ClampMagnitudeRange(minMagnitude, maxMagnitude float32) DbVector2 {
+	if maxMagnitude < 0 {
+		return Zero()
+	}
+	mag := v.Magnitude()
+	if mag == 0 {
+		return Zero()
+	}
+	minMagnitude = min(max(minMagnitude, 0), maxMagnitude)
+	switch {
+	case mag < minMagnitude:
+		return v.Mul(minMagnitude / mag)
+	case mag > maxMagnitude:
+		return v.Mul(maxMagnitude / mag)
+	}
+	return v
+}
+
+// Floor returns this vector with each component rounded down to a whole number.
+func (v DbVector2) Floor() DbVector2 {
+	return DbVector2{X: float32(math.Floor(float64(v.X))), Y: float32(math.Floor(float64(v.Y)))}
+}
+
+// Ceil returns this vector with each component rounded up to a whole number.
+func (v DbVector2) Ceil() DbVector2 {
+	return DbVector2{X: float32(math.Ceil(float64(v.X))), Y: float32(math.Ceil(float64(v.Y)))}
+}
+
+// Round returns this vector with each component rounded to the nearest whole number,
+// rounding halves to even.
+func (v DbVector2) Round() DbVector2 {
+	return DbVector2{X: float32(math.RoundToEven(float64(v.X))), Y: float32(math.RoundToEven(float64(v.Y)))}
+}
+
+// SnapToAxis returns the unit vector along whichever of the four cardinal directions
+// is nearest to this vector. On an exact diagonal (|X| == |Y|) the horizontal axis wins.
+// The zero vector and non-finite vectors return zero.
+func (v DbVector2) SnapToAxis() DbVector2 {
+	if v.IsZero() || !v.IsFinite() {
+		return Zero()
+	}
+	if abs32(v.X) >= abs32(v.Y) {
+		return DbVector2{X: sign32(v.X), Y: 0}
+	}
+	return DbVector2{X: 0, Y: sign32(v.Y)}
+}
+
+// SnapToAxis8 returns the unit vector along whichever of the four cardinal and four
+// diagonal directions is nearest to this vector. A vector exactly 22.5 degrees from an
+// axis snaps to the diagonal. The zero vector and non-finite vectors return zero.
+func (v DbVector2) SnapToAxis8() DbVector2 {
+	if v.IsZero() || !v.IsFinite() {
+		return Zero()
+	}
+	// tan(22.5°): below this ratio of minor to major component the axis is nearer
+	const axisRatio = math.Sqrt2 - 1
+	ax, ay := abs32(v.X), abs32(v.Y)
+	if min(ax, ay) < axisRatio*max(ax, ay) {
+		return v.SnapToAxis()
+	}
+	return DbVector2{X: sign32(v.X) * math.Sqrt2 / 2, Y: sign32(v.Y) * math.Sqrt2 / 2}
+}
+
+func abs32(f float32) float32 {
+	return float32(math.Abs(float64(f)))
+}
+
+// sign32 returns 1 for positive f and -1 otherwise
+func sign32(f float32) float32 {
+	if f > 0 {
+		return 1
+	}
+	return -1
+}
+
 // String returns a string representation of the vector.
 func (v DbVector2) String() string {
 	return fmt.Sprintf("DbVector2(%.3f, %.3f)", v.X, v.Y)
@@ -202,20 +402,35 @@ func (v DbVector2) MarshalJSON() ([]byte, error) {
 }
 
 // UnmarshalJSON implements JSON decoding for DbVector2.
+// Both the object form {"x": 3, "y": 4} and the array form [3, 4] are accepted.
 func (v *DbVector2) UnmarshalJSON(data []byte) error {
-	// Temporary struct for unmarshaling
-	var temp struct {
-		X float32 `json:"x"`
-		Y float32 `json:"y"`
-	}
-
-	if err := json.Unmarshal(data, &temp); err != nil {
-		return fmt.Errorf("failed to unmarshal DbVector2: %w", err)
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var components []float32
+		if err := json.Unmarshal(trimmed, &components); err != nil {
+			return fmt.Errorf("failed to unmarshal DbVector2: %w", err)
+		}
+		if len(components) != 2 {
+			return fmt.Errorf("failed to unmarshal DbVector2: expected 2 array elements, got %d", len(components))
+		}
+
+		v.X = components[0]
+		v.Y = components[1]
+	} else {
+		// Temporary struct for unmarshaling
+		var temp struct {
+			X float32 `json:"x"`
+			Y float32 `json:"y"`
+		}
+
+		if err := json.Unmarshal(data, &temp); err != nil {
+			return fmt.Errorf("failed to unmarshal DbVector2: %w", err)
+		}
+
+		v.X = temp.X
+		v.Y = temp.Y
 	}
 
-	v.X = temp.X
-	v.Y = temp.Y
-
 	// Validate the unmarshaled data
 	if !v.IsValid() {
 		return fmt.Errorf("unmarshaled DbVector2 contains invalid values: %v", v)
@@ -271,6 +486,30 @@ func (v *DbVector2) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+// BSATN Serialization
+// DbVector2 is a BSATN product type of two f32 fields, which encodes as the
+// little-endian IEEE-754 bits of X followed by Y. This must stay byte-for-byte
+// identical to the Rust and C# servers' DbVector2 encoding.
+
+// MarshalBSATN encodes the vector in SpacetimeDB's BSATN format.
+func (v DbVector2) MarshalBSATN() ([]byte, error) {
+	data := make([]byte, 0, 8)
+	data = binary.LittleEndian.AppendUint32(data, math.Float32bits(v.X))
+	data = binary.LittleEndian.AppendUint32(data, math.Float32bits(v.Y))
+	return data, nil
+}
+
+// UnmarshalBSATN decodes a vector from SpacetimeDB's BSATN format.
+func (v *DbVector2) UnmarshalBSATN(data []byte) error {
+	if len(data) != 8 {
+		return fmt.Errorf("invalid BSATN length for DbVector2: expected 8 bytes, got %d", len(data))
+	}
+
+	v.X = math.Float32frombits(binary.LittleEndian.Uint32(data[0:4]))
+	v.Y = math.Float32frombits(binary.LittleEndian.Uint32(data[4:8]))
+	return nil
+}
+
 // Utility functions for creating common vectors
 
 // FromAngle creates a unit vector from an angle in radians.
@@ -286,6 +525,84 @@ func FromPolar(magnitude, angleRadians float32) DbVector2 {
 	return FromAngle(angleRadians).Mul(magnitude)
 }
 
+// TransformPoints returns a new slice with each point scaled, rotated by the given
+// angle in radians, and then translated by offset.
+func TransformPoints(points []DbVector2, offset DbVector2, angleRadians float32, scale float32) []DbVector2 {
+	result := make([]DbVector2, len(points))
+	copy(result, points)
+	TransformPointsInPlace(result, offset, angleRadians, scale)
+	return result
+}
+
+// TransformPointsInPlace applies the same transform as TransformPoints, overwriting the input slice.
+func TransformPointsInPlace(points []DbVector2, offset DbVector2, angleRadians float32, scale float32) {
+	cos := float32(math.Cos(float64(angleRadians)))
+	sin := float32(math.Sin(float64(angleRadians)))
+	for i, p := range points {
+		x := p.X * scale
+		y := p.Y * scale
+		points[i] = DbVector2{
+			X: x*cos - y*sin + offset.X,
+			Y: x*sin + y*cos + offset.Y,
+		}
+	}
+}
+
+// SumDot returns the sum of the pairwise dot products of two equal-length slices.
+// Slices of different lengths return zero rather than silently truncating.
+func SumDot(a, b []DbVector2) float32 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var sum float32
+	for i := range a {
+		sum += a[i].X*b[i].X + a[i].Y*b[i].Y
+	}
+	return sum
+}
+
+// SumCross returns the sum of the pairwise 2D cross products of two equal-length slices.
+// Slices of different lengths return zero rather than silently truncating.
+func SumCross(a, b []DbVector2) float32 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var sum float32
+	for i := range a {
+		sum += a[i].X*b[i].Y - a[i].Y*b[i].X
+	}
+	return sum
+}
+
+// CompareVectors orders vectors by X and then by Y, returning -1, 0 or 1.
+// NaN components sort after all other values and compare equal to each other,
+// so the result is a total order suitable for sort.Slice and deterministic dedup.
+func CompareVectors(a, b DbVector2) int {
+	if c := compareFloat32(a.X, b.X); c != 0 {
+		return c
+	}
+	return compareFloat32(a.Y, b.Y)
+}
+
+// compareFloat32 compares two floats, placing NaN last
+func compareFloat32(a, b float32) int {
+	aNaN, bNaN := math.IsNaN(float64(a)), math.IsNaN(float64(b))
+	switch {
+	case aNaN && bNaN:
+		return 0
+	case aNaN:
+		return 1
+	case bNaN:
+		return -1
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
 // Min returns a vector with the minimum components of two vectors.
 func Min(a, b DbVector2) DbVector2 {
 	return DbVector2{
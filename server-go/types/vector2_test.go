@@ -1,8 +1,10 @@
 package types
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"math"
+	"sort"
 	"testing"
 )
 
@@ -118,6 +120,42 @@ func TestNormalized(t *testing.T) {
 	}
 }
 
+func TestNormalizedWithMagnitude(t *testing.T) {
+	tests := []struct {
+		name              string
+		vector            DbVector2
+		expectedUnit      DbVector2
+		expectedMagnitude float32
+	}{
+		{"Unit X", DbVector2{5.0, 0.0}, DbVector2{1.0, 0.0}, 5.0},
+		{"3-4-5 Triangle", DbVector2{3.0, 4.0}, DbVector2{0.6, 0.8}, 5.0},
+		{"Negative", DbVector2{-6.0, -8.0}, DbVector2{-0.6, -0.8}, 10.0},
+		{"Zero Vector", DbVector2{0.0, 0.0}, DbVector2{0.0, 0.0}, 0.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unit, magnitude := tt.vector.NormalizedWithMagnitude()
+			if !vectorEqual(unit, tt.expectedUnit) {
+				t.Errorf("NormalizedWithMagnitude() unit = %v, want %v", unit, tt.expectedUnit)
+			}
+			if !floatEqual(magnitude, tt.expectedMagnitude) {
+				t.Errorf("NormalizedWithMagnitude() magnitude = %v, want %v", magnitude, tt.expectedMagnitude)
+			}
+
+			// Unit vector times magnitude should reconstruct the original
+			if !vectorEqual(unit.Mul(magnitude), tt.vector) {
+				t.Errorf("unit * magnitude = %v, want %v", unit.Mul(magnitude), tt.vector)
+			}
+
+			// Should agree with the separate Normalized/Magnitude calls
+			if !vectorEqual(unit, tt.vector.Normalized()) || !floatEqual(magnitude, tt.vector.Magnitude()) {
+				t.Errorf("NormalizedWithMagnitude() disagrees with Normalized()/Magnitude()")
+			}
+		})
+	}
+}
+
 func TestArithmeticOperations(t *testing.T) {
 	v1 := DbVector2{2.0, 3.0}
 	v2 := DbVector2{1.0, 4.0}
@@ -202,6 +240,78 @@ func TestCrossProduct(t *testing.T) {
 	}
 }
 
+func TestCompareVectors(t *testing.T) {
+	nan := float32(math.NaN())
+	vectors := []DbVector2{
+		{2, 1},
+		{nan, 0},
+		{1, 5},
+		{1, nan},
+		{-3, 7},
+		{1, -2},
+		{2, 1},
+	}
+
+	sort.Slice(vectors, func(i, j int) bool {
+		return CompareVectors(vectors[i], vectors[j]) < 0
+	})
+
+	expected := []string{
+		"DbVector2(-3.000, 7.000)",
+		"DbVector2(1.000, -2.000)",
+		"DbVector2(1.000, 5.000)",
+		"DbVector2(1.000, NaN)",
+		"DbVector2(2.000, 1.000)",
+		"DbVector2(2.000, 1.000)",
+		"DbVector2(NaN, 0.000)",
+	}
+	for i, v := range vectors {
+		if v.String() != expected[i] {
+			t.Errorf("sorted[%d] = %s, want %s", i, v.String(), expected[i])
+		}
+	}
+
+	if CompareVectors(DbVector2{1, 2}, DbVector2{1, 2}) != 0 {
+		t.Error("Equal vectors should compare as 0")
+	}
+	if CompareVectors(DbVector2{nan, 1}, DbVector2{nan, 1}) != 0 {
+		t.Error("NaN components should compare equal to each other")
+	}
+	if CompareVectors(DbVector2{1, 2}, DbVector2{1, 3}) != -1 || CompareVectors(DbVector2{1, 3}, DbVector2{1, 2}) != 1 {
+		t.Error("Vectors with equal X should be ordered by Y")
+	}
+}
+
+func TestSumDotAndCross(t *testing.T) {
+	a := []DbVector2{{1, 2}, {3, 4}, {-1, 0}}
+	b := []DbVector2{{5, 6}, {0, 1}, {2, 2}}
+
+	// 1*5+2*6 + 3*0+4*1 + -1*2+0*2 = 17 + 4 - 2
+	if got := SumDot(a, b); !floatEqual(got, 19) {
+		t.Errorf("SumDot() = %f, want 19", got)
+	}
+
+	// 1*6-2*5 + 3*1-4*0 + -1*2-0*2 = -4 + 3 - 2
+	if got := SumCross(a, b); !floatEqual(got, -3) {
+		t.Errorf("SumCross() = %f, want -3", got)
+	}
+
+	if got := SumDot(nil, nil); got != 0 {
+		t.Errorf("SumDot of empty slices = %f, want 0", got)
+	}
+
+	if got := SumDot(a, b[:2]); got != 0 {
+		t.Errorf("SumDot with mismatched lengths = %f, want 0", got)
+	}
+	if got := SumCross(a[:1], b); got != 0 {
+		t.Errorf("SumCross with mismatched lengths = %f, want 0", got)
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() { SumDot(a, b); SumCross(a, b) }); allocs != 0 {
+		t.Errorf("SumDot/SumCross allocated %f times per run", allocs)
+	}
+}
+
 func TestDistance(t *testing.T) {
 	tests := []struct {
 		v1       DbVector2
@@ -244,6 +354,42 @@ func TestAngle(t *testing.T) {
 	}
 }
 
+func TestAnglePositive(t *testing.T) {
+	tests := []struct {
+		name     string
+		vector   DbVector2
+		expected float32
+	}{
+		{"Zero angle", DbVector2{1.0, 0.0}, 0.0},
+		{"First quadrant", DbVector2{1.0, 1.0}, float32(math.Pi / 4)},
+		{"Second quadrant", DbVector2{-1.0, 1.0}, float32(3 * math.Pi / 4)},
+		{"Pi", DbVector2{-1.0, 0.0}, float32(math.Pi)},
+		{"Pi from negative zero", DbVector2{-1.0, float32(math.Copysign(0, -1))}, float32(math.Pi)},
+		{"Third quadrant", DbVector2{-1.0, -1.0}, float32(5 * math.Pi / 4)},
+		{"Fourth quadrant", DbVector2{1.0, -1.0}, float32(7 * math.Pi / 4)},
+		{"Negative zero", DbVector2{1.0, float32(math.Copysign(0, -1))}, 0.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.vector.AnglePositive()
+			if !floatEqual(result, tt.expected) {
+				t.Errorf("AnglePositive() = %f, want %f", result, tt.expected)
+			}
+			if result < 0 || result >= float32(2*math.Pi) {
+				t.Errorf("AnglePositive() = %f, outside [0, 2π)", result)
+			}
+		})
+	}
+
+	t.Run("Just below 2π", func(t *testing.T) {
+		result := DbVector2{1.0, -1e-9}.AnglePositive()
+		if result >= float32(2*math.Pi) || result < float32(2*math.Pi)-1e-5 {
+			t.Errorf("AnglePositive() = %.9f, want just below 2π", result)
+		}
+	})
+}
+
 func TestAngleTo(t *testing.T) {
 	v1 := DbVector2{1.0, 0.0}
 	v2 := DbVector2{0.0, 1.0}
@@ -306,6 +452,43 @@ func TestRotate(t *testing.T) {
 	}
 }
 
+func TestTransformPoints(t *testing.T) {
+	points := []DbVector2{{1, 0}, {0, 2}, {-3, 4}}
+	offset := DbVector2{10, 20}
+	angle := float32(math.Pi / 2)
+	scale := float32(2)
+
+	result := TransformPoints(points, offset, angle, scale)
+	if len(result) != len(points) {
+		t.Fatalf("TransformPoints returned %d points, want %d", len(result), len(points))
+	}
+
+	for i, p := range points {
+		expected := p.Mul(scale).Rotate(angle).Add(offset)
+		if !vectorEqual(result[i], expected) {
+			t.Errorf("TransformPoints()[%d] = %v, want %v", i, result[i], expected)
+		}
+	}
+
+	// A 90 degree rotation maps (1, 0) * 2 to (0, 2) before the offset
+	if !vectorEqual(result[0], DbVector2{10, 22}) {
+		t.Errorf("TransformPoints()[0] = %v, want (10, 22)", result[0])
+	}
+
+	// The input slice must not be modified
+	if !vectorEqual(points[0], DbVector2{1, 0}) {
+		t.Errorf("TransformPoints modified its input: %v", points[0])
+	}
+
+	inPlace := append([]DbVector2(nil), points...)
+	TransformPointsInPlace(inPlace, offset, angle, scale)
+	for i := range inPlace {
+		if !vectorEqual(inPlace[i], result[i]) {
+			t.Errorf("TransformPointsInPlace()[%d] = %v, want %v", i, inPlace[i], result[i])
+		}
+	}
+}
+
 func TestIsZero(t *testing.T) {
 	tests := []struct {
 		vector   DbVector2
@@ -328,6 +511,32 @@ func TestIsZero(t *testing.T) {
 	}
 }
 
+func TestIsUnit(t *testing.T) {
+	tests := []struct {
+		vector   DbVector2
+		expected bool
+	}{
+		{DbVector2{1.0, 0.0}, true},
+		{DbVector2{0.0, -1.0}, true},
+		{DbVector2{0.6, 0.8}, true},
+		{DbVector2{3.0, 4.0}.Normalized(), true},
+		{DbVector2{1.0005, 0.0}, true},
+		{DbVector2{1.01, 0.0}, false},
+		{DbVector2{3.0, 4.0}, false},
+		{DbVector2{0.0, 0.0}, false},
+		{DbVector2{float32(math.NaN()), 0.0}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run("", func(t *testing.T) {
+			result := tt.vector.IsUnit(1e-3)
+			if result != tt.expected {
+				t.Errorf("IsUnit() = %v, want %v for vector %v", result, tt.expected, tt.vector)
+			}
+		})
+	}
+}
+
 func TestIsValid(t *testing.T) {
 	tests := []struct {
 		vector   DbVector2
@@ -364,6 +573,45 @@ func TestClamp(t *testing.T) {
 	}
 }
 
+func TestWithComponents(t *testing.T) {
+	v := DbVector2{3.0, -4.0}
+
+	if result := v.WithX(7.0); !vectorEqual(result, DbVector2{7.0, -4.0}) {
+		t.Errorf("WithX() = %v, want {7 -4}", result)
+	}
+	if result := v.WithY(1.5); !vectorEqual(result, DbVector2{3.0, 1.5}) {
+		t.Errorf("WithY() = %v, want {3 1.5}", result)
+	}
+	if !vectorEqual(v, DbVector2{3.0, -4.0}) {
+		t.Errorf("WithX/WithY should not modify the receiver, got %v", v)
+	}
+}
+
+func TestMinMaxComponent(t *testing.T) {
+	tests := []struct {
+		name     string
+		vector   DbVector2
+		expected [2]float32 // min, max
+	}{
+		{"x smaller", DbVector2{1.0, 2.0}, [2]float32{1.0, 2.0}},
+		{"y smaller", DbVector2{3.0, -1.0}, [2]float32{-1.0, 3.0}},
+		{"equal components", DbVector2{4.0, 4.0}, [2]float32{4.0, 4.0}},
+		{"both negative", DbVector2{-5.0, -2.0}, [2]float32{-5.0, -2.0}},
+		{"zero", DbVector2{0.0, 0.0}, [2]float32{0.0, 0.0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.vector.MinComponent(); got != tt.expected[0] {
+				t.Errorf("MinComponent() = %f, want %f", got, tt.expected[0])
+			}
+			if got := tt.vector.MaxComponent(); got != tt.expected[1] {
+				t.Errorf("MaxComponent() = %f, want %f", got, tt.expected[1])
+			}
+		})
+	}
+}
+
 func TestClampMagnitude(t *testing.T) {
 	tests := []struct {
 		vector      DbVector2
@@ -386,6 +634,147 @@ func TestClampMagnitude(t *testing.T) {
 	}
 }
 
+func TestClampMagnitudeRange(t *testing.T) {
+	tests := []struct {
+		name        string
+		vector      DbVector2
+		min, max    float32
+		expectedMag float32
+	}{
+		{"Below min", DbVector2{3.0, 4.0}, 10.0, 20.0, 10.0},
+		{"In range", DbVector2{3.0, 4.0}, 2.0, 10.0, 5.0},
+		{"Above max", DbVector2{3.0, 4.0}, 1.0, 2.0, 2.0},
+		{"Min above max", DbVector2{3.0, 4.0}, 8.0, 6.0, 6.0},
+		{"Zero vector", DbVector2{0, 0}, 1.0, 2.0, 0.0},
+		{"Negative max", DbVector2{3.0, 4.0}, 0.0, -1.0, 0.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.vector.ClampMagnitudeRange(tt.min, tt.max)
+			if !floatEqual(result.Magnitude(), tt.expectedMag) {
+				t.Errorf("ClampMagnitudeRange(%f, %f) magnitude = %f, want %f", tt.min, tt.max, result.Magnitude(), tt.expectedMag)
+			}
+			if tt.expectedMag > 0 && !result.Normalized().Equal(tt.vector.Normalized()) {
+				t.Errorf("ClampMagnitudeRange changed direction: %v -> %v", tt.vector, result)
+			}
+		})
+	}
+}
+
+func TestSafeArithmetic(t *testing.T) {
+	nan := float32(math.NaN())
+	inf := float32(math.Inf(1))
+	v := DbVector2{1, 2}
+
+	t.Run("Default passes NaN through", func(t *testing.T) {
+		if got := v.Add(DbVector2{nan, 0}); got.IsFinite() {
+			t.Errorf("Add should pass NaN through by default, got %v", got)
+		}
+		if got := v.Mul(inf); got.IsFinite() {
+			t.Errorf("Mul should pass Inf through by default, got %v", got)
+		}
+	})
+
+	t.Run("Safe mode sanitizes", func(t *testing.T) {
+		var reports []string
+		SetSafeArithmetic(true)
+		SetNonFiniteReporter(func(message string) { reports = append(reports, message) })
+		defer func() {
+			SetSafeArithmetic(false)
+			SetNonFiniteReporter(nil)
+		}()
+
+		results := map[string]DbVector2{
+			"Add": v.Add(DbVector2{nan, 0}),
+			"Sub": v.Sub(DbVector2{0, nan}),
+			"Mul": v.Mul(inf),
+			"Div": v.Div(nan),
+		}
+		for op, got := range results {
+			if got != Zero() {
+				t.Errorf("%s should sanitize to zero in safe mode, got %v", op, got)
+			}
+		}
+		if len(reports) != len(results) {
+			t.Errorf("Expected %d reports, got %d: %v", len(results), len(reports), reports)
+		}
+
+		if got := v.Add(DbVector2{1, 1}); got != (DbVector2{2, 3}) {
+			t.Errorf("Finite results should be unchanged in safe mode, got %v", got)
+		}
+		if len(reports) != len(results) {
+			t.Errorf("Finite results should not be reported, got %v", reports)
+		}
+	})
+
+	if !(DbVector2{1, 2}).IsFinite() || (DbVector2{nan, 0}).IsFinite() {
+		t.Error("IsFinite should match IsValid")
+	}
+}
+
+func TestSnapToAxis(t *testing.T) {
+	diag := float32(math.Sqrt2 / 2)
+	tests := []struct {
+		name   string
+		vector DbVector2
+		want4  DbVector2
+		want8  DbVector2
+	}{
+		{"Near right", DbVector2{10, 1}, DbVector2{1, 0}, DbVector2{1, 0}},
+		{"Near up", DbVector2{-1, 10}, DbVector2{0, 1}, DbVector2{0, 1}},
+		{"Near left", DbVector2{-10, -1}, DbVector2{-1, 0}, DbVector2{-1, 0}},
+		{"Near down", DbVector2{1, -10}, DbVector2{0, -1}, DbVector2{0, -1}},
+		{"Diagonal tie goes horizontal", DbVector2{3, 3}, DbVector2{1, 0}, DbVector2{diag, diag}},
+		{"Negative diagonal tie goes horizontal", DbVector2{-3, -3}, DbVector2{-1, 0}, DbVector2{-diag, -diag}},
+		{"Near diagonal", DbVector2{10, -8}, DbVector2{1, 0}, DbVector2{diag, -diag}},
+		{"Just past 22.5 degrees", DbVector2{10, 4.2}, DbVector2{1, 0}, DbVector2{diag, diag}},
+		{"Just under 22.5 degrees", DbVector2{10, 4.1}, DbVector2{1, 0}, DbVector2{1, 0}},
+		{"Zero", Zero(), Zero(), Zero()},
+		{"NaN", DbVector2{float32(math.NaN()), 1}, Zero(), Zero()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.vector.SnapToAxis(); !got.Equal(tt.want4) {
+				t.Errorf("SnapToAxis(%v) = %v, want %v", tt.vector, got, tt.want4)
+			}
+			if got := tt.vector.SnapToAxis8(); !got.Equal(tt.want8) {
+				t.Errorf("SnapToAxis8(%v) = %v, want %v", tt.vector, got, tt.want8)
+			}
+		})
+	}
+}
+
+func TestFloorCeilRound(t *testing.T) {
+	tests := []struct {
+		name                 string
+		vector               DbVector2
+		floor, ceil, rounded DbVector2
+	}{
+		{"Whole", DbVector2{2, -3}, DbVector2{2, -3}, DbVector2{2, -3}, DbVector2{2, -3}},
+		{"Positive halves", DbVector2{0.5, 1.5}, DbVector2{0, 1}, DbVector2{1, 2}, DbVector2{0, 2}},
+		{"More positive halves", DbVector2{2.5, 3.5}, DbVector2{2, 3}, DbVector2{3, 4}, DbVector2{2, 4}},
+		{"Negative halves", DbVector2{-0.5, -1.5}, DbVector2{-1, -2}, DbVector2{0, -1}, DbVector2{0, -2}},
+		{"More negative halves", DbVector2{-2.5, -3.5}, DbVector2{-3, -4}, DbVector2{-2, -3}, DbVector2{-2, -4}},
+		{"Fractions", DbVector2{1.2, -1.7}, DbVector2{1, -2}, DbVector2{2, -1}, DbVector2{1, -2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.vector.Floor(); got != tt.floor {
+				t.Errorf("%v.Floor() = %v, want %v", tt.vector, got, tt.floor)
+			}
+			if got := tt.vector.Ceil(); got != tt.ceil {
+				t.Errorf("%v.Ceil() = %v, want %v", tt.vector, got, tt.ceil)
+			}
+			if got := tt.vector.Round(); got != tt.rounded {
+				t.Errorf("%v.Round() = %v, want %v", tt.vector, got, tt.rounded)
+			}
+		})
+	}
+}
+
 func TestEqual(t *testing.T) {
 	v1 := DbVector2{1.0, 2.0}
 	v2 := DbVector2{1.0, 2.0}
@@ -501,6 +890,48 @@ func TestBinarySerialization(t *testing.T) {
 	}
 }
 
+// bsatnFixtures are the BSATN encodings of DbVector2 produced by the Rust and C# servers,
+// stored as hex so any drift in the Go wire format fails loudly
+var bsatnFixtures = []struct {
+	vector DbVector2
+	hex    string
+}{
+	{DbVector2{0, 0}, "0000000000000000"},
+	{DbVector2{1, 0}, "0000803f00000000"},
+	{DbVector2{3.14, 2.71}, "c3f54840a4702d40"},
+	{DbVector2{-1.5, 1e6}, "0000c0bf00247449"},
+}
+
+func TestBSATNFixtures(t *testing.T) {
+	for _, fixture := range bsatnFixtures {
+		t.Run(fixture.vector.String(), func(t *testing.T) {
+			data, err := fixture.vector.MarshalBSATN()
+			if err != nil {
+				t.Fatalf("MarshalBSATN failed: %v", err)
+			}
+			if got := hex.EncodeToString(data); got != fixture.hex {
+				t.Errorf("MarshalBSATN() = %s, want %s", got, fixture.hex)
+			}
+
+			expected, _ := hex.DecodeString(fixture.hex)
+			var decoded DbVector2
+			if err := decoded.UnmarshalBSATN(expected); err != nil {
+				t.Fatalf("UnmarshalBSATN failed: %v", err)
+			}
+			if decoded != fixture.vector {
+				t.Errorf("UnmarshalBSATN() = %v, want %v", decoded, fixture.vector)
+			}
+		})
+	}
+
+	t.Run("Invalid length", func(t *testing.T) {
+		var v DbVector2
+		if err := v.UnmarshalBSATN([]byte{0, 0, 0}); err == nil {
+			t.Error("UnmarshalBSATN should fail for short input")
+		}
+	})
+}
+
 func TestBinarySerializationEdgeCases(t *testing.T) {
 	var v DbVector2
 
@@ -539,6 +970,37 @@ func TestJSONSerializationEdgeCases(t *testing.T) {
 	if err == nil {
 		t.Error("UnmarshalJSON should fail with NaN values")
 	}
+
+	// Test array forms with the wrong number of elements
+	for _, input := range []string{`[]`, `[1]`, `[1, 2, 3]`, `["a", "b"]`} {
+		if err := v.UnmarshalJSON([]byte(input)); err == nil {
+			t.Errorf("UnmarshalJSON should fail for %s", input)
+		}
+	}
+}
+
+func TestJSONArrayForm(t *testing.T) {
+	var fromArray, fromObject DbVector2
+	if err := json.Unmarshal([]byte(` [3, 4]`), &fromArray); err != nil {
+		t.Fatalf("UnmarshalJSON failed for array form: %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"x":3,"y":4}`), &fromObject); err != nil {
+		t.Fatalf("UnmarshalJSON failed for object form: %v", err)
+	}
+
+	expected := DbVector2{3, 4}
+	if fromArray != expected || fromObject != expected {
+		t.Errorf("Array and object forms should decode to %v: got %v and %v", expected, fromArray, fromObject)
+	}
+
+	// Marshaling keeps the object form
+	data, err := json.Marshal(fromArray)
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+	if string(data) != `{"x":3,"y":4}` {
+		t.Errorf("MarshalJSON should use the object form, got %s", data)
+	}
 }
 
 // Benchmark tests
@@ -556,6 +1018,13 @@ func BenchmarkNormalized(b *testing.B) {
 	}
 }
 
+func BenchmarkNormalizedWithMagnitude(b *testing.B) {
+	v := DbVector2{3.0, 4.0}
+	for i := 0; i < b.N; i++ {
+		_, _ = v.NormalizedWithMagnitude()
+	}
+}
+
 func BenchmarkDotProduct(b *testing.B) {
 	v1 := DbVector2{3.0, 4.0}
 	v2 := DbVector2{1.0, 2.0}
@@ -573,6 +1042,15 @@ func BenchmarkBinarySerialization(b *testing.B) {
 	}
 }
 
+func BenchmarkBSATNSerialization(b *testing.B) {
+	v := DbVector2{3.14, 2.71}
+	for i := 0; i < b.N; i++ {
+		data, _ := v.MarshalBSATN()
+		var decoded DbVector2
+		_ = decoded.UnmarshalBSATN(data)
+	}
+}
+
 func BenchmarkJSONSerialization(b *testing.B) {
 	v := DbVector2{3.14, 2.71}
 	for i := 0; i < b.N; i++ {